@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blang/semver"
 
@@ -28,6 +30,9 @@ import (
 	"github.com/k8s-kanary/kanary/pkg/apis"
 	kanaryConfig "github.com/k8s-kanary/kanary/pkg/config"
 	"github.com/k8s-kanary/kanary/pkg/controller"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies/validation"
+	"github.com/k8s-kanary/kanary/pkg/webhook"
 )
 
 var log = logf.Log.WithName("cmd")
@@ -130,12 +135,40 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup the pool of prometheus clients shared by the KanaryStatefulsets
+	prometheusPoolConfig, err := getPrometheusPoolConfig()
+	if err != nil {
+		log.Error(err, "invalid prometheus client pool configuration")
+		os.Exit(1)
+	}
+	anomalydetector.ConfigurePrometheusPool(prometheusPoolConfig)
+
 	// Setup all Controllers
 	if err := controller.AddToManager(mgr); err != nil {
 		log.Error(err, "")
 		os.Exit(1)
 	}
 
+	// Setup the admission webhook server
+	if certDir := os.Getenv(kanaryConfig.KanaryWebhookCertDirEnvVar); certDir != "" {
+		key, err := webhook.NewApprovalKey(certDir)
+		switch {
+		case os.IsNotExist(err):
+			// the certificate secret is optional, the manual approval decisions are not recorded without webhook
+			log.Info("The admission webhook is disabled, no certificate", "dir", certDir)
+		case err != nil:
+			log.Error(err, "unable to read the admission webhook certificate")
+			os.Exit(1)
+		default:
+			// the manual approval decisions are only considered if they have been signed by the webhook
+			validation.ConfigureApprovalKey(key)
+			if err := mgr.Add(webhook.NewServer(certDir, os.Getenv(kanaryConfig.KanaryWebhookPortEnvVar), key)); err != nil {
+				log.Error(err, "")
+				os.Exit(1)
+			}
+		}
+	}
+
 	log.Info("Starting the Cmd.")
 
 	// Start the Cmd
@@ -144,3 +177,35 @@ func main() {
 		os.Exit(1)
 	}
 }
+
+// getPrometheusPoolConfig returns the settings of the prometheus client pool, the defaults can be overridden by environment variables
+func getPrometheusPoolConfig() (anomalydetector.PrometheusPoolConfig, error) {
+	config := anomalydetector.DefaultPrometheusPoolConfig()
+	var err error
+	if value := os.Getenv(kanaryConfig.KanaryPrometheusQPSEnvVar); value != "" {
+		if config.QPS, err = strconv.ParseFloat(value, 64); err != nil {
+			return config, fmt.Errorf("%s: %v", kanaryConfig.KanaryPrometheusQPSEnvVar, err)
+		}
+	}
+	if value := os.Getenv(kanaryConfig.KanaryPrometheusBurstEnvVar); value != "" {
+		if config.Burst, err = strconv.Atoi(value); err != nil {
+			return config, fmt.Errorf("%s: %v", kanaryConfig.KanaryPrometheusBurstEnvVar, err)
+		}
+	}
+	if value := os.Getenv(kanaryConfig.KanaryPrometheusMaxConcurrencyEnvVar); value != "" {
+		if config.MaxConcurrency, err = strconv.Atoi(value); err != nil {
+			return config, fmt.Errorf("%s: %v", kanaryConfig.KanaryPrometheusMaxConcurrencyEnvVar, err)
+		}
+	}
+	if value := os.Getenv(kanaryConfig.KanaryPrometheusCacheTTLEnvVar); value != "" {
+		if config.CacheTTL, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("%s: %v", kanaryConfig.KanaryPrometheusCacheTTLEnvVar, err)
+		}
+	}
+	if value := os.Getenv(kanaryConfig.KanaryPrometheusQueryTimeoutEnvVar); value != "" {
+		if config.QueryTimeout, err = time.ParseDuration(value); err != nil {
+			return config, fmt.Errorf("%s: %v", kanaryConfig.KanaryPrometheusQueryTimeoutEnvVar, err)
+		}
+	}
+	return config, nil
+}
@@ -14,6 +14,12 @@ import (
 // logic, and the pseudo-defaulting done in v1 conversion.
 const DefaultCPUUtilization = 80
 
+// DefaultSLOBurnRateWindows default windows of the slo validation
+var DefaultSLOBurnRateWindows = []SLOBurnRateWindow{
+	{Short: metav1.Duration{Duration: 5 * time.Minute}, Long: metav1.Duration{Duration: time.Hour}, Factor: 14.4},
+	{Short: metav1.Duration{Duration: 30 * time.Minute}, Long: metav1.Duration{Duration: 6 * time.Hour}, Factor: 6},
+}
+
 // IsDefaultedKanaryStatefulset used to know if a KanaryStatefulset is already defaulted
 // returns true if yes, else no
 func IsDefaultedKanaryStatefulset(kd *KanaryStatefulset) bool {
@@ -105,13 +111,51 @@ func IsDefaultedKanaryStatefulsetSpecValidationList(list *KanaryStatefulsetSpecV
 		}
 	}
 
+	if list.Score != nil {
+		if list.Score.Pass == nil || list.Score.Marginal == nil || list.Score.MarginalAction == "" || list.Score.ExtensionPeriod == nil || list.Score.MaxExtensions == nil {
+			return false
+		}
+	}
+
+	if list.Inconclusive != nil {
+		if list.Inconclusive.Action == "" || list.Inconclusive.ExtensionPeriod == nil || list.Inconclusive.MaxExtensions == nil {
+			return false
+		}
+	}
+
+	if list.MaxValidationPeriod != nil {
+		if list.Confidence == nil || list.Confidence.ExtensionPeriod == nil {
+			return false
+		}
+	}
+
+	if list.EarlySuccess != nil {
+		if list.EarlySuccess.MinElapsed == nil || (list.EarlySuccess.PrometheusService == "" && list.EarlySuccess.URL == "") {
+			return false
+		}
+	}
+
+	if list.Chaos != nil {
+		if list.Chaos.StartDelay == nil {
+			return false
+		}
+		for _, action := range list.Chaos.Actions {
+			if action.PodKill != nil && action.PodKill.ReadyTimeout == nil {
+				return false
+			}
+			if action.HTTPFault != nil && (action.HTTPFault.Percentage == nil || action.HTTPFault.Duration == nil) {
+				return false
+			}
+		}
+	}
+
 	return true
 }
 
 // IsDefaultedKanaryStatefulsetSpecValidation used to know if a KanaryStatefulsetSpecValidation is already defaulted
 // returns true if yes, else no
 func IsDefaultedKanaryStatefulsetSpecValidation(v *KanaryStatefulsetSpecValidation) bool {
-	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil {
+	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil && v.Metrics == nil && v.ResourceUsage == nil && v.Logs == nil && v.Events == nil && v.Job == nil && v.Alerts == nil && v.SLO == nil {
 		return false
 	}
 
@@ -129,6 +173,28 @@ func IsDefaultedKanaryStatefulsetSpecValidation(v *KanaryStatefulsetSpecValidati
 		}
 	}
 
+	if v.Metrics != nil {
+		if !isDefaultedKanaryStatefulsetSpecValidationMetrics(v.Metrics) {
+			return false
+		}
+	}
+
+	if v.Events != nil {
+		if len(v.Events.Reasons) == 0 || v.Events.MaxOccurrences == nil {
+			return false
+		}
+	}
+
+	if v.SLO != nil {
+		if (v.SLO.PrometheusService == "" && v.SLO.URL == "") || v.SLO.PodNameKey == "" || len(v.SLO.Windows) == 0 {
+			return false
+		}
+	}
+
+	if v.OnProviderError != nil && v.OnProviderError.Policy == "" {
+		return false
+	}
+
 	return true
 }
 
@@ -148,9 +214,42 @@ func isDefaultedKanaryStatefulsetSpecValidationPromQL(pq *KanaryStatefulsetSpecV
 	if pq.ValueInRange != nil && !isDefaultedKanaryStatefulsetSpecValidationPromQLValueInRange(pq.ValueInRange) {
 		return false
 	}
+	if pq.RangeQuery != nil && !isDefaultedKanaryStatefulsetSpecValidationPromQLRangeQuery(pq.RangeQuery) {
+		return false
+	}
+	if pq.Trend != nil && (pq.Trend.Horizon == nil || pq.RangeQuery == nil) {
+		return false
+	}
+	for i := range pq.Dependencies {
+		if pq.Dependencies[i].BaselineWindow == nil || pq.Dependencies[i].MaxIncreasePercent == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isDefaultedKanaryStatefulsetSpecValidationMetrics(m *KanaryStatefulsetSpecValidationMetrics) bool {
+	if m.HTTP != nil && m.HTTP.Aggregator == "" {
+		return false
+	}
+	if m.DiscreteValueOutOfList != nil && !isDefaultedKanaryStatefulsetSpecValidationPromQLDiscrete(m.DiscreteValueOutOfList) {
+		return false
+	}
+	if m.ContinuousValueDeviation != nil && !isDefaultedKanaryStatefulsetSpecValidationPromQLContinuous(m.ContinuousValueDeviation) {
+		return false
+	}
+	if m.ValueInRange != nil && !isDefaultedKanaryStatefulsetSpecValidationPromQLValueInRange(m.ValueInRange) {
+		return false
+	}
 
 	return true
 }
+
+func isDefaultedKanaryStatefulsetSpecValidationPromQLRangeQuery(r *PromQLRangeQuery) bool {
+	return r.Window != "" && r.Step != nil && r.Aggregator != ""
+}
+
 func isDefaultedKanaryStatefulsetSpecValidationPromQLValueInRange(c *ValueInRange) bool {
 	return c.Min != nil && c.Max != nil
 }
@@ -265,10 +364,84 @@ func defaultKanaryStatefulsetSpecValidationList(list *KanaryStatefulsetSpecValid
 		defaultKanaryStatefulsetSpecValidation(&value)
 		list.Items[id] = value
 	}
+	if list.Score != nil {
+		defaultKanaryStatefulsetSpecValidationScore(list.Score, list.ValidationPeriod)
+	}
+	if list.Inconclusive != nil {
+		defaultKanaryStatefulsetSpecValidationInconclusive(list.Inconclusive, list.ValidationPeriod)
+	}
+	if list.MaxValidationPeriod != nil {
+		if list.Confidence == nil {
+			list.Confidence = &KanaryStatefulsetSpecValidationConfidence{NoInconclusive: true}
+		}
+		if list.Confidence.ExtensionPeriod == nil {
+			list.Confidence.ExtensionPeriod = &metav1.Duration{Duration: 5 * time.Minute}
+		}
+	}
+	if list.EarlySuccess != nil {
+		if list.EarlySuccess.MinElapsed == nil {
+			list.EarlySuccess.MinElapsed = &metav1.Duration{Duration: 10 * time.Minute}
+		}
+		if list.EarlySuccess.PrometheusService == "" && list.EarlySuccess.URL == "" {
+			list.EarlySuccess.PrometheusService = "prometheus:9090"
+		}
+	}
+	if list.Chaos != nil {
+		defaultKanaryStatefulsetSpecValidationChaos(list.Chaos)
+	}
+}
+
+func defaultKanaryStatefulsetSpecValidationChaos(c *KanaryStatefulsetSpecValidationChaos) {
+	if c.StartDelay == nil {
+		c.StartDelay = &metav1.Duration{}
+	}
+	for i := range c.Actions {
+		if podKill := c.Actions[i].PodKill; podKill != nil && podKill.ReadyTimeout == nil {
+			podKill.ReadyTimeout = &metav1.Duration{Duration: 2 * time.Minute}
+		}
+		if fault := c.Actions[i].HTTPFault; fault != nil {
+			if fault.Percentage == nil {
+				fault.Percentage = NewFloat64(100)
+			}
+			if fault.Duration == nil {
+				fault.Duration = &metav1.Duration{Duration: time.Minute}
+			}
+		}
+	}
+}
+
+func defaultKanaryStatefulsetSpecValidationInconclusive(i *KanaryStatefulsetSpecValidationInconclusive, validationPeriod *metav1.Duration) {
+	if i.Action == "" {
+		i.Action = FailKanaryStatefulsetSpecValidationInconclusiveAction
+	}
+	if i.ExtensionPeriod == nil {
+		i.ExtensionPeriod = validationPeriod.DeepCopy()
+	}
+	if i.MaxExtensions == nil {
+		i.MaxExtensions = NewInt32(1)
+	}
+}
+
+func defaultKanaryStatefulsetSpecValidationScore(s *KanaryStatefulsetSpecValidationScore, validationPeriod *metav1.Duration) {
+	if s.Pass == nil {
+		s.Pass = NewFloat64(95)
+	}
+	if s.Marginal == nil {
+		s.Marginal = NewFloat64(75)
+	}
+	if s.MarginalAction == "" {
+		s.MarginalAction = ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction
+	}
+	if s.ExtensionPeriod == nil {
+		s.ExtensionPeriod = validationPeriod.DeepCopy()
+	}
+	if s.MaxExtensions == nil {
+		s.MaxExtensions = NewInt32(1)
+	}
 }
 
 func defaultKanaryStatefulsetSpecValidation(v *KanaryStatefulsetSpecValidation) {
-	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil {
+	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil && v.Metrics == nil && v.ResourceUsage == nil && v.Logs == nil && v.Events == nil && v.Job == nil && v.Alerts == nil && v.SLO == nil {
 		defaultKanaryStatefulsetSpecScaleValidationManual(v)
 	}
 	if v.Manual != nil {
@@ -280,6 +453,45 @@ func defaultKanaryStatefulsetSpecValidation(v *KanaryStatefulsetSpecValidation)
 		defaultKanaryStatefulsetSpecValidationPromQL(v.PromQL)
 
 	}
+	if v.Metrics != nil {
+		defaultKanaryStatefulsetSpecValidationMetrics(v.Metrics)
+	}
+	if v.Events != nil {
+		if len(v.Events.Reasons) == 0 {
+			v.Events.Reasons = append([]string{}, DefaultEventsValidationReasons...)
+		}
+		if v.Events.MaxOccurrences == nil {
+			v.Events.MaxOccurrences = NewInt32(0)
+		}
+	}
+	if v.SLO != nil {
+		if v.SLO.PrometheusService == "" && v.SLO.URL == "" {
+			v.SLO.PrometheusService = "prometheus:9090"
+		}
+		if v.SLO.PodNameKey == "" {
+			v.SLO.PodNameKey = "pod"
+		}
+		if len(v.SLO.Windows) == 0 {
+			v.SLO.Windows = append([]SLOBurnRateWindow{}, DefaultSLOBurnRateWindows...)
+		}
+	}
+	if v.OnProviderError != nil && v.OnProviderError.Policy == "" {
+		v.OnProviderError.Policy = FailKanaryStatefulsetSpecValidationProviderErrorPolicy
+	}
+}
+func defaultKanaryStatefulsetSpecValidationMetrics(m *KanaryStatefulsetSpecValidationMetrics) {
+	if m.HTTP != nil && m.HTTP.Aggregator == "" {
+		m.HTTP.Aggregator = AnyPromQLRangeQueryAggregator
+	}
+	if m.ContinuousValueDeviation != nil {
+		defaultKanaryStatefulsetSpecValidationPromQLContinuous(m.ContinuousValueDeviation)
+	}
+	if m.DiscreteValueOutOfList != nil {
+		defaultKanaryStatefulsetSpecValidationPromQLDiscreteValueOutOfList(m.DiscreteValueOutOfList)
+	}
+	if m.ValueInRange != nil {
+		defaultKanaryStatefulsetSpecValidationPromQLValueInRange(m.ValueInRange)
+	}
 }
 func defaultKanaryStatefulsetSpecValidationPromQL(pq *KanaryStatefulsetSpecValidationPromQL) {
 	if pq.PrometheusService == "" {
@@ -297,6 +509,36 @@ func defaultKanaryStatefulsetSpecValidationPromQL(pq *KanaryStatefulsetSpecValid
 	if pq.ValueInRange != nil {
 		defaultKanaryStatefulsetSpecValidationPromQLValueInRange(pq.ValueInRange)
 	}
+	if pq.Trend != nil {
+		if pq.Trend.Horizon == nil {
+			pq.Trend.Horizon = &metav1.Duration{Duration: time.Hour}
+		}
+		if pq.RangeQuery == nil {
+			pq.RangeQuery = &PromQLRangeQuery{Window: ValidationPromQLRangeQueryWindow}
+		}
+	}
+	if pq.RangeQuery != nil {
+		defaultKanaryStatefulsetSpecValidationPromQLRangeQuery(pq.RangeQuery)
+	}
+	for i := range pq.Dependencies {
+		if pq.Dependencies[i].BaselineWindow == nil {
+			pq.Dependencies[i].BaselineWindow = &metav1.Duration{Duration: 10 * time.Minute}
+		}
+		if pq.Dependencies[i].MaxIncreasePercent == nil {
+			pq.Dependencies[i].MaxIncreasePercent = NewFloat64(10)
+		}
+	}
+}
+func defaultKanaryStatefulsetSpecValidationPromQLRangeQuery(r *PromQLRangeQuery) {
+	if r.Window == "" {
+		r.Window = LastCheckPromQLRangeQueryWindow
+	}
+	if r.Step == nil {
+		r.Step = &metav1.Duration{Duration: 15 * time.Second}
+	}
+	if r.Aggregator == "" {
+		r.Aggregator = AnyPromQLRangeQueryAggregator
+	}
 }
 func defaultKanaryStatefulsetSpecValidationPromQLValueInRange(c *ValueInRange) {
 	if c.Min == nil {
@@ -336,3 +578,8 @@ func NewUInt(i uint) *uint {
 func NewFloat64(val float64) *float64 {
 	return &val
 }
+
+// NewBool returns new bool pointer instance
+func NewBool(b bool) *bool {
+	return &b
+}
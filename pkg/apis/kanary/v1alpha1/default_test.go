@@ -357,6 +357,247 @@ func Test_defaultKanaryStatefulsetSpecValidationList(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "promQL range query not defaulted",
+			list: &KanaryStatefulsetSpecValidationList{
+				Items: []KanaryStatefulsetSpecValidation{
+					{
+						PromQL: &KanaryStatefulsetSpecValidationPromQL{
+							RangeQuery: &PromQLRangeQuery{},
+						},
+					},
+				},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{
+						PromQL: &KanaryStatefulsetSpecValidationPromQL{
+							PrometheusService: "prometheus:9090",
+							PodNameKey:        "pod",
+							RangeQuery: &PromQLRangeQuery{
+								Window:     LastCheckPromQLRangeQueryWindow,
+								Step:       &metav1.Duration{Duration: 15 * time.Second},
+								Aggregator: AnyPromQLRangeQueryAggregator,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "promQL trend without range query",
+			list: &KanaryStatefulsetSpecValidationList{
+				Items: []KanaryStatefulsetSpecValidation{
+					{
+						PromQL: &KanaryStatefulsetSpecValidationPromQL{
+							Trend: &Trend{Limit: NewFloat64(1024)},
+						},
+					},
+				},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{
+						PromQL: &KanaryStatefulsetSpecValidationPromQL{
+							PrometheusService: "prometheus:9090",
+							PodNameKey:        "pod",
+							Trend: &Trend{
+								Limit:   NewFloat64(1024),
+								Horizon: &metav1.Duration{Duration: time.Hour},
+							},
+							RangeQuery: &PromQLRangeQuery{
+								Window:     ValidationPromQLRangeQueryWindow,
+								Step:       &metav1.Duration{Duration: 15 * time.Second},
+								Aggregator: AnyPromQLRangeQueryAggregator,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "score not defaulted",
+			list: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{Duration: 30 * time.Minute},
+				Items: []KanaryStatefulsetSpecValidation{
+					{Manual: &KanaryStatefulsetSpecValidationManual{StatusAfterDealine: NoneKanaryStatefulsetSpecValidationManualDeadineStatus}},
+				},
+				Score: &KanaryStatefulsetSpecValidationScore{Pass: NewFloat64(90)},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 30 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{Manual: &KanaryStatefulsetSpecValidationManual{StatusAfterDealine: NoneKanaryStatefulsetSpecValidationManualDeadineStatus}},
+				},
+				Score: &KanaryStatefulsetSpecValidationScore{
+					Pass:            NewFloat64(90),
+					Marginal:        NewFloat64(75),
+					MarginalAction:  ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction,
+					ExtensionPeriod: &metav1.Duration{Duration: 30 * time.Minute},
+					MaxExtensions:   NewInt32(1),
+				},
+			},
+		},
+		{
+			name: "maxValidationPeriod without confidence",
+			list: &KanaryStatefulsetSpecValidationList{
+				Items: []KanaryStatefulsetSpecValidation{
+					{Manual: &KanaryStatefulsetSpecValidationManual{StatusAfterDealine: NoneKanaryStatefulsetSpecValidationManualDeadineStatus}},
+				},
+				MaxValidationPeriod: &metav1.Duration{Duration: time.Hour},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{Manual: &KanaryStatefulsetSpecValidationManual{StatusAfterDealine: NoneKanaryStatefulsetSpecValidationManualDeadineStatus}},
+				},
+				MaxValidationPeriod: &metav1.Duration{Duration: time.Hour},
+				Confidence: &KanaryStatefulsetSpecValidationConfidence{
+					NoInconclusive:  true,
+					ExtensionPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+				},
+			},
+		},
+		{
+			name: "slo not defaulted",
+			list: &KanaryStatefulsetSpecValidationList{
+				Items: []KanaryStatefulsetSpecValidation{
+					{SLO: &KanaryStatefulsetSpecValidationSLO{SuccessSelector: "ok_total", TotalSelector: "requests_total", Objective: 99.9}},
+				},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{SLO: &KanaryStatefulsetSpecValidationSLO{
+						PrometheusService: "prometheus:9090",
+						PodNameKey:        "pod",
+						SuccessSelector:   "ok_total",
+						TotalSelector:     "requests_total",
+						Objective:         99.9,
+						Windows:           DefaultSLOBurnRateWindows,
+					}},
+				},
+			},
+		},
+		{
+			name: "promql dependencies not defaulted",
+			list: &KanaryStatefulsetSpecValidationList{
+				Items: []KanaryStatefulsetSpecValidation{
+					{PromQL: &KanaryStatefulsetSpecValidationPromQL{
+						Query:        "errors",
+						Dependencies: []PromQLDependency{{Name: "frontend", Query: "frontend_errors"}},
+					}},
+				},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{PromQL: &KanaryStatefulsetSpecValidationPromQL{
+						PrometheusService: "prometheus:9090",
+						PodNameKey:        "pod",
+						Query:             "errors",
+						Dependencies: []PromQLDependency{{
+							Name:               "frontend",
+							Query:              "frontend_errors",
+							BaselineWindow:     &metav1.Duration{Duration: 10 * time.Minute},
+							MaxIncreasePercent: NewFloat64(10),
+						}},
+					}},
+				},
+			},
+		},
+		{
+			name: "chaos not defaulted",
+			list: &KanaryStatefulsetSpecValidationList{
+				Chaos: &KanaryStatefulsetSpecValidationChaos{
+					Actions: []KanaryStatefulsetSpecValidationChaosAction{
+						{Name: "kill", PodKill: &ChaosPodKill{}},
+						{Name: "abort", HTTPFault: &ChaosHTTPFault{VirtualService: "foo", Subset: "canary", AbortHTTPStatus: NewInt32(503)}},
+					},
+				},
+			},
+			want: &KanaryStatefulsetSpecValidationList{
+				ValidationPeriod: &metav1.Duration{
+					Duration: 15 * time.Minute,
+				},
+				InitialDelay: &metav1.Duration{
+					Duration: 0 * time.Minute,
+				},
+				MaxIntervalPeriod: &metav1.Duration{
+					Duration: 20 * time.Second,
+				},
+				Items: []KanaryStatefulsetSpecValidation{
+					{
+						Manual: &KanaryStatefulsetSpecValidationManual{
+							StatusAfterDealine: NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+						},
+					},
+				},
+				Chaos: &KanaryStatefulsetSpecValidationChaos{
+					StartDelay: &metav1.Duration{},
+					Actions: []KanaryStatefulsetSpecValidationChaosAction{
+						{Name: "kill", PodKill: &ChaosPodKill{ReadyTimeout: &metav1.Duration{Duration: 2 * time.Minute}}},
+						{Name: "abort", HTTPFault: &ChaosHTTPFault{
+							VirtualService:  "foo",
+							Subset:          "canary",
+							AbortHTTPStatus: NewInt32(503),
+							Percentage:      NewFloat64(100),
+							Duration:        &metav1.Duration{Duration: time.Minute},
+						}},
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
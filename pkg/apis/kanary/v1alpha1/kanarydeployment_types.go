@@ -3,8 +3,10 @@ package v1alpha1
 import (
 	"k8s.io/api/apps/v1beta1"
 	"k8s.io/api/autoscaling/v2beta1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	v1 "k8s.io/api/core/v1"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -140,19 +142,285 @@ type KanaryStatefulsetSpecValidationList struct {
 	NoUpdate bool `json:"noUpdate,omitempty"`
 	// Items list of KanaryStatefulsetSpecValidation
 	Items []KanaryStatefulsetSpecValidation `json:"items,omitempty"`
+	// Policy defines how the outcomes of the validation items are combined to fail the KanaryStatefulset.
+	// If not set, the KanaryStatefulset fails as soon as one of the items (not advisory) fails.
+	Policy *KanaryStatefulsetSpecValidationPolicy `json:"policy,omitempty"`
+	// Score if set, the outcome of the validation is decided by a weighted score of the validation items,
+	// instead of the policy.
+	Score *KanaryStatefulsetSpecValidationScore `json:"score,omitempty"`
+	// Inconclusive defines the action applied at the end of the validation period when the last check of a validation
+	// item is inconclusive, for example because of a too small number of samples. If not set, the KanaryStatefulset fails.
+	Inconclusive *KanaryStatefulsetSpecValidationInconclusive `json:"inconclusive,omitempty"`
+	// MaxValidationPeriod if set, the validation period is extended until the Confidence criterion is met,
+	// the validation never lasts longer than MaxValidationPeriod.
+	MaxValidationPeriod *metav1.Duration `json:"maxValidationPeriod,omitempty"`
+	// Confidence criterion required to end the validation period, used with MaxValidationPeriod.
+	// If not set, the validation period is extended while a validation item is inconclusive.
+	Confidence *KanaryStatefulsetSpecValidationConfidence `json:"confidence,omitempty"`
+	// EarlySuccess if set, the validation succeeds before the end of the validation period when all its conditions are met
+	EarlySuccess *KanaryStatefulsetSpecValidationEarlySuccess `json:"earlySuccess,omitempty"`
+	// Chaos if set, faults are injected during the validation period to test the resilience of the canary.
+	// The validation items keep running during the chaos phase.
+	Chaos *KanaryStatefulsetSpecValidationChaos `json:"chaos,omitempty"`
 }
 
+// KanaryStatefulsetSpecValidationChaos defines the chaos phase of the validation
+type KanaryStatefulsetSpecValidationChaos struct {
+	// StartDelay duration since the beginning of the validation period before the first action. Default value is 0.
+	StartDelay *metav1.Duration `json:"startDelay,omitempty"`
+	// Actions run one after the other, an action starts at the first check after the end of the previous one
+	Actions []KanaryStatefulsetSpecValidationChaosAction `json:"actions"`
+}
+
+// KanaryStatefulsetSpecValidationChaosAction defines a fault injected during the chaos phase, only one fault should be defined
+type KanaryStatefulsetSpecValidationChaosAction struct {
+	// Name of the action, used in the status and in the failure message
+	Name string `json:"name"`
+	// PodKill deletes one canary pod, the pod should be Ready again before the timeout
+	PodKill *ChaosPodKill `json:"podKill,omitempty"`
+	// HTTPFault injects HTTP delays or aborts with istio in the traffic to the canary subset
+	HTTPFault *ChaosHTTPFault `json:"httpFault,omitempty"`
+}
+
+// ChaosPodKill defines the deletion of a canary pod
+type ChaosPodKill struct {
+	// ReadyTimeout the pod recreated by the statefulset should be Ready within this duration. Default value is 2m.
+	ReadyTimeout *metav1.Duration `json:"readyTimeout,omitempty"`
+}
+
+// ChaosHTTPFault defines an istio fault injected in the http routes of a VirtualService that target only the canary subset
+type ChaosHTTPFault struct {
+	// VirtualService name of the istio VirtualService in the namespace of the KanaryStatefulset
+	VirtualService string `json:"virtualService"`
+	// Subset of the istio DestinationRule that selects the canary pods
+	Subset string `json:"subset"`
+	// Delay fixed delay added to the requests
+	Delay *metav1.Duration `json:"delay,omitempty"`
+	// AbortHTTPStatus http status returned instead of forwarding the requests
+	AbortHTTPStatus *int32 `json:"abortHttpStatus,omitempty"`
+	// Percentage of the requests impacted by the fault. Default value is 100.
+	Percentage *float64 `json:"percentage,omitempty"`
+	// Duration of the fault injection. Default value is 1m.
+	Duration *metav1.Duration `json:"duration,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationEarlySuccess defines the conditions to promote the canary before the end of the validation period
+type KanaryStatefulsetSpecValidationEarlySuccess struct {
+	// MinElapsed minimum duration since the beginning of the validation period before an early success. Default value is 10m.
+	MinElapsed *metav1.Duration `json:"minElapsed,omitempty"`
+	// PrometheusService prometheus server of the conditions queries. Default value is prometheus:9090.
+	PrometheusService string `json:"prometheusService,omitempty"`
+	// URL of the prometheus server, it takes precedence over PrometheusService.
+	URL string `json:"url,omitempty"`
+	// Auth defines how to authenticate to the prometheus server.
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+	// Conditions all of them should be met for an early success
+	Conditions []KanaryStatefulsetSpecValidationEarlySuccessCondition `json:"conditions"`
+}
+
+// KanaryStatefulsetSpecValidationEarlySuccessCondition compares the result of a promQL query to a value or to the result of a baseline query
+type KanaryStatefulsetSpecValidationEarlySuccessCondition struct {
+	// Name of the condition, used in the status messages
+	Name string `json:"name,omitempty"`
+	// Query promQL query rendered as a go template (see README for the available variables), the values of the series are summed.
+	Query string `json:"query"`
+	// Operator comparison of the query result with the value or the baseline
+	Operator KanaryStatefulsetSpecValidationEarlySuccessOperator `json:"operator"`
+	// Value static threshold
+	Value *float64 `json:"value,omitempty"`
+	// BaselineQuery promQL query returning the threshold, for example the same metric on the stable pods
+	BaselineQuery string `json:"baselineQuery,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationEarlySuccessOperator defines the comparison of an early success condition
+type KanaryStatefulsetSpecValidationEarlySuccessOperator string
+
+const (
+	// LessThanKanaryStatefulsetSpecValidationEarlySuccessOperator the query result should be less than the threshold
+	LessThanKanaryStatefulsetSpecValidationEarlySuccessOperator KanaryStatefulsetSpecValidationEarlySuccessOperator = "LessThan"
+	// GreaterThanKanaryStatefulsetSpecValidationEarlySuccessOperator the query result should be greater than the threshold
+	GreaterThanKanaryStatefulsetSpecValidationEarlySuccessOperator KanaryStatefulsetSpecValidationEarlySuccessOperator = "GreaterThan"
+)
+
+// KanaryStatefulsetSpecValidationConfidence defines the criterion required to end the validation period.
+// All the configured conditions should be met.
+type KanaryStatefulsetSpecValidationConfidence struct {
+	// NoInconclusive requires that the last check of the validation items (not advisory) is conclusive
+	NoInconclusive bool `json:"noInconclusive,omitempty"`
+	// MinRequests requires a minimum number of requests received by the canary
+	MinRequests *KanaryStatefulsetSpecValidationConfidenceRequests `json:"minRequests,omitempty"`
+	// ExtensionPeriod duration added to the validation period while the criterion is not met. Default value is 5m.
+	ExtensionPeriod *metav1.Duration `json:"extensionPeriod,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationConfidenceRequests defines a promQL query returning the number of requests received by the canary
+type KanaryStatefulsetSpecValidationConfidenceRequests struct {
+	PrometheusService string `json:"prometheusService,omitempty"`
+	// URL of the prometheus server, it takes precedence over PrometheusService.
+	URL string `json:"url,omitempty"`
+	// Auth defines how to authenticate to the prometheus server.
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+	// Query promQL query rendered as a go template (see README for the available variables), the values of the series are summed.
+	Query string `json:"query"`
+	// Min minimum number of requests
+	Min float64 `json:"min"`
+}
+
+// KanaryStatefulsetSpecValidationInconclusive defines the action applied when a validation item is still inconclusive
+// at the end of the validation period.
+type KanaryStatefulsetSpecValidationInconclusive struct {
+	// Action applied at the end of the validation period: Fail, Pass or Extend. Default value is Fail.
+	Action KanaryStatefulsetSpecValidationInconclusiveAction `json:"action,omitempty"`
+	// ExtensionPeriod duration added to the validation period by the Extend action. Default value is the validation period.
+	ExtensionPeriod *metav1.Duration `json:"extensionPeriod,omitempty"`
+	// MaxExtensions maximum number of extensions, the KanaryStatefulset fails if a validation item is still inconclusive. Default value is 1.
+	MaxExtensions *int32 `json:"maxExtensions,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationInconclusiveAction defines the action applied when a validation item is inconclusive at the end of the validation period.
+type KanaryStatefulsetSpecValidationInconclusiveAction string
+
+const (
+	// FailKanaryStatefulsetSpecValidationInconclusiveAction means that the KanaryStatefulset fails.
+	FailKanaryStatefulsetSpecValidationInconclusiveAction KanaryStatefulsetSpecValidationInconclusiveAction = "Fail"
+	// PassKanaryStatefulsetSpecValidationInconclusiveAction means that the inconclusive validation items are considered as passed.
+	PassKanaryStatefulsetSpecValidationInconclusiveAction KanaryStatefulsetSpecValidationInconclusiveAction = "Pass"
+	// ExtendKanaryStatefulsetSpecValidationInconclusiveAction means that the validation period is extended.
+	ExtendKanaryStatefulsetSpecValidationInconclusiveAction KanaryStatefulsetSpecValidationInconclusiveAction = "Extend"
+)
+
+// KanaryStatefulsetSpecValidationScore defines a weighted score computed at each check from the sub-scores (0-100)
+// of the validation items. The KanaryStatefulset fails as soon as the score is below Marginal. At the end of the
+// validation period, it succeeds if the score is greater or equal to Pass, else the MarginalAction is applied.
+type KanaryStatefulsetSpecValidationScore struct {
+	// Pass minimum score for a success at the end of the validation period. Default value is 95.
+	Pass *float64 `json:"pass,omitempty"`
+	// Marginal the KanaryStatefulset fails when the score is below Marginal. Default value is 75.
+	Marginal *float64 `json:"marginal,omitempty"`
+	// MarginalAction action applied at the end of the validation period when the score is between Marginal and Pass:
+	// ExtendValidation or ManualApproval. Default value is ExtendValidation.
+	MarginalAction KanaryStatefulsetSpecValidationScoreMarginalAction `json:"marginalAction,omitempty"`
+	// ExtensionPeriod duration added to the validation period by the ExtendValidation action. Default value is the validation period.
+	ExtensionPeriod *metav1.Duration `json:"extensionPeriod,omitempty"`
+	// MaxExtensions maximum number of extensions, the KanaryStatefulset fails if the score is still marginal. Default value is 1.
+	MaxExtensions *int32 `json:"maxExtensions,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationScoreMarginalAction defines the action applied when the score is marginal at the end of the validation period.
+type KanaryStatefulsetSpecValidationScoreMarginalAction string
+
+const (
+	// ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction means that the validation period is extended.
+	ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction KanaryStatefulsetSpecValidationScoreMarginalAction = "ExtendValidation"
+	// ManualApprovalKanaryStatefulsetSpecValidationScoreMarginalAction means that the KanaryStatefulset waits for the decision of a manual validation item.
+	ManualApprovalKanaryStatefulsetSpecValidationScoreMarginalAction KanaryStatefulsetSpecValidationScoreMarginalAction = "ManualApproval"
+)
+
+// KanaryStatefulsetSpecValidationPolicy defines a group of operands, validation items or nested groups, and
+// the operator that combines their outcomes. The items are referenced by name, the items not referenced
+// by the policy are advisory.
+type KanaryStatefulsetSpecValidationPolicy struct {
+	// Operator combining the outcomes of the operands: And, Or or Quorum
+	Operator KanaryStatefulsetSpecValidationPolicyOperator `json:"operator"`
+	// Items names of the validation items used as operands
+	Items []string `json:"items,omitempty"`
+	// Groups nested groups used as operands
+	Groups []KanaryStatefulsetSpecValidationPolicy `json:"groups,omitempty"`
+	// Quorum minimum number of operands that must pass with the Quorum operator
+	Quorum *int32 `json:"quorum,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationPolicyOperator defines how the outcomes of the operands of a policy group are combined.
+type KanaryStatefulsetSpecValidationPolicyOperator string
+
+const (
+	// AndKanaryStatefulsetSpecValidationPolicyOperator means that the group fails if all its operands fail.
+	AndKanaryStatefulsetSpecValidationPolicyOperator KanaryStatefulsetSpecValidationPolicyOperator = "And"
+	// OrKanaryStatefulsetSpecValidationPolicyOperator means that the group fails if one of its operands fails.
+	OrKanaryStatefulsetSpecValidationPolicyOperator KanaryStatefulsetSpecValidationPolicyOperator = "Or"
+	// QuorumKanaryStatefulsetSpecValidationPolicyOperator means that the group fails if less than quorum operands pass.
+	QuorumKanaryStatefulsetSpecValidationPolicyOperator KanaryStatefulsetSpecValidationPolicyOperator = "Quorum"
+)
+
 // KanaryStatefulsetSpecValidation defines the validation configuration for the canary deployment
 type KanaryStatefulsetSpecValidation struct {
+	// Name is an optional name used to identify the validation item in the status
+	Name string `json:"name,omitempty"`
+
 	Manual     *KanaryStatefulsetSpecValidationManual     `json:"manual,omitempty"`
 	LabelWatch *KanaryStatefulsetSpecValidationLabelWatch `json:"labelWatch,omitempty"`
 	PromQL     *KanaryStatefulsetSpecValidationPromQL     `json:"promQL,omitempty"`
+	Metrics    *KanaryStatefulsetSpecValidationMetrics    `json:"metrics,omitempty"`
+
+	// ResourceUsage compares the cpu and memory usage of the canary pods with the stable pods, using the metrics.k8s.io API
+	ResourceUsage *KanaryStatefulsetSpecValidationResourceUsage `json:"resourceUsage,omitempty"`
+	// Logs counts the lines of the canary pods logs that match some patterns
+	Logs *KanaryStatefulsetSpecValidationLogs `json:"logs,omitempty"`
+	// Events counts the Warning events involving the canary pods or the StatefulSet
+	Events *KanaryStatefulsetSpecValidationEvents `json:"events,omitempty"`
+	// Job runs a Job against the canary and uses its outcome as the verdict
+	Job *KanaryStatefulsetSpecValidationJob `json:"job,omitempty"`
+	// Alerts fails the canary if some alerts matching the label matchers are firing in an Alertmanager
+	Alerts *KanaryStatefulsetSpecValidationAlerts `json:"alerts,omitempty"`
+	// SLO fails the canary if the canary pods burn the error budget of an objective too fast
+	SLO *KanaryStatefulsetSpecValidationSLO `json:"slo,omitempty"`
+
+	// FailureThreshold is the number of consecutive failed checks before the validation is considered as failed.
+	// Default value is 1, unless FailureLimit is set: the consecutive failures are then only limited by FailureLimit.
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+	// FailureLimit is the total number of failed checks over the validation period before the validation is
+	// considered as failed. Not set or 0 means no limit.
+	FailureLimit *int32 `json:"failureLimit,omitempty"`
+	// SuccessThreshold is the number of consecutive successful checks needed after a failed check
+	// to reset the consecutive failures counter. Default value is 1.
+	SuccessThreshold *int32 `json:"successThreshold,omitempty"`
+	// Advisory if set to true, a failure of the item is only reported as a warning in the status,
+	// it never fails the KanaryStatefulset.
+	Advisory bool `json:"advisory,omitempty"`
+	// Weight of the item in the score (spec.validations.score). Default value is 1.
+	Weight *int32 `json:"weight,omitempty"`
+	// OnProviderError defines what happens when the check returns an error, for example when the metrics backend
+	// is unreachable. If not set, the error is reported in the Errored condition and the check is retried.
+	OnProviderError *KanaryStatefulsetSpecValidationProviderError `json:"onProviderError,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationProviderError defines the policy applied when a check returns an error
+type KanaryStatefulsetSpecValidationProviderError struct {
+	// Policy applied during the outage. Default value is Fail.
+	Policy KanaryStatefulsetSpecValidationProviderErrorPolicy `json:"policy,omitempty"`
+	// MaxOutage maximum duration of an outage, the KanaryStatefulset fails if the outage lasts longer.
+	// Not set means no limit, except for the Fail policy which fails at the first error.
+	MaxOutage *metav1.Duration `json:"maxOutage,omitempty"`
 }
 
+// KanaryStatefulsetSpecValidationProviderErrorPolicy defines the policy applied when a check returns an error
+type KanaryStatefulsetSpecValidationProviderErrorPolicy string
+
+const (
+	// FailKanaryStatefulsetSpecValidationProviderErrorPolicy the KanaryStatefulset fails when the outage lasts longer than maxOutage
+	FailKanaryStatefulsetSpecValidationProviderErrorPolicy KanaryStatefulsetSpecValidationProviderErrorPolicy = "Fail"
+	// PauseKanaryStatefulsetSpecValidationProviderErrorPolicy the validation clock is stopped during the outage
+	PauseKanaryStatefulsetSpecValidationProviderErrorPolicy KanaryStatefulsetSpecValidationProviderErrorPolicy = "Pause"
+	// IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy the failing checks are skipped, the validation clock keeps running
+	IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy KanaryStatefulsetSpecValidationProviderErrorPolicy = "Ignore"
+)
+
 // KanaryStatefulsetSpecValidationManual defines the manual validation configuration
 type KanaryStatefulsetSpecValidationManual struct {
 	StatusAfterDealine KanaryStatefulsetSpecValidationManualDeadineStatus `json:"statusAfterDeadline,omitempty"`
 	Status             KanaryStatefulsetSpecValidationManualStatus        `json:"status,omitempty"`
+	// Approvers if set, only the allowed users and groups can take the decision, with the approval annotation
+	// or the status field. The decisions are checked by the kanary admission webhook and recorded in the status.
+	Approvers *KanaryStatefulsetSpecValidationManualApprovers `json:"approvers,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationManualApprovers defines the allow-list of the users and groups that can approve
+// or reject a KanaryStatefulset
+type KanaryStatefulsetSpecValidationManualApprovers struct {
+	// Users names of the allowed users
+	Users []string `json:"users,omitempty"`
+	// Groups names of the allowed groups
+	Groups []string `json:"groups,omitempty"`
 }
 
 // KanaryStatefulsetSpecValidationManualDeadineStatus defines the validation manual deadine mode
@@ -187,20 +455,281 @@ type KanaryStatefulsetSpecValidationLabelWatch struct {
 	// DeploymentInvalidationLabels defines labels that should be present on the canary deployment in order to invalidate
 	// the canary deployment
 	DeploymentInvalidationLabels *metav1.LabelSelector `json:"deploymentInvalidationLabels,omitempty"`
+	// StatefulSetInvalidationLabels defines labels that should be present on the StatefulSet in order to invalidate
+	// the canary
+	StatefulSetInvalidationLabels *metav1.LabelSelector `json:"statefulSetInvalidationLabels,omitempty"`
+	// PodInvalidationAnnotations defines annotations that should be present on the canary pods in order to invalidate
+	// the canary. The selector is evaluated against the annotations, so the values should be valid label values.
+	PodInvalidationAnnotations *metav1.LabelSelector `json:"podInvalidationAnnotations,omitempty"`
+	// StatefulSetInvalidationAnnotations defines annotations that should be present on the StatefulSet in order to
+	// invalidate the canary. The selector is evaluated against the annotations, so the values should be valid label values.
+	StatefulSetInvalidationAnnotations *metav1.LabelSelector `json:"statefulSetInvalidationAnnotations,omitempty"`
 }
 
 // KanaryStatefulsetSpecValidationPromQL defines the promQL validation configuration
 type KanaryStatefulsetSpecValidationPromQL struct {
 	PrometheusService string `json:"prometheusService"`
-	Query             string `json:"query"` //The promQL query
+	Query             string `json:"query"` //The promQL query, rendered as a go template (see README for the available variables)
 	// note the AND close that prevent to return record when there is less that 70 records over the floating time window of 1m
 	PodNameKey               string                    `json:"podNamekey"`   // Key to access the podName
 	AllPodsQuery             bool                      `json:"allPodsQuery"` // This indicate that the query will return a result that is applicable to all pods. The pod dimension and so the PodNameKey is not taken into account. Default value is false.
 	ValueInRange             *ValueInRange             `json:"valueInRange,omitempty"`
 	DiscreteValueOutOfList   *DiscreteValueOutOfList   `json:"discreteValueOutOfList,omitempty"`
 	ContinuousValueDeviation *ContinuousValueDeviation `json:"continuousValueDeviation,omitempty"`
+	// Trend fits a linear regression on the values of each pod over the range query, for example to detect a memory leak.
+	// If set without RangeQuery, the range query window is "validation".
+	Trend *Trend `json:"trend,omitempty"`
+	// RangeQuery if set, the query is evaluated with a range query over a time window instead of an instant query.
+	RangeQuery *PromQLRangeQuery `json:"rangeQuery,omitempty"`
+	// URL of the prometheus server, for example "https://prometheus.example.com:9090".
+	// If set, it takes precedence over PrometheusService.
+	URL string `json:"url,omitempty"`
+	// Auth defines how to authenticate to the prometheus server.
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+	// Exclusions defines the pods excluded from the anomaly detection, for instance the pods still warming up.
+	Exclusions *PromQLExclusions `json:"exclusions,omitempty"`
+	// Dependencies services or workloads, calling the canary or called by the canary, whose metric must not regress
+	// compared to a baseline captured before the canary start.
+	Dependencies []PromQLDependency `json:"dependencies,omitempty"`
+}
+
+// PromQLDependency defines a service or a workload upstream or downstream of the canary, for instance its error rate or its latency.
+// The dependency regresses when its value increases more than the tolerance compared to its baseline.
+type PromQLDependency struct {
+	// Name of the dependency, used in the status and in the failure message
+	Name string `json:"name"`
+	// Query promQL query of the dependency, rendered as a go template. The value is the sum of the series returned.
+	// The {{.Window}} variable is the baseline window, for example "10m".
+	Query string `json:"query"`
+	// BaselineWindow duration before the canary start used to compute the baseline, default 10m
+	BaselineWindow *metav1.Duration `json:"baselineWindow,omitempty"`
+	// MaxIncreasePercent maximum increase of the value compared to the baseline, default 10
+	MaxIncreasePercent *float64 `json:"maxIncreasePercent,omitempty"`
+	// MinIncrease increase of the value tolerated whatever the MaxIncreasePercent, for instance when the baseline is close to 0
+	MinIncrease *float64 `json:"minIncrease,omitempty"`
+}
+
+// PromQLExclusions defines the pods excluded from the anomaly detection of a promQL validation
+type PromQLExclusions struct {
+	// WarmUp excludes a pod during this period after its Ready transition
+	WarmUp *metav1.Duration `json:"warmUp,omitempty"`
+	// MinRequests excludes the pods with fewer requests than this value, according to the RequestsQuery
+	MinRequests *float64 `json:"minRequests,omitempty"`
+	// RequestsQuery companion promQL query returning the number of requests per pod, with the same PodNameKey
+	// and the same template variables as the query. A pod missing from the result has no request.
+	RequestsQuery string `json:"requestsQuery,omitempty"`
+	// Labels excludes the pods matching this selector
+	Labels *metav1.LabelSelector `json:"labels,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationMetrics defines a validation based on the samples returned by a metrics provider
+type KanaryStatefulsetSpecValidationMetrics struct {
+	// HTTP provider, the samples are extracted from the JSON response of an HTTP endpoint
+	HTTP                     *HTTPMetricsProvider      `json:"http,omitempty"`
+	ValueInRange             *ValueInRange             `json:"valueInRange,omitempty"`
+	DiscreteValueOutOfList   *DiscreteValueOutOfList   `json:"discreteValueOutOfList,omitempty"`
+	ContinuousValueDeviation *ContinuousValueDeviation `json:"continuousValueDeviation,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationResourceUsage defines a validation based on the resource usage of the pods
+type KanaryStatefulsetSpecValidationResourceUsage struct {
+	// CPU bounds of the cpu usage of a canary pod
+	CPU *ResourceUsageThreshold `json:"cpu,omitempty"`
+	// Memory bounds of the memory usage of a canary pod
+	Memory *ResourceUsageThreshold `json:"memory,omitempty"`
+}
+
+// ResourceUsageThreshold defines the bounds of the usage of a resource
+type ResourceUsageThreshold struct {
+	// MaxIncreasePercent maximum increase of a canary pod usage compared to the average usage of the stable pods
+	MaxIncreasePercent *float64 `json:"maxIncreasePercent,omitempty"`
+	// Limit maximum usage of a canary pod
+	Limit *resource.Quantity `json:"limit,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationLogs defines a validation based on the logs of the pods. The logs written since
+// the previous check are read, the lines matching one of the patterns are counted and converted to a rate per minute.
+type KanaryStatefulsetSpecValidationLogs struct {
+	// Container name of the container, default is the first container of the pod
+	Container string `json:"container,omitempty"`
+	// Patterns regular expressions, a line is counted if it matches one of them, for example "ERROR|panic:"
+	Patterns []string `json:"patterns"`
+	// MaxLinesPerMinute maximum rate of matching lines of a canary pod
+	MaxLinesPerMinute *float64 `json:"maxLinesPerMinute,omitempty"`
+	// MaxIncreasePercent if set, the rate of a canary pod is compared with the average rate of the stable pods.
+	// 0 means that any rate greater than the stable pods rate invalidates the canary.
+	MaxIncreasePercent *float64 `json:"maxIncreasePercent,omitempty"`
 }
 
+// KanaryStatefulsetSpecValidationEvents defines a validation based on the Warning events involving the canary pods
+// or the StatefulSet since the validation start
+type KanaryStatefulsetSpecValidationEvents struct {
+	// Reasons deny-list of event reasons. Default value is FailedMount, Unhealthy, BackOff and FailedScheduling.
+	Reasons []string `json:"reasons,omitempty"`
+	// MaxOccurrences the validation fails when the events of a reason occurred more than MaxOccurrences times. Default value is 0.
+	MaxOccurrences *int32 `json:"maxOccurrences,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationJob defines a validation based on the outcome of a Job, for instance an
+// integration test suite running against the kanary service
+type KanaryStatefulsetSpecValidationJob struct {
+	// Template of the Job created by the validation. The environment variables KANARY_SERVICE (DNS name of the
+	// kanary service) and KANARY_POD_IPS (comma separated IPs of the canary pods) are added to its containers.
+	Template batchv1beta1.JobTemplateSpec `json:"template"`
+}
+
+// KanaryStatefulsetSpecValidationAlerts defines a validation based on the alerts of an Alertmanager, using the
+// Alertmanager v2 API. An alert matching all the matchers and active when the validation item is checked invalidates
+// the canary. Only the alerts still active at the time of a check are seen: an alert that fires and resolves between
+// two checks is missed, the interval between the checks is at most spec.validations.maxIntervalPeriod.
+type KanaryStatefulsetSpecValidationAlerts struct {
+	// URL of the Alertmanager, for example "http://alertmanager.monitoring:9093"
+	URL string `json:"url"`
+	// Matchers label matchers of the alerts, the values are rendered as go templates with the same variables
+	// as the promQL query, for example {name: "pod", value: "{{.CanaryPodsRegex}}", isRegex: true}
+	Matchers []AlertMatcher `json:"matchers"`
+	// Headers added to the request
+	Headers map[string]string `json:"headers,omitempty"`
+	// SecretHeaders added to the request, the values are read from secrets of the KanaryStatefulset namespace
+	SecretHeaders map[string]v1.SecretKeySelector `json:"secretHeaders,omitempty"`
+}
+
+// KanaryStatefulsetSpecValidationSLO defines a validation of an error-ratio objective with multi-window burn-rate
+// thresholds. The burn rate is the error ratio of the canary pods divided by the error budget (1 - objective).
+type KanaryStatefulsetSpecValidationSLO struct {
+	PrometheusService string `json:"prometheusService,omitempty"`
+	// URL of the prometheus server, it takes precedence over PrometheusService.
+	URL string `json:"url,omitempty"`
+	// Auth defines how to authenticate to the prometheus server.
+	Auth *PrometheusAuth `json:"auth,omitempty"`
+	// PodNameKey label of the pod name in the counters, used to select the canary pods. Default value is pod.
+	PodNameKey string `json:"podNameKey,omitempty"`
+	// AllPodsQuery indicates that the selectors already select the canary requests, PodNameKey is not used. Default value is false.
+	AllPodsQuery bool `json:"allPodsQuery,omitempty"`
+	// SuccessSelector promQL selector of the counter of the successful requests, for example http_requests_total{code!~"5.."}
+	SuccessSelector string `json:"successSelector"`
+	// TotalSelector promQL selector of the counter of all the requests, for example http_requests_total
+	TotalSelector string `json:"totalSelector"`
+	// Objective percentage of successful requests, for example 99.9
+	Objective float64 `json:"objective"`
+	// Windows the canary fails if the burn rate exceeds the factor over both the short and the long window of one of them.
+	// Default value is 5m/1h with a factor of 14.4 and 30m/6h with a factor of 6.
+	Windows []SLOBurnRateWindow `json:"windows,omitempty"`
+}
+
+// SLOBurnRateWindow defines a burn-rate threshold over a short and a long window
+type SLOBurnRateWindow struct {
+	Short  metav1.Duration `json:"short"`
+	Long   metav1.Duration `json:"long"`
+	Factor float64         `json:"factor"`
+}
+
+// AlertMatcher defines an alert label matcher, same as the Alertmanager matchers
+type AlertMatcher struct {
+	// Name of the label
+	Name string `json:"name"`
+	// Value of the label, or regular expression if IsRegex is true
+	Value string `json:"value"`
+	// IsRegex the value is a regular expression, anchored at both ends
+	IsRegex bool `json:"isRegex,omitempty"`
+	// IsEqual if false, the matcher selects the alerts whose label does not match. Default value is true.
+	IsEqual *bool `json:"isEqual,omitempty"`
+}
+
+// DefaultEventsValidationReasons default deny-list of the events validation
+var DefaultEventsValidationReasons = []string{"FailedMount", "Unhealthy", "BackOff", "FailedScheduling"}
+
+// HTTPMetricsProvider defines how to get the samples from an HTTP endpoint returning a JSON document
+type HTTPMetricsProvider struct {
+	// URL called with a GET request, rendered as a go template with the same variables as the promQL query
+	URL string `json:"url"`
+	// Headers added to the request
+	Headers map[string]string `json:"headers,omitempty"`
+	// SecretHeaders added to the request, the values are read from secrets of the KanaryStatefulset namespace
+	SecretHeaders map[string]v1.SecretKeySelector `json:"secretHeaders,omitempty"`
+	// ItemsPath JSONPath to the list of items in the response, one item per pod (or per serie), for example "{.series[*]}"
+	ItemsPath string `json:"itemsPath"`
+	// PodNamePath JSONPath to the pod name, relative to an item, for example "{.tags.pod}"
+	PodNamePath string `json:"podNamePath,omitempty"`
+	// ValuePath JSONPath to the value, relative to an item. If several values are returned, they are reduced with the aggregator
+	ValuePath string `json:"valuePath"`
+	// LabelPaths JSONPath to additional dimensions, relative to an item, the key of discreteValueOutOfList should be defined here
+	LabelPaths map[string]string `json:"labelPaths,omitempty"`
+	// AllPodsQuery indicates that the values are applicable to all pods, PodNamePath is not used. Default value is false.
+	AllPodsQuery bool `json:"allPodsQuery,omitempty"`
+	// Aggregator reduces the values of an item to one value, same values as the promQL rangeQuery aggregator. Default value is "any".
+	Aggregator PromQLRangeQueryAggregator `json:"aggregator,omitempty"`
+}
+
+// PrometheusAuth defines the authentication to the prometheus server.
+// The secrets are read from the KanaryStatefulset namespace at each validation.
+type PrometheusAuth struct {
+	// BearerToken secret key containing the token sent in the Authorization header
+	BearerToken *v1.SecretKeySelector `json:"bearerToken,omitempty"`
+	// BasicAuth defines the user and password sent in the Authorization header
+	BasicAuth *PrometheusBasicAuth `json:"basicAuth,omitempty"`
+	// TLS defines the TLS configuration used to connect to the prometheus server
+	TLS *PrometheusTLSConfig `json:"tls,omitempty"`
+	// Headers are added to each request, for example "X-Scope-OrgID" for a multi-tenant server (Thanos, Cortex)
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// PrometheusBasicAuth defines the basic authentication to the prometheus server
+type PrometheusBasicAuth struct {
+	Username v1.SecretKeySelector `json:"username"`
+	Password v1.SecretKeySelector `json:"password"`
+}
+
+// PrometheusTLSConfig defines the TLS configuration to connect to the prometheus server
+type PrometheusTLSConfig struct {
+	// CA secret key containing the PEM encoded CA used to verify the server certificate
+	CA *v1.SecretKeySelector `json:"ca,omitempty"`
+	// Cert secret key containing the PEM encoded client certificate (mTLS)
+	Cert *v1.SecretKeySelector `json:"cert,omitempty"`
+	// Key secret key containing the PEM encoded client key (mTLS)
+	Key *v1.SecretKeySelector `json:"key,omitempty"`
+	// ServerName is used to verify the hostname of the server certificate
+	ServerName string `json:"serverName,omitempty"`
+	// InsecureSkipVerify disables the verification of the server certificate
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// PromQLRangeQuery defines how the promQL query is evaluated over a time window
+type PromQLRangeQuery struct {
+	// Window defines the beginning of the evaluated time range. Default value is "lastCheck".
+	Window PromQLRangeQueryWindow `json:"window,omitempty"`
+	// Step is the query resolution step width. Default value is 15s.
+	Step *metav1.Duration `json:"step,omitempty"`
+	// Aggregator reduces the samples of each returned serie to one value: "max", "min", "avg",
+	// "pN" for the Nth percentile (for example "p95"), or "any" to consider the pod out of bounds
+	// as soon as one sample is. Default value is "any".
+	Aggregator PromQLRangeQueryAggregator `json:"aggregator,omitempty"`
+}
+
+// PromQLRangeQueryWindow defines the beginning of the time range of a promQL range query
+type PromQLRangeQueryWindow string
+
+const (
+	// ValidationPromQLRangeQueryWindow means that the range starts when the validation starts (creation + initialDelay).
+	ValidationPromQLRangeQueryWindow PromQLRangeQueryWindow = "validation"
+	// LastCheckPromQLRangeQueryWindow means that the range starts at the previous validation check.
+	LastCheckPromQLRangeQueryWindow PromQLRangeQueryWindow = "lastCheck"
+)
+
+// PromQLRangeQueryAggregator defines how the samples of a serie are reduced to a single value
+type PromQLRangeQueryAggregator string
+
+const (
+	// MaxPromQLRangeQueryAggregator keeps the highest sample
+	MaxPromQLRangeQueryAggregator PromQLRangeQueryAggregator = "max"
+	// MinPromQLRangeQueryAggregator keeps the lowest sample
+	MinPromQLRangeQueryAggregator PromQLRangeQueryAggregator = "min"
+	// AvgPromQLRangeQueryAggregator computes the average of the samples
+	AvgPromQLRangeQueryAggregator PromQLRangeQueryAggregator = "avg"
+	// AnyPromQLRangeQueryAggregator keeps the first sample out of bounds if any, else the last sample
+	AnyPromQLRangeQueryAggregator PromQLRangeQueryAggregator = "any"
+)
+
 // ValueInRange detect anomaly when the value returned is not inside the defined range
 type ValueInRange struct {
 	Min *float64 `json:"min"` // Min , the lower bound of the range. Default value is 0.0
@@ -215,6 +744,17 @@ type ContinuousValueDeviation struct {
 	MaxDeviationPercent *float64 `json:"maxDeviationPercent"` // MaxDeviationPercent maxDeviation computation based on % of the mean
 }
 
+// Trend detect anomaly when the values of a canary pod grow too fast. The query should return a serie per pod,
+// for example: container_memory_working_set_bytes{container="myapp"}
+type Trend struct {
+	// Limit the pod is invalid if its value projected after Horizon is greater than the limit
+	Limit *float64 `json:"limit,omitempty"`
+	// Horizon duration of the projection. Default value is 1h.
+	Horizon *metav1.Duration `json:"horizon,omitempty"`
+	// MaxSlopeDeviationPercent the canary pod is invalid if its slope is significantly steeper than the average slope of the stable pods
+	MaxSlopeDeviationPercent *float64 `json:"maxSlopeDeviationPercent,omitempty"`
+}
+
 // DiscreteValueOutOfList detect anomaly when the a value is not in the list with a ratio that exceed the tolerance
 // The promQL should return counter that are grouped by:
 // 1-the key of the value to monitor
@@ -225,6 +765,9 @@ type DiscreteValueOutOfList struct {
 	GoodValues       []string `json:"goodValues,omitempty"` // Good Values ["200","201"]. If empty means that BadValues should be used to do exclusion instead of inclusion.
 	BadValues        []string `json:"badValues,omitempty"`  // Bad Values ["500","404"].
 	TolerancePercent *uint    `json:"tolerance"`            // % of Bad values tolerated until the pod is considered out of SLA
+	// MinSamples minimum number of values (good and bad) of a pod to conclude, below the result of the pod is inconclusive.
+	// If not set, one value is enough.
+	MinSamples *uint `json:"minSamples,omitempty"`
 }
 
 // KanaryStatefulsetStatus defines the observed state of KanaryStatefulset
@@ -235,6 +778,177 @@ type KanaryStatefulsetStatus struct {
 	Conditions []KanaryStatefulsetCondition `json:"conditions,omitempty"`
 	// Report
 	Report KanaryStatefulsetStatusReport `json:"report,omitempty"`
+	// Validations represents the status of each validation item.
+	Validations []KanaryStatefulsetValidationStatus `json:"validations,omitempty"`
+	// Score represents the status of the weighted score, if spec.validations.score is set.
+	Score *KanaryStatefulsetScoreStatus `json:"score,omitempty"`
+	// Approvals audit trail of the manual approval decisions, the most recent last
+	Approvals []KanaryStatefulsetApproval `json:"approvals,omitempty"`
+	// InconclusiveExtensions is the number of extensions of the validation period due to inconclusive validation items
+	InconclusiveExtensions int32 `json:"inconclusiveExtensions,omitempty"`
+	// ValidationDeadline is the effective end of the validation period, extensions included
+	ValidationDeadline *metav1.Time `json:"validationDeadline,omitempty"`
+	// ConfidenceExtensions extensions of the validation period because the confidence criterion was not met, the most recent last
+	ConfidenceExtensions []KanaryStatefulsetValidationExtension `json:"confidenceExtensions,omitempty"`
+	// Chaos represents the status of the chaos actions started, in the order of spec.validations.chaos.actions
+	Chaos []KanaryStatefulsetChaosActionStatus `json:"chaos,omitempty"`
+}
+
+// KanaryStatefulsetChaosActionStatus defines the status of a chaos action
+type KanaryStatefulsetChaosActionStatus struct {
+	// Name of the action
+	Name string `json:"name"`
+	// Type of the action: podKill or httpFault
+	Type string `json:"type"`
+	// Target of the action: the deleted pod or the VirtualService
+	Target string `json:"target,omitempty"`
+	// TargetUID uid of the deleted pod
+	TargetUID string `json:"targetUID,omitempty"`
+	// Start time of the action, for podKill the time of the pod deletion
+	Start metav1.Time `json:"start"`
+	// End time of the action, nil while it is running
+	End *metav1.Time `json:"end,omitempty"`
+	// Outcome of the action
+	Outcome KanaryStatefulsetChaosOutcome `json:"outcome"`
+	// Message details of the outcome
+	Message string `json:"message,omitempty"`
+}
+
+// KanaryStatefulsetChaosOutcome defines the outcome of a chaos action
+type KanaryStatefulsetChaosOutcome string
+
+const (
+	// RunningKanaryStatefulsetChaosOutcome the action is in progress
+	RunningKanaryStatefulsetChaosOutcome KanaryStatefulsetChaosOutcome = "Running"
+	// SucceededKanaryStatefulsetChaosOutcome the canary survived the action
+	SucceededKanaryStatefulsetChaosOutcome KanaryStatefulsetChaosOutcome = "Succeeded"
+	// FailedKanaryStatefulsetChaosOutcome the canary did not survive the action, the KanaryStatefulset fails
+	FailedKanaryStatefulsetChaosOutcome KanaryStatefulsetChaosOutcome = "Failed"
+	// AbortedKanaryStatefulsetChaosOutcome the validation ended before the end of the action, the fault is reverted
+	AbortedKanaryStatefulsetChaosOutcome KanaryStatefulsetChaosOutcome = "Aborted"
+)
+
+// KanaryStatefulsetValidationExtension defines an extension of the validation period
+type KanaryStatefulsetValidationExtension struct {
+	// Time of the extension
+	Time metav1.Time `json:"time"`
+	// Duration added to the validation period
+	Duration metav1.Duration `json:"duration"`
+	// Reason of the extension
+	Reason string `json:"reason"`
+}
+
+// KanaryStatefulsetApproval defines a manual approval decision
+type KanaryStatefulsetApproval struct {
+	// Approver name of the user who took the decision
+	Approver string `json:"approver"`
+	// Decision valid or invalid
+	Decision KanaryStatefulsetSpecValidationManualStatus `json:"decision"`
+	// Time of the decision
+	Time metav1.Time `json:"time"`
+	// Comment provided with the decision
+	Comment string `json:"comment,omitempty"`
+	// Item name of the manual validation item for a decision taken with its status, empty for the approval annotation
+	Item string `json:"item,omitempty"`
+}
+
+// KanaryStatefulsetScoreStatus defines the observed state of the weighted score
+type KanaryStatefulsetScoreStatus struct {
+	// Extensions is the number of extensions of the validation period due to a marginal score
+	Extensions int32 `json:"extensions,omitempty"`
+	// History contains the scores of the last checks, the most recent last
+	History []KanaryStatefulsetScoreCheck `json:"history,omitempty"`
+}
+
+// KanaryStatefulsetScoreCheck score computed during a check
+type KanaryStatefulsetScoreCheck struct {
+	Time  metav1.Time `json:"time"`
+	Score float64     `json:"score"`
+}
+
+// KanaryStatefulsetValidationStatus defines the observed state of a validation item
+type KanaryStatefulsetValidationStatus struct {
+	// Index of the validation item in spec.validations.items
+	Index int `json:"index"`
+	// ConsecutiveFailures is the number of consecutive failed checks
+	ConsecutiveFailures int32 `json:"consecutiveFailures"`
+	// ConsecutiveSuccesses is the number of consecutive successful checks
+	ConsecutiveSuccesses int32 `json:"consecutiveSuccesses"`
+	// Failures is the total number of failed checks
+	Failures int32 `json:"failures"`
+	// Successes is the total number of successful checks
+	Successes int32 `json:"successes"`
+
+	// Name of the validation item (spec.validations.items[].name)
+	Name string `json:"name,omitempty"`
+	// Type of the validation item: manual, labelWatch, promQL, metrics, resourceUsage, logs, events, job, alerts or slo
+	Type string `json:"type,omitempty"`
+	// LastCheckTime is the last time the validation item has been checked
+	LastCheckTime *metav1.Time `json:"lastCheckTime,omitempty"`
+	// Values measured during the last check, by pod name
+	Values []KanaryStatefulsetValidationValue `json:"values,omitempty"`
+	// Threshold applied on the measured values during the last check
+	Threshold string `json:"threshold,omitempty"`
+	// Query is the rendered query used during the last check
+	Query string `json:"query,omitempty"`
+	// History contains the outcome of the last checks, the most recent last
+	History []KanaryStatefulsetValidationCheck `json:"history,omitempty"`
+	// Warning is the failure message of the last check of an advisory item
+	Warning string `json:"warning,omitempty"`
+	// ExcludedPods pods excluded from the anomaly detection during the last check
+	ExcludedPods []KanaryStatefulsetExcludedPod `json:"excludedPods,omitempty"`
+	// Inconclusive is true if the last check could not conclude, it is neither a success nor a failure
+	Inconclusive bool `json:"inconclusive,omitempty"`
+	// Outages periods during which the checks returned an error, with an onProviderError policy. The most recent last.
+	Outages []KanaryStatefulsetValidationOutage `json:"outages,omitempty"`
+	// Baselines values of the promQL dependencies captured before the canary start
+	Baselines []KanaryStatefulsetValidationBaseline `json:"baselines,omitempty"`
+}
+
+// KanaryStatefulsetValidationBaseline defines the baseline of a promQL dependency
+type KanaryStatefulsetValidationBaseline struct {
+	// Name of the dependency
+	Name string `json:"name"`
+	// Value of the dependency query over the baseline window
+	Value float64 `json:"value"`
+	// Time at which the query has been evaluated, the canary start
+	Time metav1.Time `json:"time"`
+}
+
+// KanaryStatefulsetValidationOutage defines a period during which the checks of a validation item returned an error
+type KanaryStatefulsetValidationOutage struct {
+	// Start time of the first failing check
+	Start metav1.Time `json:"start"`
+	// End time of the first successful check after the outage, or of the last failing check while the outage is ongoing
+	End metav1.Time `json:"end"`
+	// Ongoing is true until a check succeeds
+	Ongoing bool `json:"ongoing,omitempty"`
+	// Error returned by the last failing check
+	Error string `json:"error,omitempty"`
+}
+
+// KanaryStatefulsetExcludedPod defines a pod excluded from the anomaly detection
+type KanaryStatefulsetExcludedPod struct {
+	// Name of the pod
+	Name string `json:"name"`
+	// Reason of the exclusion
+	Reason string `json:"reason"`
+}
+
+// KanaryStatefulsetValidationValue defines a value measured during a check
+type KanaryStatefulsetValidationValue struct {
+	// Name of the pod (or of the measured item if the value is not related to a pod)
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// KanaryStatefulsetValidationCheck defines the outcome of a validation check
+type KanaryStatefulsetValidationCheck struct {
+	Time    metav1.Time `json:"time"`
+	Failed  bool        `json:"failed"`
+	Comment string      `json:"comment,omitempty"`
+	// Inconclusive is true if the check could not conclude
+	Inconclusive bool `json:"inconclusive,omitempty"`
 }
 
 type KanaryStatefulsetStatusReport struct {
@@ -242,6 +956,7 @@ type KanaryStatefulsetStatusReport struct {
 	Validation string `json:"validation,omitempty"`
 	Scale      string `json:"scale,omitempty"`
 	Traffic    string `json:"traffic,omitempty"`
+	Score      string `json:"score,omitempty"`
 }
 
 // DeploymentTemplate is the object that describes the deployment that will be created.
@@ -309,6 +1024,33 @@ const (
 	MD5KanaryStatefulsetAnnotationKey KanaryStatefulsetAnnotationKeyType = "kanary.k8s-operators.dev/md5"
 )
 
+const (
+	// KanaryStatefulsetApprovalAnnotationKey correspond to the annotation key used on a KanaryStatefulset to provide
+	// the manual approval decision: valid or invalid.
+	KanaryStatefulsetApprovalAnnotationKey = "kanary.k8s-operators.dev/approval"
+	// KanaryStatefulsetApprovalCommentAnnotationKey correspond to the annotation key used on a KanaryStatefulset to provide
+	// a comment with the manual approval decision.
+	KanaryStatefulsetApprovalCommentAnnotationKey = "kanary.k8s-operators.dev/approval-comment"
+	// KanaryStatefulsetApprovedByAnnotationKey correspond to the annotation key set by the admission webhook with the
+	// name of the user who took the manual approval decision.
+	KanaryStatefulsetApprovedByAnnotationKey = "kanary.k8s-operators.dev/approved-by"
+	// KanaryStatefulsetApprovedAtAnnotationKey correspond to the annotation key set by the admission webhook with the
+	// time (RFC3339) of the manual approval decision.
+	KanaryStatefulsetApprovedAtAnnotationKey = "kanary.k8s-operators.dev/approved-at"
+	// KanaryStatefulsetManualDecisionsAnnotationKey correspond to the annotation key set by the admission webhook with the
+	// decisions taken with the status of the manual validation items (JSON), with the user and the time of each decision.
+	KanaryStatefulsetManualDecisionsAnnotationKey = "kanary.k8s-operators.dev/manual-decisions"
+	// KanaryStatefulsetApprovalSignatureAnnotationKey correspond to the annotation key set by the admission webhook with the
+	// signature of the decisions it recorded, the kanary-controller ignores the decisions without a valid signature.
+	KanaryStatefulsetApprovalSignatureAnnotationKey = "kanary.k8s-operators.dev/approval-signature"
+	// KanaryStatefulsetChaosAnnotationKey correspond to the annotation key set on an istio VirtualService with the
+	// name of the KanaryStatefulset that injected a fault in its routes.
+	KanaryStatefulsetChaosAnnotationKey = "kanary.k8s-operators.dev/chaos"
+	// KanaryStatefulsetChaosFinalizer correspond to the finalizer set on a KanaryStatefulset while a fault is injected,
+	// in order to revert it if the KanaryStatefulset is deleted.
+	KanaryStatefulsetChaosFinalizer = "kanary.k8s-operators.dev/chaos"
+)
+
 const (
 	// KanaryStatefulsetIsKanaryLabelKey correspond to the label key used on a deployment to inform
 	// that this instance is used in a canary deployment.
@@ -6,10 +6,89 @@ package v1alpha1
 
 import (
 	v2beta1 "k8s.io/api/autoscaling/v2beta1"
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AlertMatcher) DeepCopyInto(out *AlertMatcher) {
+	*out = *in
+	if in.IsEqual != nil {
+		in, out := &in.IsEqual, &out.IsEqual
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AlertMatcher.
+func (in *AlertMatcher) DeepCopy() *AlertMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(AlertMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChaosHTTPFault) DeepCopyInto(out *ChaosHTTPFault) {
+	*out = *in
+	if in.Delay != nil {
+		in, out := &in.Delay, &out.Delay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.AbortHTTPStatus != nil {
+		in, out := &in.AbortHTTPStatus, &out.AbortHTTPStatus
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Percentage != nil {
+		in, out := &in.Percentage, &out.Percentage
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosHTTPFault.
+func (in *ChaosHTTPFault) DeepCopy() *ChaosHTTPFault {
+	if in == nil {
+		return nil
+	}
+	out := new(ChaosHTTPFault)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChaosPodKill) DeepCopyInto(out *ChaosPodKill) {
+	*out = *in
+	if in.ReadyTimeout != nil {
+		in, out := &in.ReadyTimeout, &out.ReadyTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChaosPodKill.
+func (in *ChaosPodKill) DeepCopy() *ChaosPodKill {
+	if in == nil {
+		return nil
+	}
+	out := new(ChaosPodKill)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContinuousValueDeviation) DeepCopyInto(out *ContinuousValueDeviation) {
 	*out = *in
@@ -68,6 +147,11 @@ func (in *DiscreteValueOutOfList) DeepCopyInto(out *DiscreteValueOutOfList) {
 		*out = new(uint)
 		**out = **in
 	}
+	if in.MinSamples != nil {
+		in, out := &in.MinSamples, &out.MinSamples
+		*out = new(uint)
+		**out = **in
+	}
 	return
 }
 
@@ -81,6 +165,43 @@ func (in *DiscreteValueOutOfList) DeepCopy() *DiscreteValueOutOfList {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPMetricsProvider) DeepCopyInto(out *HTTPMetricsProvider) {
+	*out = *in
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretHeaders != nil {
+		in, out := &in.SecretHeaders, &out.SecretHeaders
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.LabelPaths != nil {
+		in, out := &in.LabelPaths, &out.LabelPaths
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPMetricsProvider.
+func (in *HTTPMetricsProvider) DeepCopy() *HTTPMetricsProvider {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPMetricsProvider)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HorizontalPodAutoscalerSpec) DeepCopyInto(out *HorizontalPodAutoscalerSpec) {
 	*out = *in
@@ -137,6 +258,44 @@ func (in *KanaryStatefulset) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetApproval) DeepCopyInto(out *KanaryStatefulsetApproval) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetApproval.
+func (in *KanaryStatefulsetApproval) DeepCopy() *KanaryStatefulsetApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetChaosActionStatus) DeepCopyInto(out *KanaryStatefulsetChaosActionStatus) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	if in.End != nil {
+		in, out := &in.End, &out.End
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetChaosActionStatus.
+func (in *KanaryStatefulsetChaosActionStatus) DeepCopy() *KanaryStatefulsetChaosActionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetChaosActionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanaryStatefulsetCondition) DeepCopyInto(out *KanaryStatefulsetCondition) {
 	*out = *in
@@ -155,6 +314,22 @@ func (in *KanaryStatefulsetCondition) DeepCopy() *KanaryStatefulsetCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetExcludedPod) DeepCopyInto(out *KanaryStatefulsetExcludedPod) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetExcludedPod.
+func (in *KanaryStatefulsetExcludedPod) DeepCopy() *KanaryStatefulsetExcludedPod {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetExcludedPod)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanaryStatefulsetList) DeepCopyInto(out *KanaryStatefulsetList) {
 	*out = *in
@@ -188,6 +363,46 @@ func (in *KanaryStatefulsetList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetScoreCheck) DeepCopyInto(out *KanaryStatefulsetScoreCheck) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetScoreCheck.
+func (in *KanaryStatefulsetScoreCheck) DeepCopy() *KanaryStatefulsetScoreCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetScoreCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetScoreStatus) DeepCopyInto(out *KanaryStatefulsetScoreStatus) {
+	*out = *in
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]KanaryStatefulsetScoreCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetScoreStatus.
+func (in *KanaryStatefulsetScoreStatus) DeepCopy() *KanaryStatefulsetScoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetScoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KanaryStatefulsetSpec) DeepCopyInto(out *KanaryStatefulsetSpec) {
 	*out = *in
@@ -298,7 +513,7 @@ func (in *KanaryStatefulsetSpecValidation) DeepCopyInto(out *KanaryStatefulsetSp
 	if in.Manual != nil {
 		in, out := &in.Manual, &out.Manual
 		*out = new(KanaryStatefulsetSpecValidationManual)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.LabelWatch != nil {
 		in, out := &in.LabelWatch, &out.LabelWatch
@@ -310,6 +525,66 @@ func (in *KanaryStatefulsetSpecValidation) DeepCopyInto(out *KanaryStatefulsetSp
 		*out = new(KanaryStatefulsetSpecValidationPromQL)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(KanaryStatefulsetSpecValidationMetrics)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ResourceUsage != nil {
+		in, out := &in.ResourceUsage, &out.ResourceUsage
+		*out = new(KanaryStatefulsetSpecValidationResourceUsage)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(KanaryStatefulsetSpecValidationLogs)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = new(KanaryStatefulsetSpecValidationEvents)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(KanaryStatefulsetSpecValidationJob)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = new(KanaryStatefulsetSpecValidationAlerts)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(KanaryStatefulsetSpecValidationSLO)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureLimit != nil {
+		in, out := &in.FailureLimit, &out.FailureLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.SuccessThreshold != nil {
+		in, out := &in.SuccessThreshold, &out.SuccessThreshold
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int32)
+		**out = **in
+	}
+	if in.OnProviderError != nil {
+		in, out := &in.OnProviderError, &out.OnProviderError
+		*out = new(KanaryStatefulsetSpecValidationProviderError)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -324,52 +599,53 @@ func (in *KanaryStatefulsetSpecValidation) DeepCopy() *KanaryStatefulsetSpecVali
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetSpecValidationLabelWatch) DeepCopyInto(out *KanaryStatefulsetSpecValidationLabelWatch) {
+func (in *KanaryStatefulsetSpecValidationAlerts) DeepCopyInto(out *KanaryStatefulsetSpecValidationAlerts) {
 	*out = *in
-	if in.PodInvalidationLabels != nil {
-		in, out := &in.PodInvalidationLabels, &out.PodInvalidationLabels
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]AlertMatcher, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
-	if in.DeploymentInvalidationLabels != nil {
-		in, out := &in.DeploymentInvalidationLabels, &out.DeploymentInvalidationLabels
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.SecretHeaders != nil {
+		in, out := &in.SecretHeaders, &out.SecretHeaders
+		*out = make(map[string]corev1.SecretKeySelector, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationLabelWatch.
-func (in *KanaryStatefulsetSpecValidationLabelWatch) DeepCopy() *KanaryStatefulsetSpecValidationLabelWatch {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationAlerts.
+func (in *KanaryStatefulsetSpecValidationAlerts) DeepCopy() *KanaryStatefulsetSpecValidationAlerts {
 	if in == nil {
 		return nil
 	}
-	out := new(KanaryStatefulsetSpecValidationLabelWatch)
+	out := new(KanaryStatefulsetSpecValidationAlerts)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetSpecValidationList) DeepCopyInto(out *KanaryStatefulsetSpecValidationList) {
+func (in *KanaryStatefulsetSpecValidationChaos) DeepCopyInto(out *KanaryStatefulsetSpecValidationChaos) {
 	*out = *in
-	if in.InitialDelay != nil {
-		in, out := &in.InitialDelay, &out.InitialDelay
+	if in.StartDelay != nil {
+		in, out := &in.StartDelay, &out.StartDelay
 		*out = new(v1.Duration)
 		**out = **in
 	}
-	if in.ValidationPeriod != nil {
-		in, out := &in.ValidationPeriod, &out.ValidationPeriod
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.MaxIntervalPeriod != nil {
-		in, out := &in.MaxIntervalPeriod, &out.MaxIntervalPeriod
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]KanaryStatefulsetSpecValidation, len(*in))
+	if in.Actions != nil {
+		in, out := &in.Actions, &out.Actions
+		*out = make([]KanaryStatefulsetSpecValidationChaosAction, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -377,89 +653,703 @@ func (in *KanaryStatefulsetSpecValidationList) DeepCopyInto(out *KanaryStatefuls
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationList.
-func (in *KanaryStatefulsetSpecValidationList) DeepCopy() *KanaryStatefulsetSpecValidationList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationChaos.
+func (in *KanaryStatefulsetSpecValidationChaos) DeepCopy() *KanaryStatefulsetSpecValidationChaos {
 	if in == nil {
 		return nil
 	}
-	out := new(KanaryStatefulsetSpecValidationList)
+	out := new(KanaryStatefulsetSpecValidationChaos)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetSpecValidationManual) DeepCopyInto(out *KanaryStatefulsetSpecValidationManual) {
+func (in *KanaryStatefulsetSpecValidationChaosAction) DeepCopyInto(out *KanaryStatefulsetSpecValidationChaosAction) {
 	*out = *in
+	if in.PodKill != nil {
+		in, out := &in.PodKill, &out.PodKill
+		*out = new(ChaosPodKill)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HTTPFault != nil {
+		in, out := &in.HTTPFault, &out.HTTPFault
+		*out = new(ChaosHTTPFault)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationManual.
-func (in *KanaryStatefulsetSpecValidationManual) DeepCopy() *KanaryStatefulsetSpecValidationManual {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationChaosAction.
+func (in *KanaryStatefulsetSpecValidationChaosAction) DeepCopy() *KanaryStatefulsetSpecValidationChaosAction {
 	if in == nil {
 		return nil
 	}
-	out := new(KanaryStatefulsetSpecValidationManual)
+	out := new(KanaryStatefulsetSpecValidationChaosAction)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetSpecValidationPromQL) DeepCopyInto(out *KanaryStatefulsetSpecValidationPromQL) {
+func (in *KanaryStatefulsetSpecValidationConfidence) DeepCopyInto(out *KanaryStatefulsetSpecValidationConfidence) {
 	*out = *in
-	if in.ValueInRange != nil {
-		in, out := &in.ValueInRange, &out.ValueInRange
-		*out = new(ValueInRange)
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = new(KanaryStatefulsetSpecValidationConfidenceRequests)
 		(*in).DeepCopyInto(*out)
 	}
-	if in.DiscreteValueOutOfList != nil {
-		in, out := &in.DiscreteValueOutOfList, &out.DiscreteValueOutOfList
-		*out = new(DiscreteValueOutOfList)
-		(*in).DeepCopyInto(*out)
+	if in.ExtensionPeriod != nil {
+		in, out := &in.ExtensionPeriod, &out.ExtensionPeriod
+		*out = new(v1.Duration)
+		**out = **in
 	}
-	if in.ContinuousValueDeviation != nil {
-		in, out := &in.ContinuousValueDeviation, &out.ContinuousValueDeviation
-		*out = new(ContinuousValueDeviation)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationConfidence.
+func (in *KanaryStatefulsetSpecValidationConfidence) DeepCopy() *KanaryStatefulsetSpecValidationConfidence {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationConfidence)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationConfidenceRequests) DeepCopyInto(out *KanaryStatefulsetSpecValidationConfidenceRequests) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(PrometheusAuth)
 		(*in).DeepCopyInto(*out)
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationPromQL.
-func (in *KanaryStatefulsetSpecValidationPromQL) DeepCopy() *KanaryStatefulsetSpecValidationPromQL {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationConfidenceRequests.
+func (in *KanaryStatefulsetSpecValidationConfidenceRequests) DeepCopy() *KanaryStatefulsetSpecValidationConfidenceRequests {
 	if in == nil {
 		return nil
 	}
-	out := new(KanaryStatefulsetSpecValidationPromQL)
+	out := new(KanaryStatefulsetSpecValidationConfidenceRequests)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetStatus) DeepCopyInto(out *KanaryStatefulsetStatus) {
+func (in *KanaryStatefulsetSpecValidationEarlySuccess) DeepCopyInto(out *KanaryStatefulsetSpecValidationEarlySuccess) {
 	*out = *in
+	if in.MinElapsed != nil {
+		in, out := &in.MinElapsed, &out.MinElapsed
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(PrometheusAuth)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
-		*out = make([]KanaryStatefulsetCondition, len(*in))
+		*out = make([]KanaryStatefulsetSpecValidationEarlySuccessCondition, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.Report = in.Report
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetStatus.
-func (in *KanaryStatefulsetStatus) DeepCopy() *KanaryStatefulsetStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationEarlySuccess.
+func (in *KanaryStatefulsetSpecValidationEarlySuccess) DeepCopy() *KanaryStatefulsetSpecValidationEarlySuccess {
 	if in == nil {
 		return nil
 	}
-	out := new(KanaryStatefulsetStatus)
+	out := new(KanaryStatefulsetSpecValidationEarlySuccess)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *KanaryStatefulsetStatusReport) DeepCopyInto(out *KanaryStatefulsetStatusReport) {
+func (in *KanaryStatefulsetSpecValidationEarlySuccessCondition) DeepCopyInto(out *KanaryStatefulsetSpecValidationEarlySuccessCondition) {
+	*out = *in
+	if in.Value != nil {
+		in, out := &in.Value, &out.Value
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationEarlySuccessCondition.
+func (in *KanaryStatefulsetSpecValidationEarlySuccessCondition) DeepCopy() *KanaryStatefulsetSpecValidationEarlySuccessCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationEarlySuccessCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationEvents) DeepCopyInto(out *KanaryStatefulsetSpecValidationEvents) {
+	*out = *in
+	if in.Reasons != nil {
+		in, out := &in.Reasons, &out.Reasons
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxOccurrences != nil {
+		in, out := &in.MaxOccurrences, &out.MaxOccurrences
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationEvents.
+func (in *KanaryStatefulsetSpecValidationEvents) DeepCopy() *KanaryStatefulsetSpecValidationEvents {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationEvents)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationInconclusive) DeepCopyInto(out *KanaryStatefulsetSpecValidationInconclusive) {
+	*out = *in
+	if in.ExtensionPeriod != nil {
+		in, out := &in.ExtensionPeriod, &out.ExtensionPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxExtensions != nil {
+		in, out := &in.MaxExtensions, &out.MaxExtensions
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationInconclusive.
+func (in *KanaryStatefulsetSpecValidationInconclusive) DeepCopy() *KanaryStatefulsetSpecValidationInconclusive {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationInconclusive)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationJob) DeepCopyInto(out *KanaryStatefulsetSpecValidationJob) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationJob.
+func (in *KanaryStatefulsetSpecValidationJob) DeepCopy() *KanaryStatefulsetSpecValidationJob {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationJob)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationLabelWatch) DeepCopyInto(out *KanaryStatefulsetSpecValidationLabelWatch) {
+	*out = *in
+	if in.PodInvalidationLabels != nil {
+		in, out := &in.PodInvalidationLabels, &out.PodInvalidationLabels
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeploymentInvalidationLabels != nil {
+		in, out := &in.DeploymentInvalidationLabels, &out.DeploymentInvalidationLabels
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulSetInvalidationLabels != nil {
+		in, out := &in.StatefulSetInvalidationLabels, &out.StatefulSetInvalidationLabels
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodInvalidationAnnotations != nil {
+		in, out := &in.PodInvalidationAnnotations, &out.PodInvalidationAnnotations
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StatefulSetInvalidationAnnotations != nil {
+		in, out := &in.StatefulSetInvalidationAnnotations, &out.StatefulSetInvalidationAnnotations
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationLabelWatch.
+func (in *KanaryStatefulsetSpecValidationLabelWatch) DeepCopy() *KanaryStatefulsetSpecValidationLabelWatch {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationLabelWatch)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationList) DeepCopyInto(out *KanaryStatefulsetSpecValidationList) {
+	*out = *in
+	if in.InitialDelay != nil {
+		in, out := &in.InitialDelay, &out.InitialDelay
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ValidationPeriod != nil {
+		in, out := &in.ValidationPeriod, &out.ValidationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxIntervalPeriod != nil {
+		in, out := &in.MaxIntervalPeriod, &out.MaxIntervalPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]KanaryStatefulsetSpecValidation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Policy != nil {
+		in, out := &in.Policy, &out.Policy
+		*out = new(KanaryStatefulsetSpecValidationPolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(KanaryStatefulsetSpecValidationScore)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Inconclusive != nil {
+		in, out := &in.Inconclusive, &out.Inconclusive
+		*out = new(KanaryStatefulsetSpecValidationInconclusive)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxValidationPeriod != nil {
+		in, out := &in.MaxValidationPeriod, &out.MaxValidationPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Confidence != nil {
+		in, out := &in.Confidence, &out.Confidence
+		*out = new(KanaryStatefulsetSpecValidationConfidence)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EarlySuccess != nil {
+		in, out := &in.EarlySuccess, &out.EarlySuccess
+		*out = new(KanaryStatefulsetSpecValidationEarlySuccess)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Chaos != nil {
+		in, out := &in.Chaos, &out.Chaos
+		*out = new(KanaryStatefulsetSpecValidationChaos)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationList.
+func (in *KanaryStatefulsetSpecValidationList) DeepCopy() *KanaryStatefulsetSpecValidationList {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationLogs) DeepCopyInto(out *KanaryStatefulsetSpecValidationLogs) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxLinesPerMinute != nil {
+		in, out := &in.MaxLinesPerMinute, &out.MaxLinesPerMinute
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxIncreasePercent != nil {
+		in, out := &in.MaxIncreasePercent, &out.MaxIncreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationLogs.
+func (in *KanaryStatefulsetSpecValidationLogs) DeepCopy() *KanaryStatefulsetSpecValidationLogs {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationLogs)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationManual) DeepCopyInto(out *KanaryStatefulsetSpecValidationManual) {
+	*out = *in
+	if in.Approvers != nil {
+		in, out := &in.Approvers, &out.Approvers
+		*out = new(KanaryStatefulsetSpecValidationManualApprovers)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationManual.
+func (in *KanaryStatefulsetSpecValidationManual) DeepCopy() *KanaryStatefulsetSpecValidationManual {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationManual)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationManualApprovers) DeepCopyInto(out *KanaryStatefulsetSpecValidationManualApprovers) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationManualApprovers.
+func (in *KanaryStatefulsetSpecValidationManualApprovers) DeepCopy() *KanaryStatefulsetSpecValidationManualApprovers {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationManualApprovers)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationMetrics) DeepCopyInto(out *KanaryStatefulsetSpecValidationMetrics) {
+	*out = *in
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPMetricsProvider)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValueInRange != nil {
+		in, out := &in.ValueInRange, &out.ValueInRange
+		*out = new(ValueInRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiscreteValueOutOfList != nil {
+		in, out := &in.DiscreteValueOutOfList, &out.DiscreteValueOutOfList
+		*out = new(DiscreteValueOutOfList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContinuousValueDeviation != nil {
+		in, out := &in.ContinuousValueDeviation, &out.ContinuousValueDeviation
+		*out = new(ContinuousValueDeviation)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationMetrics.
+func (in *KanaryStatefulsetSpecValidationMetrics) DeepCopy() *KanaryStatefulsetSpecValidationMetrics {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationMetrics)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationPolicy) DeepCopyInto(out *KanaryStatefulsetSpecValidationPolicy) {
+	*out = *in
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]KanaryStatefulsetSpecValidationPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Quorum != nil {
+		in, out := &in.Quorum, &out.Quorum
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationPolicy.
+func (in *KanaryStatefulsetSpecValidationPolicy) DeepCopy() *KanaryStatefulsetSpecValidationPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationPromQL) DeepCopyInto(out *KanaryStatefulsetSpecValidationPromQL) {
+	*out = *in
+	if in.ValueInRange != nil {
+		in, out := &in.ValueInRange, &out.ValueInRange
+		*out = new(ValueInRange)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DiscreteValueOutOfList != nil {
+		in, out := &in.DiscreteValueOutOfList, &out.DiscreteValueOutOfList
+		*out = new(DiscreteValueOutOfList)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ContinuousValueDeviation != nil {
+		in, out := &in.ContinuousValueDeviation, &out.ContinuousValueDeviation
+		*out = new(ContinuousValueDeviation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Trend != nil {
+		in, out := &in.Trend, &out.Trend
+		*out = new(Trend)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RangeQuery != nil {
+		in, out := &in.RangeQuery, &out.RangeQuery
+		*out = new(PromQLRangeQuery)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(PrometheusAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Exclusions != nil {
+		in, out := &in.Exclusions, &out.Exclusions
+		*out = new(PromQLExclusions)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Dependencies != nil {
+		in, out := &in.Dependencies, &out.Dependencies
+		*out = make([]PromQLDependency, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationPromQL.
+func (in *KanaryStatefulsetSpecValidationPromQL) DeepCopy() *KanaryStatefulsetSpecValidationPromQL {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationPromQL)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationProviderError) DeepCopyInto(out *KanaryStatefulsetSpecValidationProviderError) {
+	*out = *in
+	if in.MaxOutage != nil {
+		in, out := &in.MaxOutage, &out.MaxOutage
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationProviderError.
+func (in *KanaryStatefulsetSpecValidationProviderError) DeepCopy() *KanaryStatefulsetSpecValidationProviderError {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationProviderError)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationResourceUsage) DeepCopyInto(out *KanaryStatefulsetSpecValidationResourceUsage) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		*out = new(ResourceUsageThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(ResourceUsageThreshold)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationResourceUsage.
+func (in *KanaryStatefulsetSpecValidationResourceUsage) DeepCopy() *KanaryStatefulsetSpecValidationResourceUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationResourceUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationSLO) DeepCopyInto(out *KanaryStatefulsetSpecValidationSLO) {
+	*out = *in
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(PrometheusAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Windows != nil {
+		in, out := &in.Windows, &out.Windows
+		*out = make([]SLOBurnRateWindow, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationSLO.
+func (in *KanaryStatefulsetSpecValidationSLO) DeepCopy() *KanaryStatefulsetSpecValidationSLO {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationSLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetSpecValidationScore) DeepCopyInto(out *KanaryStatefulsetSpecValidationScore) {
+	*out = *in
+	if in.Pass != nil {
+		in, out := &in.Pass, &out.Pass
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Marginal != nil {
+		in, out := &in.Marginal, &out.Marginal
+		*out = new(float64)
+		**out = **in
+	}
+	if in.ExtensionPeriod != nil {
+		in, out := &in.ExtensionPeriod, &out.ExtensionPeriod
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxExtensions != nil {
+		in, out := &in.MaxExtensions, &out.MaxExtensions
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetSpecValidationScore.
+func (in *KanaryStatefulsetSpecValidationScore) DeepCopy() *KanaryStatefulsetSpecValidationScore {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetSpecValidationScore)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetStatus) DeepCopyInto(out *KanaryStatefulsetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]KanaryStatefulsetCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.Report = in.Report
+	if in.Validations != nil {
+		in, out := &in.Validations, &out.Validations
+		*out = make([]KanaryStatefulsetValidationStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Score != nil {
+		in, out := &in.Score, &out.Score
+		*out = new(KanaryStatefulsetScoreStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approvals != nil {
+		in, out := &in.Approvals, &out.Approvals
+		*out = make([]KanaryStatefulsetApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ValidationDeadline != nil {
+		in, out := &in.ValidationDeadline, &out.ValidationDeadline
+		*out = (*in).DeepCopy()
+	}
+	if in.ConfidenceExtensions != nil {
+		in, out := &in.ConfidenceExtensions, &out.ConfidenceExtensions
+		*out = make([]KanaryStatefulsetValidationExtension, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Chaos != nil {
+		in, out := &in.Chaos, &out.Chaos
+		*out = make([]KanaryStatefulsetChaosActionStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetStatus.
+func (in *KanaryStatefulsetStatus) DeepCopy() *KanaryStatefulsetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetStatusReport) DeepCopyInto(out *KanaryStatefulsetStatusReport) {
 	*out = *in
 	return
 }
@@ -474,6 +1364,388 @@ func (in *KanaryStatefulsetStatusReport) DeepCopy() *KanaryStatefulsetStatusRepo
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationBaseline) DeepCopyInto(out *KanaryStatefulsetValidationBaseline) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationBaseline.
+func (in *KanaryStatefulsetValidationBaseline) DeepCopy() *KanaryStatefulsetValidationBaseline {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationBaseline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationCheck) DeepCopyInto(out *KanaryStatefulsetValidationCheck) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationCheck.
+func (in *KanaryStatefulsetValidationCheck) DeepCopy() *KanaryStatefulsetValidationCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationExtension) DeepCopyInto(out *KanaryStatefulsetValidationExtension) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationExtension.
+func (in *KanaryStatefulsetValidationExtension) DeepCopy() *KanaryStatefulsetValidationExtension {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationExtension)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationOutage) DeepCopyInto(out *KanaryStatefulsetValidationOutage) {
+	*out = *in
+	in.Start.DeepCopyInto(&out.Start)
+	in.End.DeepCopyInto(&out.End)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationOutage.
+func (in *KanaryStatefulsetValidationOutage) DeepCopy() *KanaryStatefulsetValidationOutage {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationOutage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationStatus) DeepCopyInto(out *KanaryStatefulsetValidationStatus) {
+	*out = *in
+	if in.LastCheckTime != nil {
+		in, out := &in.LastCheckTime, &out.LastCheckTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]KanaryStatefulsetValidationValue, len(*in))
+		copy(*out, *in)
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]KanaryStatefulsetValidationCheck, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExcludedPods != nil {
+		in, out := &in.ExcludedPods, &out.ExcludedPods
+		*out = make([]KanaryStatefulsetExcludedPod, len(*in))
+		copy(*out, *in)
+	}
+	if in.Outages != nil {
+		in, out := &in.Outages, &out.Outages
+		*out = make([]KanaryStatefulsetValidationOutage, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Baselines != nil {
+		in, out := &in.Baselines, &out.Baselines
+		*out = make([]KanaryStatefulsetValidationBaseline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationStatus.
+func (in *KanaryStatefulsetValidationStatus) DeepCopy() *KanaryStatefulsetValidationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KanaryStatefulsetValidationValue) DeepCopyInto(out *KanaryStatefulsetValidationValue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KanaryStatefulsetValidationValue.
+func (in *KanaryStatefulsetValidationValue) DeepCopy() *KanaryStatefulsetValidationValue {
+	if in == nil {
+		return nil
+	}
+	out := new(KanaryStatefulsetValidationValue)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromQLDependency) DeepCopyInto(out *PromQLDependency) {
+	*out = *in
+	if in.BaselineWindow != nil {
+		in, out := &in.BaselineWindow, &out.BaselineWindow
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxIncreasePercent != nil {
+		in, out := &in.MaxIncreasePercent, &out.MaxIncreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MinIncrease != nil {
+		in, out := &in.MinIncrease, &out.MinIncrease
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromQLDependency.
+func (in *PromQLDependency) DeepCopy() *PromQLDependency {
+	if in == nil {
+		return nil
+	}
+	out := new(PromQLDependency)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromQLExclusions) DeepCopyInto(out *PromQLExclusions) {
+	*out = *in
+	if in.WarmUp != nil {
+		in, out := &in.WarmUp, &out.WarmUp
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinRequests != nil {
+		in, out := &in.MinRequests, &out.MinRequests
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromQLExclusions.
+func (in *PromQLExclusions) DeepCopy() *PromQLExclusions {
+	if in == nil {
+		return nil
+	}
+	out := new(PromQLExclusions)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromQLRangeQuery) DeepCopyInto(out *PromQLRangeQuery) {
+	*out = *in
+	if in.Step != nil {
+		in, out := &in.Step, &out.Step
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromQLRangeQuery.
+func (in *PromQLRangeQuery) DeepCopy() *PromQLRangeQuery {
+	if in == nil {
+		return nil
+	}
+	out := new(PromQLRangeQuery)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAuth) DeepCopyInto(out *PrometheusAuth) {
+	*out = *in
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BasicAuth != nil {
+		in, out := &in.BasicAuth, &out.BasicAuth
+		*out = new(PrometheusBasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(PrometheusTLSConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAuth.
+func (in *PrometheusAuth) DeepCopy() *PrometheusAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusBasicAuth) DeepCopyInto(out *PrometheusBasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusBasicAuth.
+func (in *PrometheusBasicAuth) DeepCopy() *PrometheusBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusTLSConfig) DeepCopyInto(out *PrometheusTLSConfig) {
+	*out = *in
+	if in.CA != nil {
+		in, out := &in.CA, &out.CA
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cert != nil {
+		in, out := &in.Cert, &out.Cert
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Key != nil {
+		in, out := &in.Key, &out.Key
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusTLSConfig.
+func (in *PrometheusTLSConfig) DeepCopy() *PrometheusTLSConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusTLSConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ResourceUsageThreshold) DeepCopyInto(out *ResourceUsageThreshold) {
+	*out = *in
+	if in.MaxIncreasePercent != nil {
+		in, out := &in.MaxIncreasePercent, &out.MaxIncreasePercent
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceUsageThreshold.
+func (in *ResourceUsageThreshold) DeepCopy() *ResourceUsageThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(ResourceUsageThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SLOBurnRateWindow) DeepCopyInto(out *SLOBurnRateWindow) {
+	*out = *in
+	out.Short = in.Short
+	out.Long = in.Long
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SLOBurnRateWindow.
+func (in *SLOBurnRateWindow) DeepCopy() *SLOBurnRateWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(SLOBurnRateWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Trend) DeepCopyInto(out *Trend) {
+	*out = *in
+	if in.Limit != nil {
+		in, out := &in.Limit, &out.Limit
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Horizon != nil {
+		in, out := &in.Horizon, &out.Horizon
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MaxSlopeDeviationPercent != nil {
+		in, out := &in.MaxSlopeDeviationPercent, &out.MaxSlopeDeviationPercent
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Trend.
+func (in *Trend) DeepCopy() *Trend {
+	if in == nil {
+		return nil
+	}
+	out := new(Trend)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ValueInRange) DeepCopyInto(out *ValueInRange) {
 	*out = *in
@@ -2,3 +2,24 @@ package config
 
 // KanaryStatusSubresourceDisabledEnvVar use to know if status subresource is disabled
 const KanaryStatusSubresourceDisabledEnvVar = "KANARY_STATUS_SUBRESOURCE_DISABLED"
+
+// KanaryWebhookCertDirEnvVar use to enable the admission webhook server, the directory contains the tls.crt and tls.key files
+const KanaryWebhookCertDirEnvVar = "KANARY_WEBHOOK_CERT_DIR"
+
+// KanaryWebhookPortEnvVar use to configure the port of the admission webhook server
+const KanaryWebhookPortEnvVar = "KANARY_WEBHOOK_PORT"
+
+// KanaryPrometheusQPSEnvVar use to configure the maximum number of queries per second sent to a prometheus server
+const KanaryPrometheusQPSEnvVar = "KANARY_PROMETHEUS_QPS"
+
+// KanaryPrometheusBurstEnvVar use to configure the number of queries sent at once to a prometheus server before the rate limit applies
+const KanaryPrometheusBurstEnvVar = "KANARY_PROMETHEUS_BURST"
+
+// KanaryPrometheusMaxConcurrencyEnvVar use to configure the maximum number of queries running in parallel on a prometheus server
+const KanaryPrometheusMaxConcurrencyEnvVar = "KANARY_PROMETHEUS_MAX_CONCURRENCY"
+
+// KanaryPrometheusCacheTTLEnvVar use to configure the duration during which the result of a prometheus query is shared
+const KanaryPrometheusCacheTTLEnvVar = "KANARY_PROMETHEUS_CACHE_TTL"
+
+// KanaryPrometheusQueryTimeoutEnvVar use to configure the maximum duration of a prometheus query
+const KanaryPrometheusQueryTimeoutEnvVar = "KANARY_PROMETHEUS_QUERY_TIMEOUT"
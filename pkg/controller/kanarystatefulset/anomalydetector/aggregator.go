@@ -0,0 +1,99 @@
+package anomalydetector
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//AggregatorMax keeps the highest value
+	AggregatorMax = "max"
+	//AggregatorMin keeps the lowest value
+	AggregatorMin = "min"
+	//AggregatorAvg computes the average of the values
+	AggregatorAvg = "avg"
+	//AggregatorAny keeps the first value out of bounds if any, else the last value
+	AggregatorAny = "any"
+)
+
+//ValidateAggregator returns an error if the aggregator is not supported
+func ValidateAggregator(aggregator string) error {
+	switch aggregator {
+	case AggregatorMax, AggregatorMin, AggregatorAvg, AggregatorAny:
+		return nil
+	}
+	if _, err := parsePercentile(aggregator); err != nil {
+		return err
+	}
+	return nil
+}
+
+//aggregate reduces a list of values to a single value.
+//outOfBounds is only used by the "any" aggregator, if nil "any" behaves like "max"
+func aggregate(values []float64, aggregator string, outOfBounds func(float64) bool) (float64, error) {
+	if len(values) == 0 {
+		return 0, fmt.Errorf("no value to aggregate")
+	}
+	switch aggregator {
+	case AggregatorMax:
+		return maxValue(values), nil
+	case AggregatorMin:
+		result := values[0]
+		for _, v := range values[1:] {
+			result = math.Min(result, v)
+		}
+		return result, nil
+	case AggregatorAvg:
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		return sum / float64(len(values)), nil
+	case AggregatorAny, "":
+		if outOfBounds == nil {
+			return maxValue(values), nil
+		}
+		for _, v := range values {
+			if outOfBounds(v) {
+				return v, nil
+			}
+		}
+		return values[len(values)-1], nil
+	}
+
+	percentile, err := parsePercentile(aggregator)
+	if err != nil {
+		return 0, err
+	}
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+	// nearest-rank method
+	rank := int(math.Ceil(percentile / 100.0 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1], nil
+}
+
+func maxValue(values []float64) float64 {
+	result := values[0]
+	for _, v := range values[1:] {
+		result = math.Max(result, v)
+	}
+	return result
+}
+
+//parsePercentile parses aggregator of the form "pN" like "p95" or "p99.9"
+func parsePercentile(aggregator string) (float64, error) {
+	if !strings.HasPrefix(aggregator, "p") {
+		return 0, fmt.Errorf("unknown aggregator '%s'", aggregator)
+	}
+	percentile, err := strconv.ParseFloat(aggregator[1:], 64)
+	if err != nil || percentile <= 0 || percentile > 100 {
+		return 0, fmt.Errorf("bad percentile aggregator '%s', should be between p0 and p100", aggregator)
+	}
+	return percentile, nil
+}
@@ -0,0 +1,104 @@
+package anomalydetector
+
+import (
+	"testing"
+)
+
+func Test_aggregate(t *testing.T) {
+	outOfBounds := func(v float64) bool { return v > 10 }
+	tests := []struct {
+		name        string
+		values      []float64
+		aggregator  string
+		outOfBounds func(float64) bool
+		want        float64
+		wantErr     bool
+	}{
+		{
+			name:       "empty",
+			values:     []float64{},
+			aggregator: AggregatorMax,
+			wantErr:    true,
+		},
+		{
+			name:       "max",
+			values:     []float64{3, 12, 5},
+			aggregator: AggregatorMax,
+			want:       12,
+		},
+		{
+			name:       "min",
+			values:     []float64{3, 12, 1},
+			aggregator: AggregatorMin,
+			want:       1,
+		},
+		{
+			name:       "avg",
+			values:     []float64{3, 12, 6},
+			aggregator: AggregatorAvg,
+			want:       7,
+		},
+		{
+			name:        "any with spike",
+			values:      []float64{3, 12, 5},
+			aggregator:  AggregatorAny,
+			outOfBounds: outOfBounds,
+			want:        12,
+		},
+		{
+			name:        "any without spike",
+			values:      []float64{3, 8, 5},
+			aggregator:  AggregatorAny,
+			outOfBounds: outOfBounds,
+			want:        5,
+		},
+		{
+			name:       "any without bounds",
+			values:     []float64{3, 8, 5},
+			aggregator: AggregatorAny,
+			want:       8,
+		},
+		{
+			name:       "p50",
+			values:     []float64{5, 1, 4, 2, 3},
+			aggregator: "p50",
+			want:       3,
+		},
+		{
+			name:       "p90",
+			values:     []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10},
+			aggregator: "p90",
+			want:       9,
+		},
+		{
+			name:       "p100",
+			values:     []float64{1, 2, 3},
+			aggregator: "p100",
+			want:       3,
+		},
+		{
+			name:       "bad percentile",
+			values:     []float64{1, 2, 3},
+			aggregator: "p101",
+			wantErr:    true,
+		},
+		{
+			name:       "unknown",
+			values:     []float64{1, 2, 3},
+			aggregator: "median",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := aggregate(tt.values, tt.aggregator, tt.outOfBounds)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("aggregate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,6 +12,17 @@ type AnomalyDetector interface {
 	GetPodsOutOfBounds() ([]*kapiv1.Pod, error)
 }
 
+//ValuesReporter is implemented by the AnomalyDetectors that expose the value measured for each pod during the last analysis
+type ValuesReporter interface {
+	GetLastValues() map[string]float64
+}
+
+//InconclusiveReporter is implemented by the AnomalyDetectors that can't conclude on some pods during the last analysis,
+//for example because of a too small number of samples. These pods are neither in bounds nor out of bounds.
+type InconclusiveReporter interface {
+	GetInconclusivePods() []string
+}
+
 //Config generic part of the configuration for anomalyDetector
 type Config struct {
 	Selector      labels.Selector
@@ -21,11 +32,13 @@ type Config struct {
 }
 
 var _ AnomalyDetector = &Fake{}
+var _ ValuesReporter = &Fake{}
 
 //Fake should be used in test to mock an AnomalyDetector
 type Fake struct {
-	Pods []*kapiv1.Pod
-	Err  error
+	Pods   []*kapiv1.Pod
+	Values map[string]float64
+	Err    error
 }
 
 //GetPodsOutOfBounds implements AnomalyDetector
@@ -33,6 +46,11 @@ func (f *Fake) GetPodsOutOfBounds() ([]*kapiv1.Pod, error) {
 	return f.Pods, f.Err
 }
 
+//GetLastValues implements ValuesReporter
+func (f *Fake) GetLastValues() map[string]float64 {
+	return f.Values
+}
+
 //FakeFactory create a fake anomaly detector factory that return a Fake anomaly detector
 func FakeFactory(p []*kapiv1.Pod, e error) Factory {
 	return func(cfg FactoryConfig) (AnomalyDetector, error) {
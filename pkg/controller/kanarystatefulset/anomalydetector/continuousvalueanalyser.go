@@ -9,6 +9,7 @@ import (
 )
 
 var _ AnomalyDetector = &ContinuousValueDeviationAnalyser{}
+var _ ValuesReporter = &ContinuousValueDeviationAnalyser{}
 
 //deviationByPodName float64: 1=no deviation at all, 0.2=80% deviation down, 1.7=70% deviation up
 type deviationByPodName map[string]float64
@@ -27,6 +28,12 @@ type ContinuousValueDeviationAnalyser struct {
 	ConfigAnalyser Config
 
 	analyser continuousValueAnalyser
+	values   map[string]float64
+}
+
+//GetLastValues implements interface ValuesReporter, the value is the deviation compare to the average
+func (d *ContinuousValueDeviationAnalyser) GetLastValues() map[string]float64 {
+	return d.values
 }
 
 //GetPodsOutOfBounds implements interface AnomalyDetector
@@ -49,6 +56,7 @@ func (d *ContinuousValueDeviationAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod,
 	if err != nil {
 		return nil, err
 	}
+	d.values = expandGlobalValue(deviationByPods, podByName)
 
 	if len(deviationByPods) == 0 {
 		return result, nil
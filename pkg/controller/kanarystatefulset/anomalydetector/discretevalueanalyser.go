@@ -2,6 +2,7 @@ package anomalydetector
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/k8s-kanary/kanary/pkg/pod"
 	kapiv1 "k8s.io/api/core/v1"
@@ -18,6 +19,8 @@ type discreteValueAnalyser interface {
 }
 
 var _ AnomalyDetector = &DiscreteValueOutOfListAnalyser{}
+var _ ValuesReporter = &DiscreteValueOutOfListAnalyser{}
+var _ InconclusiveReporter = &DiscreteValueOutOfListAnalyser{}
 
 //DiscreteValueOutOfListConfig configuration for DiscreteValueOutOfListAnalyser
 type DiscreteValueOutOfListConfig struct {
@@ -25,6 +28,7 @@ type DiscreteValueOutOfListConfig struct {
 	GoodValues       []string // Good Values ["200","201"]. If empty means that BadValues should be used to do exclusion instead of inclusion.
 	BadValues        []string // Bad Values ["500","404"].
 	TolerancePercent uint
+	MinSamples       uint // Minimum number of values of a pod to conclude, below the pod is inconclusive
 	valueCheckerFunc func(value string) (ok bool)
 }
 
@@ -33,7 +37,19 @@ type DiscreteValueOutOfListAnalyser struct {
 	ConfigSpecific DiscreteValueOutOfListConfig
 	ConfigAnalyser Config
 
-	analyser discreteValueAnalyser
+	analyser     discreteValueAnalyser
+	values       map[string]float64
+	inconclusive []string
+}
+
+//GetLastValues implements interface ValuesReporter, the value is the percentage of bad values
+func (d *DiscreteValueOutOfListAnalyser) GetLastValues() map[string]float64 {
+	return d.values
+}
+
+//GetInconclusivePods implements interface InconclusiveReporter, the pods with less than MinSamples values are inconclusive
+func (d *DiscreteValueOutOfListAnalyser) GetInconclusivePods() []string {
+	return d.inconclusive
 }
 
 //GetPodsOutOfBounds implements interface AnomalyDetector
@@ -67,6 +83,8 @@ func (d *DiscreteValueOutOfListAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod, er
 		}
 	}
 
+	d.values = map[string]float64{}
+	d.inconclusive = nil
 	for podName, counter := range countersByPods {
 		_, found := podWithNoTraffic[podName]
 		if found {
@@ -75,14 +93,31 @@ func (d *DiscreteValueOutOfListAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod, er
 
 		sum := counter.ok + counter.ko
 		if sum >= 1 {
-			ratio := counter.ko * 100 / sum
-			if ratio > d.ConfigSpecific.TolerancePercent {
-				if p, ok := podByName[podName]; ok {
-					// Only keeping known pod with ratio superior to Tolerance
-					result = append(result, p)
-				}
+			d.values[podName] = float64(counter.ko) * 100 / float64(sum)
+		}
+		if sum < 1 || sum < d.ConfigSpecific.MinSamples {
+			if _, ok := podByName[podName]; ok && d.ConfigSpecific.MinSamples > 0 {
+				// Not enough values to conclude on a known pod
+				d.inconclusive = append(d.inconclusive, podName)
+			}
+			continue
+		}
+		if d.values[podName] > float64(d.ConfigSpecific.TolerancePercent) {
+			if p, ok := podByName[podName]; ok {
+				// Only keeping known pod with ratio superior to Tolerance
+				result = append(result, p)
+			}
+		}
+	}
+	if d.ConfigSpecific.MinSamples > 0 {
+		// The known pods without any value are inconclusive too
+		for podName := range podByName {
+			_, found := podWithNoTraffic[podName]
+			if _, ok := countersByPods[podName]; !ok && !found {
+				d.inconclusive = append(d.inconclusive, podName)
 			}
 		}
 	}
+	sort.Strings(d.inconclusive)
 	return result, nil
 }
@@ -18,15 +18,17 @@ func TestDiscreteValueOutOfListAnalyser_GetPodsOutOfBounds(t *testing.T) {
 
 	type fields struct {
 		TolerancePercent uint
+		MinSamples       uint
 		selector         labels.Selector
 		analyser         discreteValueAnalyser
 		podLister        kv1.PodNamespaceLister
 	}
 	tests := []struct {
-		name    string
-		fields  fields
-		want    []*kapiv1.Pod
-		wantErr bool
+		name             string
+		fields           fields
+		want             []*kapiv1.Pod
+		wantInconclusive []string
+		wantErr          bool
 	}{
 		{
 			name: "analysis error",
@@ -150,11 +152,47 @@ func TestDiscreteValueOutOfListAnalyser_GetPodsOutOfBounds(t *testing.T) {
 				test.PodGen("B", "test-ns", map[string]string{"app": "bar", "phase": "prd"}, nil, true, true)},
 			wantErr: false,
 		},
+		{
+			name: "10%, ratio not truncated",
+			fields: fields{
+				TolerancePercent: 10,
+				selector:         labels.Everything(),
+				analyser:         &testDiscreateValueAnalyser{okkoByPodName: okkoByPodName{"A": {18, 2}, "B": {17, 2}}},
+				podLister: test.NewTestPodNamespaceLister(
+					[]*kapiv1.Pod{
+						test.PodGen("A", "test-ns", map[string]string{"app": "foo", "phase": "prd"}, nil, true, true),
+						test.PodGen("B", "test-ns", map[string]string{"app": "bar", "phase": "prd"}, nil, true, true),
+					}, "test-ns"),
+			},
+			want: []*kapiv1.Pod{
+				test.PodGen("B", "test-ns", map[string]string{"app": "bar", "phase": "prd"}, nil, true, true)},
+			wantErr: false,
+		},
+		{
+			name: "10%, minSamples",
+			fields: fields{
+				TolerancePercent: 10,
+				MinSamples:       10,
+				selector:         labels.Everything(),
+				analyser:         &testDiscreateValueAnalyser{okkoByPodName: okkoByPodName{"A": {2, 1}, "B": {10, 8}, "D": {0, 0}}},
+				podLister: test.NewTestPodNamespaceLister(
+					[]*kapiv1.Pod{
+						test.PodGen("A", "test-ns", map[string]string{"app": "foo", "phase": "prd"}, nil, true, true),
+						test.PodGen("B", "test-ns", map[string]string{"app": "bar", "phase": "prd"}, nil, true, true),
+						test.PodGen("C", "test-ns", map[string]string{"app": "bar", "phase": "pdt"}, nil, true, true),
+						test.PodGen("D", "test-ns", map[string]string{"app": "bar", "phase": "pdt"}, nil, true, true),
+					}, "test-ns"),
+			},
+			want: []*kapiv1.Pod{
+				test.PodGen("B", "test-ns", map[string]string{"app": "bar", "phase": "prd"}, nil, true, true)},
+			wantInconclusive: []string{"A", "C", "D"},
+			wantErr:          false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			d := &DiscreteValueOutOfListAnalyser{
-				ConfigSpecific: DiscreteValueOutOfListConfig{TolerancePercent: tt.fields.TolerancePercent},
+				ConfigSpecific: DiscreteValueOutOfListConfig{TolerancePercent: tt.fields.TolerancePercent, MinSamples: tt.fields.MinSamples},
 				ConfigAnalyser: Config{
 					Selector:  tt.fields.selector,
 					PodLister: tt.fields.podLister,
@@ -179,6 +217,9 @@ func TestDiscreteValueOutOfListAnalyser_GetPodsOutOfBounds(t *testing.T) {
 			if !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("Got DiscreteValueOutOfListAnalyser.GetPodsOutOfBounds() = %v,\n want %v", got, tt.want)
 			}
+			if inconclusive := d.GetInconclusivePods(); !reflect.DeepEqual(inconclusive, tt.wantInconclusive) {
+				t.Errorf("DiscreteValueOutOfListAnalyser.GetInconclusivePods() = %v, want %v", inconclusive, tt.wantInconclusive)
+			}
 		})
 	}
 
@@ -10,7 +10,10 @@ type FactoryConfig struct {
 	DiscreteValueOutOfListConfig   *DiscreteValueOutOfListConfig
 	ContinuousValueDeviationConfig *ContinuousValueDeviationConfig
 	ValueInRangeConfig             *ValueInRangeConfig
+	TrendConfig                    *TrendConfig
 	PromConfig                     *ConfigPrometheusAnomalyDetector
+	HTTPConfig                     *ConfigHTTPMetricsProvider
+	ResourceUsageConfig            *ResourceUsageConfig
 	CustomService                  string
 	customFactory                  Factory //for test purpose
 }
@@ -40,16 +43,38 @@ func New(cfg FactoryConfig) (AnomalyDetector, error) {
 		}
 	}
 
+	if cfg.TrendConfig != nil {
+		if cfg.DiscreteValueOutOfListConfig != nil || cfg.ContinuousValueDeviationConfig != nil || cfg.ValueInRangeConfig != nil {
+			return nil, errMulti
+		}
+	}
+
+	if cfg.PromConfig != nil && cfg.HTTPConfig != nil {
+		return nil, errMulti
+	}
+
+	if cfg.PromConfig != nil || cfg.HTTPConfig != nil {
+		if cfg.DiscreteValueOutOfListConfig != nil || cfg.ContinuousValueDeviationConfig != nil || cfg.ValueInRangeConfig != nil || cfg.TrendConfig != nil {
+			provider, aggregator, err := newMetricsProvider(cfg)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case cfg.TrendConfig != nil:
+				return newTrendAnalyser(cfg.Config, *cfg.TrendConfig, provider)
+			case cfg.DiscreteValueOutOfListConfig != nil:
+				return newDiscreteValueOutOfList(cfg.Config, *cfg.DiscreteValueOutOfListConfig, provider, aggregator)
+			case cfg.ContinuousValueDeviationConfig != nil:
+				return newContinuousValueDeviation(cfg.Config, *cfg.ContinuousValueDeviationConfig, provider, aggregator)
+			default:
+				return newValueInRange(cfg.Config, *cfg.ValueInRangeConfig, provider, aggregator)
+			}
+		}
+	}
+
 	switch {
-	case cfg.PromConfig != nil && cfg.DiscreteValueOutOfListConfig != nil:
-		cfg.PromConfig.logger = cfg.Logger
-		return newDiscreteValueOutOfListWithProm(cfg.Config, *cfg.DiscreteValueOutOfListConfig, *cfg.PromConfig)
-	case cfg.PromConfig != nil && cfg.ContinuousValueDeviationConfig != nil:
-		cfg.PromConfig.logger = cfg.Logger
-		return newContinuousValueDeviationWithProm(cfg.Config, *cfg.ContinuousValueDeviationConfig, *cfg.PromConfig)
-	case cfg.PromConfig != nil && cfg.ValueInRangeConfig != nil:
-		cfg.PromConfig.logger = cfg.Logger
-		return newValueInRangeWithProm(cfg.Config, *cfg.ValueInRangeConfig, *cfg.PromConfig)
+	case cfg.ResourceUsageConfig != nil:
+		return newResourceUsageAnalyser(cfg.Config, *cfg.ResourceUsageConfig)
 	case cfg.CustomService != "":
 		return newCustomAnalyser(cfg.CustomService, cfg.Config)
 	case cfg.customFactory != nil:
@@ -59,6 +84,23 @@ func New(cfg FactoryConfig) (AnomalyDetector, error) {
 	}
 }
 
+//newMetricsProvider build the MetricsProvider and returns the aggregator to apply on its samples
+func newMetricsProvider(cfg FactoryConfig) (MetricsProvider, string, error) {
+	if cfg.PromConfig != nil {
+		cfg.PromConfig.logger = cfg.Logger
+		return newPromMetricsProvider(*cfg.PromConfig)
+	}
+	provider, err := newHTTPMetricsProvider(*cfg.HTTPConfig)
+	if err != nil {
+		return nil, "", err
+	}
+	aggregator := cfg.HTTPConfig.Aggregator
+	if aggregator == "" {
+		aggregator = AggregatorAny
+	}
+	return provider, aggregator, nil
+}
+
 func newCustomAnalyser(customService string, cfg Config) (*CustomAnomalyDetector, error) {
 	c := &CustomAnomalyDetector{
 		serviceURI: customService,
@@ -68,8 +110,8 @@ func newCustomAnalyser(customService string, cfg Config) (*CustomAnomalyDetector
 	return c, nil
 }
 
-//newValueInRangeWithProm buld an anomaly detector for value in range based on prometheus
-func newValueInRangeWithProm(configAnalyser Config, configValueInRange ValueInRangeConfig, configProm ConfigPrometheusAnomalyDetector) (AnomalyDetector, error) {
+//newValueInRange buld an anomaly detector for value in range based on a metrics provider
+func newValueInRange(configAnalyser Config, configValueInRange ValueInRangeConfig, provider MetricsProvider, aggregator string) (AnomalyDetector, error) {
 
 	a := &ValueInRangeAnalyser{
 		ConfigAnalyser: configAnalyser,
@@ -77,14 +119,14 @@ func newValueInRangeWithProm(configAnalyser Config, configValueInRange ValueInRa
 	}
 
 	var err error
-	if a.analyser, err = newPromValueInRangeAnalyser(configProm, configValueInRange); err != nil {
+	if a.analyser, err = newMetricsValueInRangeAnalyser(provider, aggregator, configValueInRange); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
-//newContinuousValueDeviationWithProm buld an anomaly detector for Continuous value deviation based on prometheus
-func newContinuousValueDeviationWithProm(configAnalyser Config, configContinuousValueDeviation ContinuousValueDeviationConfig, configProm ConfigPrometheusAnomalyDetector) (AnomalyDetector, error) {
+//newContinuousValueDeviation buld an anomaly detector for Continuous value deviation based on a metrics provider
+func newContinuousValueDeviation(configAnalyser Config, configContinuousValueDeviation ContinuousValueDeviationConfig, provider MetricsProvider, aggregator string) (AnomalyDetector, error) {
 
 	a := &ContinuousValueDeviationAnalyser{
 		ConfigAnalyser: configAnalyser,
@@ -92,14 +134,14 @@ func newContinuousValueDeviationWithProm(configAnalyser Config, configContinuous
 	}
 
 	var err error
-	if a.analyser, err = newPromContinuousValueDeviationAnalyser(configProm, configContinuousValueDeviation); err != nil {
+	if a.analyser, err = newMetricsContinuousValueDeviationAnalyser(provider, aggregator, configContinuousValueDeviation); err != nil {
 		return nil, err
 	}
 	return a, nil
 }
 
-//newDiscreteValueOutOfListWithProm build an anomaly detector for Discrete Value count based on prometheus
-func newDiscreteValueOutOfListWithProm(configAnalyser Config, configDiscreteValueOutOfList DiscreteValueOutOfListConfig, configProm ConfigPrometheusAnomalyDetector) (AnomalyDetector, error) {
+//newDiscreteValueOutOfList build an anomaly detector for Discrete Value count based on a metrics provider
+func newDiscreteValueOutOfList(configAnalyser Config, configDiscreteValueOutOfList DiscreteValueOutOfListConfig, provider MetricsProvider, aggregator string) (AnomalyDetector, error) {
 
 	a := &DiscreteValueOutOfListAnalyser{
 		ConfigAnalyser: configAnalyser,
@@ -107,7 +149,7 @@ func newDiscreteValueOutOfListWithProm(configAnalyser Config, configDiscreteValu
 	}
 
 	var err error
-	if a.analyser, err = newPromDiscreteValueOutOfListAnalyser(configProm, configDiscreteValueOutOfList); err != nil {
+	if a.analyser, err = newMetricsDiscreteValueOutOfListAnalyser(provider, aggregator, configDiscreteValueOutOfList); err != nil {
 		return nil, err
 	}
 	return a, nil
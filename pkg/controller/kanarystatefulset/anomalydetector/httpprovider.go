@@ -0,0 +1,212 @@
+package anomalydetector
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"time"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+//ConfigHTTPMetricsProvider configuration of the generic HTTP/JSON MetricsProvider
+type ConfigHTTPMetricsProvider struct {
+	// URL called with a GET request, the response should be a JSON document
+	URL     string
+	Headers map[string]string
+	// ItemsPath JSONPath to the list of items in the response, one item per serie
+	ItemsPath string
+	// PodNamePath JSONPath to the pod name, relative to an item
+	PodNamePath string
+	// ValuePath JSONPath to the value(s), relative to an item
+	ValuePath string
+	// LabelPaths JSONPath to additional dimensions, relative to an item, indexed by label name
+	LabelPaths map[string]string
+	// AllPodsQuery the values are applicable to all pods, PodNamePath is not used
+	AllPodsQuery bool
+	// Aggregator reduces the values of an item to one value
+	Aggregator string
+	Timeout    time.Duration
+}
+
+var _ MetricsProvider = &httpMetricsProvider{}
+
+//httpMetricsProvider MetricsProvider calling an HTTP endpoint and extracting the samples with JSONPath
+type httpMetricsProvider struct {
+	config  ConfigHTTPMetricsProvider
+	client  *http.Client
+	items   *jsonPathField
+	podName *jsonPathField
+	value   *jsonPathField
+	labels  map[string]*jsonPathField
+}
+
+//jsonPathField parsed JSONPath and its name for the error messages
+type jsonPathField struct {
+	name string
+	path *jsonpath.JSONPath
+}
+
+func newHTTPMetricsProvider(config ConfigHTTPMetricsProvider) (*httpMetricsProvider, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("the http metrics provider url is not defined")
+	}
+	if config.Aggregator != "" {
+		if err := ValidateAggregator(config.Aggregator); err != nil {
+			return nil, err
+		}
+	}
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 10 * time.Second
+	}
+
+	p := &httpMetricsProvider{
+		config: config,
+		client: &http.Client{Timeout: timeout},
+		labels: map[string]*jsonPathField{},
+	}
+	var err error
+	if p.items, err = parseJSONPath("itemsPath", config.ItemsPath); err != nil {
+		return nil, err
+	}
+	if p.value, err = parseJSONPath("valuePath", config.ValuePath); err != nil {
+		return nil, err
+	}
+	if !config.AllPodsQuery {
+		if p.podName, err = parseJSONPath("podNamePath", config.PodNamePath); err != nil {
+			return nil, err
+		}
+	}
+	for name, path := range config.LabelPaths {
+		if p.labels[name], err = parseJSONPath(name, path); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+//GetSamples implements MetricsProvider
+func (p *httpMetricsProvider) GetSamples() ([]Sample, error) {
+	request, err := http.NewRequest(http.MethodGet, p.config.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the metrics request: %v", err)
+	}
+	for k, v := range p.config.Headers {
+		request.Header.Set(k, v)
+	}
+	response, err := p.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error while contacting the metrics server: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the metrics server did not respond Ok (200) but %d", response.StatusCode)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response buffer %v", err)
+	}
+	var data interface{}
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, fmt.Errorf("decoding metrics server response failed: %v", err)
+	}
+	return p.samplesFromData(data)
+}
+
+func (p *httpMetricsProvider) samplesFromData(data interface{}) ([]Sample, error) {
+	items, err := findJSONPath(p.items, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []Sample{}
+	for _, item := range items {
+		sample := Sample{PodName: GlobalQueryKey, Labels: map[string]string{}}
+		if !p.config.AllPodsQuery {
+			if sample.PodName, err = findJSONPathString(p.podName, item); err != nil {
+				return nil, err
+			}
+		}
+		for name, path := range p.labels {
+			if sample.Labels[name], err = findJSONPathString(path, item); err != nil {
+				return nil, err
+			}
+		}
+		values, err := findJSONPath(p.value, item)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range values {
+			value, err := toFloat(v)
+			if err != nil {
+				return nil, err
+			}
+			sample.Values = append(sample.Values, value)
+		}
+		if len(sample.Values) == 0 {
+			continue
+		}
+		result = append(result, sample)
+	}
+	return result, nil
+}
+
+func parseJSONPath(name, path string) (*jsonPathField, error) {
+	if path == "" {
+		return nil, fmt.Errorf("the jsonpath %s is not defined", name)
+	}
+	j := jsonpath.New(name)
+	if err := j.Parse(path); err != nil {
+		return nil, fmt.Errorf("unable to parse the jsonpath %s=%s: %v", name, path, err)
+	}
+	return &jsonPathField{name: name, path: j}, nil
+}
+
+//findJSONPath returns all the values matching the JSONPath, the matching arrays are flattened
+func findJSONPath(j *jsonPathField, data interface{}) ([]interface{}, error) {
+	results, err := j.path.FindResults(data)
+	if err != nil {
+		return nil, fmt.Errorf("jsonpath %s: %v", j.name, err)
+	}
+	values := []interface{}{}
+	for _, result := range results {
+		for _, r := range result {
+			v := r.Interface()
+			if list, ok := v.([]interface{}); ok {
+				values = append(values, list...)
+				continue
+			}
+			values = append(values, v)
+		}
+	}
+	return values, nil
+}
+
+func findJSONPathString(j *jsonPathField, data interface{}) (string, error) {
+	values, err := findJSONPath(j, data)
+	if err != nil {
+		return "", err
+	}
+	if len(values) != 1 {
+		return "", fmt.Errorf("jsonpath %s: expected one value, found %d", j.name, len(values))
+	}
+	return fmt.Sprintf("%v", values[0]), nil
+}
+
+func toFloat(v interface{}) (float64, error) {
+	switch value := v.(type) {
+	case float64:
+		return value, nil
+	case string:
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return 0, fmt.Errorf("the value %q is not a number", value)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("the value %v is not a number: %T", v, v)
+	}
+}
@@ -0,0 +1,156 @@
+package anomalydetector
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func Test_httpMetricsProvider_GetSamples(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ConfigHTTPMetricsProvider
+		body    string
+		status  int
+		want    []Sample
+		wantErr bool
+	}{
+		{
+			name: "one value per pod",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:   "{.series[*]}",
+				PodNamePath: "{.tags.pod}",
+				ValuePath:   "{.value}",
+				LabelPaths:  map[string]string{"code": "{.tags.code}"},
+			},
+			body: `{"series":[{"tags":{"pod":"foo-0","code":"200"},"value":0.5},{"tags":{"pod":"foo-1","code":"500"},"value":"1.5"}]}`,
+			want: []Sample{
+				{PodName: "foo-0", Labels: map[string]string{"code": "200"}, Values: []float64{0.5}},
+				{PodName: "foo-1", Labels: map[string]string{"code": "500"}, Values: []float64{1.5}},
+			},
+		},
+		{
+			name: "several values, all pods",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:    "{.results[*]}",
+				ValuePath:    "{.values[*][1]}",
+				AllPodsQuery: true,
+			},
+			body: `{"results":[{"values":[[1556000000,1],[1556000015,3]]}]}`,
+			want: []Sample{
+				{PodName: GlobalQueryKey, Labels: map[string]string{}, Values: []float64{1, 3}},
+			},
+		},
+		{
+			name: "value not a number",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:   "{.series[*]}",
+				PodNamePath: "{.pod}",
+				ValuePath:   "{.value}",
+			},
+			body:    `{"series":[{"pod":"foo-0","value":"NaN?"}]}`,
+			wantErr: true,
+		},
+		{
+			name: "missing pod name",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:   "{.series[*]}",
+				PodNamePath: "{.pod}",
+				ValuePath:   "{.value}",
+			},
+			body:    `{"series":[{"value":1}]}`,
+			wantErr: true,
+		},
+		{
+			name: "server error",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:   "{.series[*]}",
+				PodNamePath: "{.pod}",
+				ValuePath:   "{.value}",
+			},
+			status:  http.StatusInternalServerError,
+			wantErr: true,
+		},
+		{
+			name: "bad json",
+			config: ConfigHTTPMetricsProvider{
+				ItemsPath:   "{.series[*]}",
+				PodNamePath: "{.pod}",
+				ValuePath:   "{.value}",
+			},
+			body:    `{"series":`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Token") != "secret" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			tt.config.URL = server.URL
+			tt.config.Headers = map[string]string{"X-Token": "secret"}
+			p, err := newHTTPMetricsProvider(tt.config)
+			if err != nil {
+				t.Fatalf("newHTTPMetricsProvider() error = %v", err)
+			}
+			got, err := p.GetSamples()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("httpMetricsProvider.GetSamples() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("httpMetricsProvider.GetSamples() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newHTTPMetricsProvider(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ConfigHTTPMetricsProvider
+		wantErr bool
+	}{
+		{
+			name:   "ok",
+			config: ConfigHTTPMetricsProvider{URL: "http://metrics", ItemsPath: "{.items[*]}", PodNamePath: "{.pod}", ValuePath: "{.value}"},
+		},
+		{
+			name:    "missing url",
+			config:  ConfigHTTPMetricsProvider{ItemsPath: "{.items[*]}", PodNamePath: "{.pod}", ValuePath: "{.value}"},
+			wantErr: true,
+		},
+		{
+			name:    "missing pod name path",
+			config:  ConfigHTTPMetricsProvider{URL: "http://metrics", ItemsPath: "{.items[*]}", ValuePath: "{.value}"},
+			wantErr: true,
+		},
+		{
+			name:   "missing pod name path, all pods",
+			config: ConfigHTTPMetricsProvider{URL: "http://metrics", ItemsPath: "{.items[*]}", ValuePath: "{.value}", AllPodsQuery: true},
+		},
+		{
+			name:    "bad jsonpath",
+			config:  ConfigHTTPMetricsProvider{URL: "http://metrics", ItemsPath: "{.items[*]", PodNamePath: "{.pod}", ValuePath: "{.value}"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := newHTTPMetricsProvider(tt.config); (err != nil) != tt.wantErr {
+				t.Errorf("newHTTPMetricsProvider() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,176 @@
+package anomalydetector
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+//Sample values returned by a MetricsProvider for one serie
+type Sample struct {
+	// PodName is GlobalQueryKey if the value applies to all pods, empty if the pod dimension is missing
+	PodName string
+	// Labels dimensions of the serie
+	Labels map[string]string
+	// Values samples of the serie, the most recent last
+	Values []float64
+	// Timestamps time of each value, optional: only set by the providers that know it
+	Timestamps []time.Time
+}
+
+//MetricsProvider returns the samples used by the analysers
+type MetricsProvider interface {
+	GetSamples() ([]Sample, error)
+}
+
+//podName returns the pod name of the sample, or an error if the pod dimension is missing
+func (s *Sample) podName() (string, error) {
+	if s.PodName == "" {
+		return "", fmt.Errorf("The metric returned is missing the podName dimension, while the query is not marked to be global")
+	}
+	return s.PodName, nil
+}
+
+//===== DiscreteValueOutOfListAnalyser =====
+
+type metricsDiscreteValueOutOfListAnalyser struct {
+	provider   MetricsProvider
+	aggregator string
+	config     DiscreteValueOutOfListConfig
+}
+
+func newMetricsDiscreteValueOutOfListAnalyser(provider MetricsProvider, aggregator string, config DiscreteValueOutOfListConfig) (*metricsDiscreteValueOutOfListAnalyser, error) {
+	if err := ValidateAggregator(aggregator); err != nil {
+		return nil, err
+	}
+	good, bad := config.GoodValues, config.BadValues
+	valueCheckerFunc := func(value string) bool { return ContainsString(good, value) }
+	if len(good) == 0 && len(bad) != 0 {
+		valueCheckerFunc = func(value string) bool { return !ContainsString(bad, value) }
+	}
+	config.valueCheckerFunc = valueCheckerFunc
+
+	return &metricsDiscreteValueOutOfListAnalyser{provider: provider, aggregator: aggregator, config: config}, nil
+}
+
+func (p *metricsDiscreteValueOutOfListAnalyser) doAnalysis() (okkoByPodName, error) {
+	// promQL example: sum(delta(ms_rpc_count{job=\"kubernetes-pods\",run=\"foo\"}[10s])) by (code,kubernetes_pod_name)
+	// the pod name is read from "kubernetes_pod_name", p.config.Key should be "code"
+	samples, err := p.provider.GetSamples()
+	if err != nil {
+		return nil, err
+	}
+	return p.buildCounters(samples)
+}
+
+func (p *metricsDiscreteValueOutOfListAnalyser) buildCounters(samples []Sample) (okkoByPodName, error) {
+	countersByPods := okkoByPodName{}
+
+	for _, sample := range samples {
+		podName, err := sample.podName()
+		if err != nil {
+			continue // TODO: this analyser does not fail when problem with podKeyName. To Fix
+		}
+		value, err := aggregate(sample.Values, p.aggregator, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		counters := countersByPods[podName]
+
+		discreteValue := sample.Labels[p.config.Key]
+		if p.config.valueCheckerFunc(discreteValue) {
+			counters.ok += uint(value)
+		} else {
+			counters.ko += uint(value)
+		}
+		countersByPods[podName] = counters
+	}
+	return countersByPods, nil
+}
+
+//===== ContinuousValueDeviationAnalyser =====
+
+type metricsContinuousValueDeviationAnalyser struct {
+	provider   MetricsProvider
+	aggregator string
+	config     ContinuousValueDeviationConfig
+}
+
+func newMetricsContinuousValueDeviationAnalyser(provider MetricsProvider, aggregator string, config ContinuousValueDeviationConfig) (*metricsContinuousValueDeviationAnalyser, error) {
+	if err := ValidateAggregator(aggregator); err != nil {
+		return nil, err
+	}
+	return &metricsContinuousValueDeviationAnalyser{provider: provider, aggregator: aggregator, config: config}, nil
+}
+
+func (p *metricsContinuousValueDeviationAnalyser) doAnalysis() (deviationByPodName, error) {
+	// promQL example: (rate(solution_price_sum{}[1m])/rate(solution_price_count{}[1m]) and delta(solution_price_count{}[1m])>70) / scalar(sum(rate(solution_price_sum{}[1m]))/sum(rate(solution_price_count{}[1m])))
+	samples, err := p.provider.GetSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	maxDeviation := p.config.MaxDeviationPercent / 100.0
+	outOfBounds := func(deviation float64) bool { return math.Abs(1-deviation) > maxDeviation }
+
+	result := deviationByPodName{}
+	for _, sample := range samples {
+		podName, err := sample.podName()
+		if err != nil {
+			return nil, err
+		}
+		deviation, err := aggregate(sample.Values, p.aggregator, outOfBounds)
+		if err != nil {
+			return nil, err
+		}
+		result[podName] = deviation
+	}
+	return result, nil
+}
+
+//===== ValueInRangeAnalyser =====
+
+type metricsValueInRangeAnalyser struct {
+	provider   MetricsProvider
+	aggregator string
+	config     ValueInRangeConfig
+	values     map[string]float64
+}
+
+func newMetricsValueInRangeAnalyser(provider MetricsProvider, aggregator string, config ValueInRangeConfig) (*metricsValueInRangeAnalyser, error) {
+	if err := ValidateAggregator(aggregator); err != nil {
+		return nil, err
+	}
+	return &metricsValueInRangeAnalyser{provider: provider, aggregator: aggregator, config: config}, nil
+}
+
+func (p *metricsValueInRangeAnalyser) doAnalysis() (inRangeByPodName, error) {
+	// promQL example: (rate(solution_price_sum{}[1m])/rate(solution_price_count{}[1m]) and delta(solution_price_count{}[1m])>70) / scalar(sum(rate(solution_price_sum{}[1m]))/sum(rate(solution_price_count{}[1m])))
+	samples, err := p.provider.GetSamples()
+	if err != nil {
+		return nil, err
+	}
+
+	inRange := func(value float64) bool { return value >= p.config.Min && value <= p.config.Max }
+
+	result := inRangeByPodName{}
+	p.values = map[string]float64{}
+	for _, sample := range samples {
+		podName, err := sample.podName()
+		if err != nil {
+			return nil, err
+		}
+		value, err := aggregate(sample.Values, p.aggregator, func(v float64) bool { return !inRange(v) })
+		if err != nil {
+			return nil, err
+		}
+		result[podName] = inRange(value)
+		p.values[podName] = value
+	}
+	return result, nil
+}
+
+func (p *metricsValueInRangeAnalyser) lastValues() map[string]float64 {
+	return p.values
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
-	promClient "github.com/prometheus/client_golang/api"
 	promApi "github.com/prometheus/client_golang/api/prometheus/v1"
 
 	"github.com/prometheus/common/model"
@@ -22,183 +21,137 @@ type ConfigPrometheusAnomalyDetector struct {
 	PodNameKey        string
 	AllPodsQuery      bool
 	Query             string
+	Range             *RangeQueryConfig
+	Time              time.Time //evaluation time of the instant query, now if zero
+	URL               string
+	Auth              *PrometheusAuthConfig
 	queryAPI          promApi.API
 	logger            logr.Logger
 }
 
-//===== DiscreteValueOutOfListAnalyser =====
-
-type promDiscreteValueOutOfListAnalyser struct {
-	promConfig ConfigPrometheusAnomalyDetector
-	config     DiscreteValueOutOfListConfig
+//RangeQueryConfig configuration to evaluate the query over a time range instead of an instant
+type RangeQueryConfig struct {
+	Start      time.Time
+	Step       time.Duration
+	Aggregator string
 }
 
-func (p *promDiscreteValueOutOfListAnalyser) doAnalysis() (okkoByPodName, error) {
-	ctx := context.Background()
-	tsNow := time.Now()
-
-	// promQL example: sum(delta(ms_rpc_count{job=\"kubernetes-pods\",run=\"foo\"}[10s])) by (code,kubernetes_pod_name)
-	// p.config.PodNameKey should be "kubernetes_pod_name"
-	// p.config.Key should be "code"
-	m, err := p.promConfig.queryAPI.Query(ctx, p.promConfig.Query, tsNow)
-	if err != nil {
-		return nil, fmt.Errorf("error processing prometheus query: %s", err)
-	}
+var _ MetricsProvider = &promMetricsProvider{}
 
-	vector, ok := m.(model.Vector)
-	if !ok {
-		return nil, fmt.Errorf("the prometheus query did not return a result in the form of expected type 'model.Vector': %s", err)
-	}
-
-	return p.buildCounters(vector), nil
+//promMetricsProvider MetricsProvider backed by prometheus
+type promMetricsProvider struct {
+	config ConfigPrometheusAnomalyDetector
 }
 
-func (p *promDiscreteValueOutOfListAnalyser) buildCounters(vector model.Vector) okkoByPodName {
-	countersByPods := okkoByPodName{}
-
-	for _, sample := range vector {
-		metrics := sample.Metric
-		podName, err := extractPodNameFromMetric(metrics, p.promConfig)
-		if err != nil {
-			continue // TODO: this analyser does not fail when problem with podKeyName. To Fix
-		}
-
-		counters := countersByPods[podName]
-
-		discreteValue := metrics[model.LabelName(p.config.Key)]
-		if p.config.valueCheckerFunc(string(discreteValue)) {
-			counters.ok += uint(sample.Value)
-		} else {
-			counters.ko += uint(sample.Value)
-		}
-		countersByPods[podName] = counters
-	}
-	return countersByPods
-}
-
-func newPromDiscreteValueOutOfListAnalyser(promConfig ConfigPrometheusAnomalyDetector, config DiscreteValueOutOfListConfig) (*promDiscreteValueOutOfListAnalyser, error) {
-	good, bad := config.GoodValues, config.BadValues
-	valueCheckerFunc := func(value string) bool { return ContainsString(good, value) }
-	if len(good) == 0 && len(bad) != 0 {
-		valueCheckerFunc = func(value string) bool { return !ContainsString(bad, value) }
+//newPromMetricsProvider returns a MetricsProvider backed by prometheus and the aggregator of the range query
+func newPromMetricsProvider(promConfig ConfigPrometheusAnomalyDetector) (*promMetricsProvider, string, error) {
+	if err := promConfig.validate(); err != nil {
+		return nil, "", err
 	}
-
-	config.valueCheckerFunc = valueCheckerFunc
-	promconfig := promClient.Config{Address: "http://" + promConfig.PrometheusService}
-	prometheusClient, err := promClient.NewClient(promconfig)
+	queryAPI, err := prometheusPool.getQueryAPI(&promConfig)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	promConfig.queryAPI = promApi.NewAPI(prometheusClient)
-
-	return &promDiscreteValueOutOfListAnalyser{config: config, promConfig: promConfig}, nil
-}
-
-//===== ContinuousValueDeviationAnalyser =====
-
-type promContinuousValueDeviationAnalyser struct {
-	promConfig ConfigPrometheusAnomalyDetector
-	config     ContinuousValueDeviationConfig
+	promConfig.queryAPI = queryAPI
+	return &promMetricsProvider{config: promConfig}, promConfig.aggregator(), nil
 }
 
-//newPromContinuousValueDeviationAnalyser new amnalyser for ContinuousValueDeviation backed by prometheus
-func newPromContinuousValueDeviationAnalyser(promConfig ConfigPrometheusAnomalyDetector, config ContinuousValueDeviationConfig) (*promContinuousValueDeviationAnalyser, error) {
-
-	promconfig := promClient.Config{Address: "http://" + promConfig.PrometheusService}
-	prometheusClient, err := promClient.NewClient(promconfig)
+//NewPrometheusMetricsProvider returns a MetricsProvider running the promQL query of the configuration
+func NewPrometheusMetricsProvider(promConfig ConfigPrometheusAnomalyDetector) (MetricsProvider, error) {
+	provider, _, err := newPromMetricsProvider(promConfig)
 	if err != nil {
 		return nil, err
 	}
-	promConfig.queryAPI = promApi.NewAPI(prometheusClient)
-	return &promContinuousValueDeviationAnalyser{promConfig: promConfig, config: config}, nil
+	return provider, nil
 }
 
-func (p *promContinuousValueDeviationAnalyser) doAnalysis() (deviationByPodName, error) {
+//GetSamples implements MetricsProvider. It runs the promQL query and returns the values of each serie.
+//Vector and Scalar results contain one value per serie, Matrix results contain all the samples of the range
+func (p *promMetricsProvider) GetSamples() ([]Sample, error) {
 	ctx := context.Background()
 	tsNow := time.Now()
 
-	// promQL example: (rate(solution_price_sum{}[1m])/rate(solution_price_count{}[1m]) and delta(solution_price_count{}[1m])>70) / scalar(sum(rate(solution_price_sum{}[1m]))/sum(rate(solution_price_count{}[1m])))
-	// p.PodNameKey should point to the label containing the pod name (if the query is not for all pods)
-	m, err := p.promConfig.queryAPI.Query(ctx, p.promConfig.Query, tsNow)
+	var m model.Value
+	var err error
+	if p.config.Range != nil {
+		m, err = p.config.queryAPI.QueryRange(ctx, p.config.Query, promApi.Range{Start: p.config.Range.Start, End: tsNow, Step: p.config.Range.Step})
+	} else {
+		ts := tsNow
+		if !p.config.Time.IsZero() {
+			ts = p.config.Time
+		}
+		m, err = p.config.queryAPI.Query(ctx, p.config.Query, ts)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("error processing prometheus query: %s", err)
 	}
+	return p.samplesFromValue(m)
+}
 
-	vector, ok := m.(model.Vector)
-	if !ok {
-		return nil, fmt.Errorf("the prometheus query did not return a result in the form of expected type 'model.Vector': %s", err)
-	}
-
-	result := deviationByPodName{}
-	for _, sample := range vector {
-		podName, err := extractPodNameFromMetric(sample.Metric, p.promConfig)
-		if err != nil {
-			return nil, err
+func (p *promMetricsProvider) samplesFromValue(m model.Value) ([]Sample, error) {
+	result := []Sample{}
+	switch v := m.(type) {
+	case model.Vector:
+		for _, sample := range v {
+			result = append(result, p.newSample(sample.Metric, []float64{float64(sample.Value)}, []time.Time{sample.Timestamp.Time()}))
 		}
-
-		deviation := sample.Value
-		result[podName] = float64(deviation)
+	case model.Matrix:
+		for _, stream := range v {
+			if len(stream.Values) == 0 {
+				continue
+			}
+			values := []float64{}
+			timestamps := []time.Time{}
+			for _, pair := range stream.Values {
+				values = append(values, float64(pair.Value))
+				timestamps = append(timestamps, pair.Timestamp.Time())
+			}
+			result = append(result, p.newSample(stream.Metric, values, timestamps))
+		}
+	case *model.Scalar:
+		if v != nil {
+			result = append(result, p.newSample(model.Metric{}, []float64{float64(v.Value)}, []time.Time{v.Timestamp.Time()}))
+		}
+	default:
+		return nil, fmt.Errorf("the prometheus query did not return a result in the form of expected type 'model.Vector', 'model.Matrix' or 'model.Scalar': %T", m)
 	}
 	return result, nil
 }
 
-// ===== ValueInRangeAnalyser =====
-
-type promValueInRangeAnalyser struct {
-	promConfig ConfigPrometheusAnomalyDetector
-	config     ValueInRangeConfig
-}
-
-//newPromValueInRangeAnalyser new amnalyser for ValueInRange backed by prometheus
-func newPromValueInRangeAnalyser(promConfig ConfigPrometheusAnomalyDetector, config ValueInRangeConfig) (*promValueInRangeAnalyser, error) {
-
-	promconfig := promClient.Config{Address: "http://" + promConfig.PrometheusService}
-	prometheusClient, err := promClient.NewClient(promconfig)
-	if err != nil {
-		return nil, err
+func (p *promMetricsProvider) newSample(metric model.Metric, values []float64, timestamps []time.Time) Sample {
+	labels := map[string]string{}
+	for k, v := range metric {
+		labels[string(k)] = string(v)
+	}
+	return Sample{
+		PodName:    extractPodNameFromMetric(metric, p.config),
+		Labels:     labels,
+		Values:     values,
+		Timestamps: timestamps,
 	}
-	promConfig.queryAPI = promApi.NewAPI(prometheusClient)
-	return &promValueInRangeAnalyser{promConfig: promConfig, config: config}, nil
 }
 
-func (p *promValueInRangeAnalyser) doAnalysis() (inRangeByPodName, error) {
-	ctx := context.Background()
-	tsNow := time.Now()
-
-	// promQL example: (rate(solution_price_sum{}[1m])/rate(solution_price_count{}[1m]) and delta(solution_price_count{}[1m])>70) / scalar(sum(rate(solution_price_sum{}[1m]))/sum(rate(solution_price_count{}[1m])))
-	// p.PodNameKey should point to the label containing the pod name (if the query is not for all pods)
-	m, err := p.promConfig.queryAPI.Query(ctx, p.promConfig.Query, tsNow)
-	if err != nil {
-		return nil, fmt.Errorf("error processing prometheus query: %s", err)
+//aggregator returns the aggregator of the range configuration
+func (c *ConfigPrometheusAnomalyDetector) aggregator() string {
+	if c.Range != nil && c.Range.Aggregator != "" {
+		return c.Range.Aggregator
 	}
+	return AggregatorAny
+}
 
-	vector, ok := m.(model.Vector)
-	if !ok {
-		return nil, fmt.Errorf("the prometheus query did not return a result in the form of expected type 'model.Vector': %s", err)
+func (c *ConfigPrometheusAnomalyDetector) validate() error {
+	if c.Range == nil {
+		return nil
 	}
-
-	result := inRangeByPodName{}
-	for _, sample := range vector {
-		podName, err := extractPodNameFromMetric(sample.Metric, p.promConfig)
-		if err != nil {
-			return nil, err
-		}
-		if float64(sample.Value) >= p.config.Min && float64(sample.Value) <= p.config.Max {
-			result[podName] = true
-		} else {
-			result[podName] = false
-		}
+	if c.Range.Step <= 0 {
+		return fmt.Errorf("the range query step should be greater than 0")
 	}
-	return result, nil
+	return ValidateAggregator(c.Range.Aggregator)
 }
 
-func extractPodNameFromMetric(metrics model.Metric, promConfig ConfigPrometheusAnomalyDetector) (string, error) {
-	podName := string(metrics[model.LabelName(promConfig.PodNameKey)])
+func extractPodNameFromMetric(metrics model.Metric, promConfig ConfigPrometheusAnomalyDetector) string {
 	if promConfig.AllPodsQuery {
-		podName = GlobalQueryKey
-	}
-	if podName == "" && !promConfig.AllPodsQuery {
-		return "", fmt.Errorf("The metric returned is missing the podName dimension '%s', while the query is not marked to be global", podName)
+		return GlobalQueryKey
 	}
-	return podName, nil
+	return string(metrics[model.LabelName(promConfig.PodNameKey)])
 }
@@ -143,14 +143,21 @@ func Test_promDiscreteValueOutOfListAnalyser_buildCounters(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &promDiscreteValueOutOfListAnalyser{
-				config: tt.fields.config,
-				promConfig: ConfigPrometheusAnomalyDetector{
+			provider := &promMetricsProvider{
+				config: ConfigPrometheusAnomalyDetector{
 					PodNameKey: tt.fields.PodNameKey,
 					logger:     logf.Log,
 				},
 			}
-			if got := p.buildCounters(tt.args.vector); !reflect.DeepEqual(got, tt.want) {
+			samples, err := provider.samplesFromValue(tt.args.vector)
+			if err != nil {
+				t.Fatalf("promMetricsProvider.samplesFromValue() error = %v", err)
+			}
+			p := &metricsDiscreteValueOutOfListAnalyser{
+				config:     tt.fields.config,
+				aggregator: AggregatorAny,
+			}
+			if got, _ := p.buildCounters(samples); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("promDiscreteValueOutOfListAnalyser.buildCounters() = %v, want %v", got, tt.want)
 			}
 		})
@@ -211,13 +218,16 @@ func Test_promDiscreteValueOutOfListAnalyser_doAnalysis(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &promDiscreteValueOutOfListAnalyser{
+			p := &metricsDiscreteValueOutOfListAnalyser{
 				config: tt.fields.config,
-				promConfig: ConfigPrometheusAnomalyDetector{
-					PodNameKey: tt.fields.PodNameKey,
-					queryAPI:   tt.fields.qAPI,
-					logger:     logf.Log,
+				provider: &promMetricsProvider{
+					config: ConfigPrometheusAnomalyDetector{
+						PodNameKey: tt.fields.PodNameKey,
+						queryAPI:   tt.fields.qAPI,
+						logger:     logf.Log,
+					},
 				},
+				aggregator: AggregatorAny,
 			}
 			got, err := p.doAnalysis()
 			if (err != nil) != tt.wantErr {
@@ -349,13 +359,16 @@ func Test_promContinuousValueDeviationAnalyser_doAnalysis(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			p := &promContinuousValueDeviationAnalyser{
+			p := &metricsContinuousValueDeviationAnalyser{
 				config: tt.fields.config,
-				promConfig: ConfigPrometheusAnomalyDetector{
-					PodNameKey: tt.fields.PodNameKey,
-					queryAPI:   tt.fields.qAPI,
-					logger:     logf.Log,
+				provider: &promMetricsProvider{
+					config: ConfigPrometheusAnomalyDetector{
+						PodNameKey: tt.fields.PodNameKey,
+						queryAPI:   tt.fields.qAPI,
+						logger:     logf.Log,
+					},
 				},
+				aggregator: AggregatorAny,
 			}
 			got, err := p.doAnalysis()
 			if (err != nil) != tt.wantErr {
@@ -368,3 +381,137 @@ func Test_promContinuousValueDeviationAnalyser_doAnalysis(t *testing.T) {
 		})
 	}
 }
+
+func Test_promValueInRangeAnalyser_doAnalysis(t *testing.T) {
+	type fields struct {
+		config       ValueInRangeConfig
+		PodNameKey   string
+		AllPodsQuery bool
+		Range        *RangeQueryConfig
+		qAPI         promApi.API
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    inRangeByPodName
+		wantErr bool
+	}{
+		{
+			name: "caseErrorQuery",
+			fields: fields{
+				config: ValueInRangeConfig{Min: 0, Max: 1},
+				qAPI: &testPrometheusAPI{
+					err: fmt.Errorf("A prom Error"),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "vector",
+			fields: fields{
+				config:     ValueInRangeConfig{Min: 0, Max: 1},
+				PodNameKey: "pod",
+				qAPI: &testPrometheusAPI{
+					value: model.Vector([]*model.Sample{
+						{Metric: model.Metric{"pod": "podA"}, Value: 0.5},
+						{Metric: model.Metric{"pod": "podB"}, Value: 1.5},
+					}),
+				},
+			},
+			want:    inRangeByPodName{"podA": true, "podB": false},
+			wantErr: false,
+		},
+		{
+			name: "scalar",
+			fields: fields{
+				config:       ValueInRangeConfig{Min: 0, Max: 1},
+				AllPodsQuery: true,
+				qAPI: &testPrometheusAPI{
+					value: &model.Scalar{Value: 0.5},
+				},
+			},
+			want:    inRangeByPodName{GlobalQueryKey: true},
+			wantErr: false,
+		},
+		{
+			name: "scalar not global",
+			fields: fields{
+				config:     ValueInRangeConfig{Min: 0, Max: 1},
+				PodNameKey: "pod",
+				qAPI: &testPrometheusAPI{
+					value: &model.Scalar{Value: 0.5},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "range any, spike detected",
+			fields: fields{
+				config:     ValueInRangeConfig{Min: 0, Max: 1},
+				PodNameKey: "pod",
+				Range:      &RangeQueryConfig{Step: time.Second, Aggregator: AggregatorAny},
+				qAPI: &testPrometheusAPI{
+					value: model.Matrix([]*model.SampleStream{
+						{Metric: model.Metric{"pod": "podA"}, Values: []model.SamplePair{{Value: 0.5}, {Value: 0.2}}},
+						{Metric: model.Metric{"pod": "podB"}, Values: []model.SamplePair{{Value: 0.5}, {Value: 3}, {Value: 0.2}}},
+					}),
+				},
+			},
+			want:    inRangeByPodName{"podA": true, "podB": false},
+			wantErr: false,
+		},
+		{
+			name: "range avg, spike smoothed",
+			fields: fields{
+				config:     ValueInRangeConfig{Min: 0, Max: 1},
+				PodNameKey: "pod",
+				Range:      &RangeQueryConfig{Step: time.Second, Aggregator: AggregatorAvg},
+				qAPI: &testPrometheusAPI{
+					value: model.Matrix([]*model.SampleStream{
+						{Metric: model.Metric{"pod": "podB"}, Values: []model.SamplePair{{Value: 0.1}, {Value: 1.6}, {Value: 0.1}}},
+					}),
+				},
+			},
+			want:    inRangeByPodName{"podB": true},
+			wantErr: false,
+		},
+		{
+			name: "badCast",
+			fields: fields{
+				config:     ValueInRangeConfig{Min: 0, Max: 1},
+				PodNameKey: "pod",
+				qAPI: &testPrometheusAPI{
+					value: &model.String{},
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promConfig := ConfigPrometheusAnomalyDetector{
+				PodNameKey:   tt.fields.PodNameKey,
+				AllPodsQuery: tt.fields.AllPodsQuery,
+				Range:        tt.fields.Range,
+				queryAPI:     tt.fields.qAPI,
+				logger:       logf.Log,
+			}
+			p := &metricsValueInRangeAnalyser{
+				config:     tt.fields.config,
+				provider:   &promMetricsProvider{config: promConfig},
+				aggregator: promConfig.aggregator(),
+			}
+			got, err := p.doAnalysis()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("promValueInRangeAnalyser.doAnalysis() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("promValueInRangeAnalyser.doAnalysis() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
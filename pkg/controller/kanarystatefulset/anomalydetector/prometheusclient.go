@@ -0,0 +1,118 @@
+package anomalydetector
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	promClient "github.com/prometheus/client_golang/api"
+	promApi "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+//PrometheusAuthConfig authentication and TLS settings to connect to prometheus, the secrets are already resolved
+type PrometheusAuthConfig struct {
+	BearerToken string
+	Username    string
+	Password    string
+	Headers     map[string]string
+
+	CA                 []byte
+	Cert               []byte
+	Key                []byte
+	ServerName         string
+	InsecureSkipVerify bool
+}
+
+//address returns the prometheus server address, the URL takes precedence over the service
+func (c *ConfigPrometheusAnomalyDetector) address() string {
+	if c.URL != "" {
+		return c.URL
+	}
+	return "http://" + c.PrometheusService
+}
+
+//newPrometheusQueryAPI builds the prometheus client shared by all the prometheus analysers
+func newPrometheusQueryAPI(c *ConfigPrometheusAnomalyDetector) (promApi.API, error) {
+	config := promClient.Config{Address: c.address()}
+	if c.Auth != nil {
+		roundTripper, err := newPrometheusRoundTripper(c.Auth)
+		if err != nil {
+			return nil, err
+		}
+		config.RoundTripper = roundTripper
+	}
+	prometheusClient, err := promClient.NewClient(config)
+	if err != nil {
+		return nil, err
+	}
+	return promApi.NewAPI(prometheusClient), nil
+}
+
+func newPrometheusRoundTripper(auth *PrometheusAuthConfig) (http.RoundTripper, error) {
+	tlsConfig, err := newPrometheusTLSConfig(auth)
+	if err != nil {
+		return nil, err
+	}
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSHandshakeTimeout: 10 * time.Second,
+		TLSClientConfig:     tlsConfig,
+	}
+	return &authRoundTripper{auth: auth, next: transport}, nil
+}
+
+func newPrometheusTLSConfig(auth *PrometheusAuthConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         auth.ServerName,
+		InsecureSkipVerify: auth.InsecureSkipVerify,
+	}
+	if len(auth.CA) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(auth.CA) {
+			return nil, fmt.Errorf("unable to parse the prometheus CA certificate")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(auth.Cert) > 0 || len(auth.Key) > 0 {
+		cert, err := tls.X509KeyPair(auth.Cert, auth.Key)
+		if err != nil {
+			return nil, fmt.Errorf("unable to load the prometheus client certificate: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+//authRoundTripper adds the authentication and the custom headers to the prometheus requests
+type authRoundTripper struct {
+	auth *PrometheusAuthConfig
+	next http.RoundTripper
+}
+
+//RoundTrip implements http.RoundTripper
+func (rt *authRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	// the request should not be modified by a RoundTripper
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+
+	for k, v := range rt.auth.Headers {
+		r.Header.Set(k, v)
+	}
+	if rt.auth.BearerToken != "" {
+		r.Header.Set("Authorization", "Bearer "+rt.auth.BearerToken)
+	} else if rt.auth.Username != "" {
+		r.SetBasicAuth(rt.auth.Username, rt.auth.Password)
+	}
+	return rt.next.RoundTrip(r)
+}
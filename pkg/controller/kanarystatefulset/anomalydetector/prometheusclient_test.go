@@ -0,0 +1,96 @@
+package anomalydetector
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_newPrometheusQueryAPI(t *testing.T) {
+	emptyVector := `{"status":"success","data":{"resultType":"vector","result":[]}}`
+
+	tests := []struct {
+		name        string
+		tls         bool
+		auth        *PrometheusAuthConfig
+		wantHeaders map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "no auth",
+		},
+		{
+			name: "bearer token and tenant header",
+			auth: &PrometheusAuthConfig{
+				BearerToken: "token",
+				Headers:     map[string]string{"X-Scope-OrgID": "tenant"},
+			},
+			wantHeaders: map[string]string{"Authorization": "Bearer token", "X-Scope-OrgID": "tenant"},
+		},
+		{
+			name:        "basic auth",
+			auth:        &PrometheusAuthConfig{Username: "user", Password: "pass"},
+			wantHeaders: map[string]string{"Authorization": "Basic dXNlcjpwYXNz"},
+		},
+		{
+			name: "https with CA",
+			tls:  true,
+			auth: &PrometheusAuthConfig{},
+		},
+		{
+			name:    "https with unknown CA",
+			tls:     true,
+			auth:    &PrometheusAuthConfig{},
+			wantErr: true,
+		},
+		{
+			name:    "bad CA",
+			auth:    &PrometheusAuthConfig{CA: []byte("not a certificate")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				for k, v := range tt.wantHeaders {
+					if got := r.Header.Get(k); got != v {
+						t.Errorf("header %s = %s, want %s", k, got, v)
+					}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(emptyVector))
+			})
+			var server *httptest.Server
+			if tt.tls {
+				server = httptest.NewTLSServer(handler)
+				if !tt.wantErr {
+					tt.auth.CA = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+				}
+			} else {
+				server = httptest.NewServer(handler)
+			}
+			defer server.Close()
+
+			config := &ConfigPrometheusAnomalyDetector{URL: server.URL, Auth: tt.auth}
+			queryAPI, err := newPrometheusQueryAPI(config)
+			if err == nil {
+				_, err = queryAPI.Query(context.Background(), "up", time.Now())
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("newPrometheusQueryAPI() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigPrometheusAnomalyDetector_address(t *testing.T) {
+	if got := (&ConfigPrometheusAnomalyDetector{PrometheusService: "prometheus:9090"}).address(); got != "http://prometheus:9090" {
+		t.Errorf("address() = %s, want http://prometheus:9090", got)
+	}
+	if got := (&ConfigPrometheusAnomalyDetector{PrometheusService: "prometheus:9090", URL: "https://prom.example.com"}).address(); got != "https://prom.example.com" {
+		t.Errorf("address() = %s, want https://prom.example.com", got)
+	}
+}
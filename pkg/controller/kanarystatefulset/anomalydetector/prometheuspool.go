@@ -0,0 +1,332 @@
+package anomalydetector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"time"
+
+	promApi "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"golang.org/x/time/rate"
+
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	//DefaultPrometheusQPS default maximum number of queries per second sent to a prometheus server
+	DefaultPrometheusQPS = 20
+	//DefaultPrometheusBurst default number of queries sent at once to a prometheus server before the rate limit applies
+	DefaultPrometheusBurst = 40
+	//DefaultPrometheusMaxConcurrency default maximum number of queries running in parallel on a prometheus server
+	DefaultPrometheusMaxConcurrency = 10
+	//DefaultPrometheusCacheTTL default duration during which the result of a query is shared
+	DefaultPrometheusCacheTTL = 10 * time.Second
+	//DefaultPrometheusQueryTimeout default maximum duration of a query, the rate limit and concurrency waits included
+	DefaultPrometheusQueryTimeout = 30 * time.Second
+
+	//prometheusClientIdleTimeout a client not used during this duration is removed from the pool
+	prometheusClientIdleTimeout = 10 * time.Minute
+)
+
+//PrometheusPoolConfig settings of the prometheus clients shared by all the KanaryStatefulsets
+type PrometheusPoolConfig struct {
+	//QPS maximum number of queries per second sent to a prometheus server, 0 disables the rate limit
+	QPS float64
+	//Burst number of queries sent at once to a prometheus server before the rate limit applies
+	Burst int
+	//MaxConcurrency maximum number of queries running in parallel on a prometheus server, 0 disables the cap
+	MaxConcurrency int
+	//CacheTTL the result of a query is shared by the identical queries evaluated in the same time bucket of this duration, 0 disables the cache
+	CacheTTL time.Duration
+	//QueryTimeout maximum duration of a query, the rate limit and concurrency waits included, 0 disables the timeout
+	QueryTimeout time.Duration
+}
+
+//DefaultPrometheusPoolConfig returns the default settings of the prometheus client pool
+func DefaultPrometheusPoolConfig() PrometheusPoolConfig {
+	return PrometheusPoolConfig{
+		QPS:            DefaultPrometheusQPS,
+		Burst:          DefaultPrometheusBurst,
+		MaxConcurrency: DefaultPrometheusMaxConcurrency,
+		CacheTTL:       DefaultPrometheusCacheTTL,
+		QueryTimeout:   DefaultPrometheusQueryTimeout,
+	}
+}
+
+var (
+	prometheusQueryCache = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kanary_prometheus_query_cache_total",
+		Help: "Number of prometheus queries served by the cache (result=hit) or sent to the server (result=miss)",
+	}, []string{"result"})
+	prometheusQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "kanary_prometheus_query_duration_seconds",
+		Help:    "Latency of the queries sent to the prometheus servers, the rate limit and concurrency waits excluded",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"server"})
+	prometheusQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "kanary_prometheus_query_errors_total",
+		Help: "Number of prometheus queries that returned an error",
+	}, []string{"server"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(prometheusQueryCache, prometheusQueryDuration, prometheusQueryErrors)
+}
+
+//prometheusPool controller-wide pool used by the prometheus metrics providers
+var prometheusPool = newPrometheusClientPool(DefaultPrometheusPoolConfig())
+
+//ConfigurePrometheusPool replaces the shared pool of prometheus clients, it should be called before the controllers start
+func ConfigurePrometheusPool(config PrometheusPoolConfig) {
+	prometheusPool = newPrometheusClientPool(config)
+}
+
+//prometheusClientPool shares the prometheus clients by server and authentication, limits the load on each server
+//and deduplicates the identical queries of the KanaryStatefulsets
+type prometheusClientPool struct {
+	config PrometheusPoolConfig
+	now    func() time.Time
+	newAPI func(c *ConfigPrometheusAnomalyDetector) (promApi.API, error) //for test purposes
+
+	mutex     sync.Mutex
+	clients   map[string]*pooledQueryAPI
+	servers   map[string]*prometheusServer
+	cache     map[queryCacheKey]*queryCacheEntry
+	lastPurge time.Time
+}
+
+func newPrometheusClientPool(config PrometheusPoolConfig) *prometheusClientPool {
+	return &prometheusClientPool{
+		config:  config,
+		now:     time.Now,
+		newAPI:  newPrometheusQueryAPI,
+		clients: map[string]*pooledQueryAPI{},
+		servers: map[string]*prometheusServer{},
+		cache:   map[queryCacheKey]*queryCacheEntry{},
+	}
+}
+
+//prometheusServer limits of a prometheus server, shared by all the clients of this server
+type prometheusServer struct {
+	name    string
+	limiter *rate.Limiter
+	slots   chan struct{}
+}
+
+//queryCacheKey identifies the identical queries: same client, same query and same evaluation time bucket
+type queryCacheKey struct {
+	client string
+	query  string
+	start  int64
+	step   time.Duration
+	bucket int64
+}
+
+//queryCacheEntry result of a query, done is closed when the query has returned
+type queryCacheEntry struct {
+	done    chan struct{}
+	expires time.Time
+	value   model.Value
+	err     error
+}
+
+//getQueryAPI returns the client of the prometheus server and authentication of the configuration
+func (p *prometheusClientPool) getQueryAPI(c *ConfigPrometheusAnomalyDetector) (promApi.API, error) {
+	key, err := getClientKey(c)
+	if err != nil {
+		return nil, err
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	now := p.now()
+	p.purge(now)
+	if client, ok := p.clients[key]; ok {
+		client.lastUsed = now
+		return client, nil
+	}
+	queryAPI, err := p.newAPI(c)
+	if err != nil {
+		return nil, err
+	}
+	client := &pooledQueryAPI{API: queryAPI, pool: p, key: key, server: p.getServer(c.address()), lastUsed: now}
+	p.clients[key] = client
+	return client, nil
+}
+
+func (p *prometheusClientPool) getServer(address string) *prometheusServer {
+	if server, ok := p.servers[address]; ok {
+		return server
+	}
+	server := &prometheusServer{name: getServerName(address)}
+	if p.config.QPS > 0 {
+		burst := p.config.Burst
+		if burst < 1 {
+			burst = 1
+		}
+		server.limiter = rate.NewLimiter(rate.Limit(p.config.QPS), burst)
+	}
+	if p.config.MaxConcurrency > 0 {
+		server.slots = make(chan struct{}, p.config.MaxConcurrency)
+	}
+	p.servers[address] = server
+	return server
+}
+
+//purge removes the expired results, the idle clients and the servers without client, at most once per TTL. The mutex should be held.
+func (p *prometheusClientPool) purge(now time.Time) {
+	interval := p.config.CacheTTL
+	if interval <= 0 {
+		interval = prometheusClientIdleTimeout
+	}
+	if now.Sub(p.lastPurge) < interval {
+		return
+	}
+	p.lastPurge = now
+	for key, entry := range p.cache {
+		if now.After(entry.expires) {
+			delete(p.cache, key)
+		}
+	}
+	for key, client := range p.clients {
+		if now.Sub(client.lastUsed) > prometheusClientIdleTimeout {
+			delete(p.clients, key)
+		}
+	}
+	// the servers of the deleted or updated configurations would otherwise stay in the pool
+	used := map[*prometheusServer]bool{}
+	for _, client := range p.clients {
+		used[client.server] = true
+	}
+	for address, server := range p.servers {
+		if !used[server] {
+			delete(p.servers, address)
+		}
+	}
+}
+
+//do runs the query, or waits for the result of an identical query of the same time bucket
+func (p *prometheusClientPool) do(ctx context.Context, key queryCacheKey, server *prometheusServer, query func(ctx context.Context) (model.Value, error)) (model.Value, error) {
+	if p.config.QueryTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.config.QueryTimeout)
+		defer cancel()
+	}
+	if p.config.CacheTTL <= 0 {
+		prometheusQueryCache.WithLabelValues("miss").Inc()
+		return server.run(ctx, query)
+	}
+
+	p.mutex.Lock()
+	now := p.now()
+	p.purge(now)
+	if entry, ok := p.cache[key]; ok && now.Before(entry.expires) {
+		p.mutex.Unlock()
+		prometheusQueryCache.WithLabelValues("hit").Inc()
+		select {
+		case <-entry.done:
+			return entry.value, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	entry := &queryCacheEntry{done: make(chan struct{}), expires: now.Add(p.config.CacheTTL)}
+	p.cache[key] = entry
+	p.mutex.Unlock()
+
+	prometheusQueryCache.WithLabelValues("miss").Inc()
+	entry.value, entry.err = server.run(ctx, query)
+	if entry.err != nil {
+		// the waiting queries get the error, the next ones retry
+		p.mutex.Lock()
+		if p.cache[key] == entry {
+			delete(p.cache, key)
+		}
+		p.mutex.Unlock()
+	}
+	close(entry.done)
+	return entry.value, entry.err
+}
+
+//run sends the query to the server once the rate limit and the concurrency cap allow it
+func (s *prometheusServer) run(ctx context.Context, query func(ctx context.Context) (model.Value, error)) (model.Value, error) {
+	if s.limiter != nil {
+		if err := s.limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+	if s.slots != nil {
+		select {
+		case s.slots <- struct{}{}:
+			defer func() { <-s.slots }()
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	start := time.Now()
+	value, err := query(ctx)
+	prometheusQueryDuration.WithLabelValues(s.name).Observe(time.Since(start).Seconds())
+	if err != nil {
+		prometheusQueryErrors.WithLabelValues(s.name).Inc()
+	}
+	return value, err
+}
+
+var _ promApi.API = &pooledQueryAPI{}
+
+//pooledQueryAPI prometheus client of the pool, the queries go through the cache and the limits of the server
+type pooledQueryAPI struct {
+	promApi.API
+	pool     *prometheusClientPool
+	server   *prometheusServer
+	key      string
+	lastUsed time.Time
+}
+
+//Query implements promApi.API
+func (c *pooledQueryAPI) Query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	key := queryCacheKey{client: c.key, query: query, bucket: c.pool.getBucket(ts)}
+	return c.pool.do(ctx, key, c.server, func(ctx context.Context) (model.Value, error) {
+		return c.API.Query(ctx, query, ts)
+	})
+}
+
+//QueryRange implements promApi.API
+func (c *pooledQueryAPI) QueryRange(ctx context.Context, query string, r promApi.Range) (model.Value, error) {
+	key := queryCacheKey{client: c.key, query: query, start: c.pool.getBucket(r.Start), step: r.Step, bucket: c.pool.getBucket(r.End)}
+	return c.pool.do(ctx, key, c.server, func(ctx context.Context) (model.Value, error) {
+		return c.API.QueryRange(ctx, query, r)
+	})
+}
+
+//getBucket returns the time bucket of an evaluation time
+func (p *prometheusClientPool) getBucket(t time.Time) int64 {
+	if p.config.CacheTTL <= 0 {
+		return t.UnixNano()
+	}
+	return t.Truncate(p.config.CacheTTL).UnixNano()
+}
+
+//getClientKey identifies the server and the authentication of the configuration, the credentials are hashed
+func getClientKey(c *ConfigPrometheusAnomalyDetector) (string, error) {
+	auth, err := json.Marshal(c.Auth)
+	if err != nil {
+		return "", fmt.Errorf("unable to serialize the prometheus authentication: %v", err)
+	}
+	sum := sha256.Sum256(auth)
+	return c.address() + "#" + hex.EncodeToString(sum[:]), nil
+}
+
+//getServerName returns the server label of the metrics, without the credentials and the path of the address
+func getServerName(address string) string {
+	u, err := url.Parse(address)
+	if err != nil || u.Host == "" {
+		return address
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,152 @@
+package anomalydetector
+
+import (
+	"fmt"
+
+	"github.com/k8s-kanary/kanary/pkg/pod"
+	kapiv1 "k8s.io/api/core/v1"
+)
+
+var _ AnomalyDetector = &ResourceUsageAnalyser{}
+var _ ValuesReporter = &ResourceUsageAnalyser{}
+
+//PodUsageGetter returns the resource usage of the pods, indexed by pod name. Pods without metrics are not in the result
+type PodUsageGetter interface {
+	GetPodsUsage(pods []*kapiv1.Pod) (map[string]kapiv1.ResourceList, error)
+}
+
+//ResourceUsageConfig Configuration for ResourceUsageAnalyser
+type ResourceUsageConfig struct {
+	CPU    *ResourceUsageThresholdConfig
+	Memory *ResourceUsageThresholdConfig
+	//IsCanary returns true if the pod runs the version under validation
+	IsCanary    func(*kapiv1.Pod) bool
+	UsageGetter PodUsageGetter
+}
+
+//ResourceUsageThresholdConfig bounds of the usage of one resource
+type ResourceUsageThresholdConfig struct {
+	//MaxIncreasePercent maximum increase of a canary pod usage compared to the average of the stable pods
+	MaxIncreasePercent *float64
+	//Limit maximum usage of a canary pod, in cores for the cpu and in bytes for the memory
+	Limit *float64
+}
+
+//ResourceUsageAnalyser anomalyDetector that compares the cpu and memory usage of the canary pods with the stable pods
+type ResourceUsageAnalyser struct {
+	ConfigSpecific ResourceUsageConfig
+	ConfigAnalyser Config
+
+	values map[string]float64
+}
+
+func newResourceUsageAnalyser(cfg Config, specific ResourceUsageConfig) (*ResourceUsageAnalyser, error) {
+	if specific.UsageGetter == nil || specific.IsCanary == nil {
+		return nil, fmt.Errorf("the resource usage analyser requires a usage getter and a canary pods filter")
+	}
+	return &ResourceUsageAnalyser{ConfigSpecific: specific, ConfigAnalyser: cfg}, nil
+}
+
+//GetLastValues implements interface ValuesReporter
+func (d *ResourceUsageAnalyser) GetLastValues() map[string]float64 {
+	return d.values
+}
+
+//GetPodsOutOfBounds implements interface AnomalyDetector
+func (d *ResourceUsageAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod, error) {
+	listOfPods, err := d.ConfigAnalyser.PodLister.List(d.ConfigAnalyser.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("can't list pods, error:%v", err)
+	}
+	listOfPods, err = pod.PurgeNotReadyPods(listOfPods)
+	if err != nil {
+		return nil, fmt.Errorf("can't purge not ready pods, error:%v", err)
+	}
+	if d.ConfigAnalyser.ExclusionFunc != nil {
+		if listOfPods, err = pod.FilterOut(listOfPods, d.ConfigAnalyser.ExclusionFunc); err != nil {
+			return nil, err
+		}
+	}
+
+	usageByPodName, err := d.ConfigSpecific.UsageGetter.GetPodsUsage(listOfPods)
+	if err != nil {
+		return nil, err
+	}
+
+	canaryPods := []*kapiv1.Pod{}
+	var stableCPU, stableMemory float64
+	stableCount := 0
+	for _, p := range listOfPods {
+		if d.ConfigSpecific.IsCanary(p) {
+			canaryPods = append(canaryPods, p)
+			continue
+		}
+		if usage, ok := usageByPodName[p.Name]; ok {
+			stableCPU += cpuCores(usage)
+			stableMemory += memoryBytes(usage)
+			stableCount++
+		}
+	}
+	if stableCount > 0 {
+		stableCPU = stableCPU / float64(stableCount)
+		stableMemory = stableMemory / float64(stableCount)
+	}
+
+	d.values = map[string]float64{}
+	result := []*kapiv1.Pod{}
+	for _, p := range canaryPods {
+		usage, ok := usageByPodName[p.Name]
+		if !ok {
+			continue
+		}
+		cpu, memory := cpuCores(usage), memoryBytes(usage)
+		outOfBounds := false
+		if d.ConfigSpecific.CPU != nil {
+			d.values[p.Name+"/cpu"] = cpu
+			outOfBounds = outOfBounds || d.ConfigSpecific.CPU.isOutOfBounds(cpu, stableCPU, stableCount > 0)
+		}
+		if d.ConfigSpecific.Memory != nil {
+			d.values[p.Name+"/memory"] = memory
+			outOfBounds = outOfBounds || d.ConfigSpecific.Memory.isOutOfBounds(memory, stableMemory, stableCount > 0)
+		}
+		if outOfBounds {
+			result = append(result, p)
+		}
+	}
+	if stableCount > 0 {
+		if d.ConfigSpecific.CPU != nil {
+			d.values["stable/cpu"] = stableCPU
+		}
+		if d.ConfigSpecific.Memory != nil {
+			d.values["stable/memory"] = stableMemory
+		}
+	}
+	return result, nil
+}
+
+//isOutOfBounds the comparison with the stable average is only done if at least one stable pod reported its usage
+func (t *ResourceUsageThresholdConfig) isOutOfBounds(value, stableAverage float64, hasStable bool) bool {
+	if t.Limit != nil && value > *t.Limit {
+		return true
+	}
+	if t.MaxIncreasePercent != nil && hasStable && value > stableAverage*(1+*t.MaxIncreasePercent/100) {
+		return true
+	}
+	return false
+}
+
+func cpuCores(usage kapiv1.ResourceList) float64 {
+	q, ok := usage[kapiv1.ResourceCPU]
+	if !ok {
+		return 0
+	}
+	return float64(q.MilliValue()) / 1000
+}
+
+func memoryBytes(usage kapiv1.ResourceList) float64 {
+	q, ok := usage[kapiv1.ResourceMemory]
+	if !ok {
+		return 0
+	}
+	return float64(q.Value())
+}
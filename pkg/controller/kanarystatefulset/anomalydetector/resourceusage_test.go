@@ -0,0 +1,144 @@
+package anomalydetector
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	test "github.com/k8s-kanary/kanary/test"
+	kapiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/labels"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+type testPodUsageGetter struct {
+	usage map[string]kapiv1.ResourceList
+	err   error
+}
+
+func (t *testPodUsageGetter) GetPodsUsage(pods []*kapiv1.Pod) (map[string]kapiv1.ResourceList, error) {
+	return t.usage, t.err
+}
+
+func newResourceList(cpu, memory string) kapiv1.ResourceList {
+	return kapiv1.ResourceList{kapiv1.ResourceCPU: resource.MustParse(cpu), kapiv1.ResourceMemory: resource.MustParse(memory)}
+}
+
+func TestResourceUsageAnalyser_GetPodsOutOfBounds(t *testing.T) {
+	pods := []*kapiv1.Pod{
+		test.PodGen("stable-0", "test-ns", map[string]string{"app": "foo", "rev": "v1"}, nil, true, true),
+		test.PodGen("stable-1", "test-ns", map[string]string{"app": "foo", "rev": "v1"}, nil, true, true),
+		test.PodGen("canary-2", "test-ns", map[string]string{"app": "foo", "rev": "v2"}, nil, true, true),
+		test.PodGen("canary-3", "test-ns", map[string]string{"app": "foo", "rev": "v2"}, nil, true, false),
+	}
+	isCanary := func(p *kapiv1.Pod) bool { return p.Labels["rev"] == "v2" }
+
+	tests := []struct {
+		name       string
+		config     ResourceUsageConfig
+		exclusion  func(*kapiv1.Pod) (bool, error)
+		want       []string
+		wantValues map[string]float64
+		wantErr    bool
+	}{
+		{
+			name: "usage getter error",
+			config: ResourceUsageConfig{
+				CPU:         &ResourceUsageThresholdConfig{MaxIncreasePercent: floatPtr(50)},
+				UsageGetter: &testPodUsageGetter{err: fmt.Errorf("metrics api not available")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cpu within the increase",
+			config: ResourceUsageConfig{
+				CPU: &ResourceUsageThresholdConfig{MaxIncreasePercent: floatPtr(50)},
+				UsageGetter: &testPodUsageGetter{usage: map[string]kapiv1.ResourceList{
+					"stable-0": newResourceList("100m", "100Mi"),
+					"stable-1": newResourceList("300m", "100Mi"),
+					"canary-2": newResourceList("250m", "500Mi"),
+				}},
+			},
+			want:       []string{},
+			wantValues: map[string]float64{"canary-2/cpu": 0.25, "stable/cpu": 0.2},
+		},
+		{
+			name: "cpu increase exceeded, not ready canary ignored",
+			config: ResourceUsageConfig{
+				CPU: &ResourceUsageThresholdConfig{MaxIncreasePercent: floatPtr(20)},
+				UsageGetter: &testPodUsageGetter{usage: map[string]kapiv1.ResourceList{
+					"stable-0": newResourceList("100m", "100Mi"),
+					"stable-1": newResourceList("300m", "100Mi"),
+					"canary-2": newResourceList("250m", "100Mi"),
+					"canary-3": newResourceList("900m", "100Mi"),
+				}},
+			},
+			want:       []string{"canary-2"},
+			wantValues: map[string]float64{"canary-2/cpu": 0.25, "stable/cpu": 0.2},
+		},
+		{
+			name: "memory limit exceeded",
+			config: ResourceUsageConfig{
+				Memory: &ResourceUsageThresholdConfig{Limit: floatPtr(200 * 1024 * 1024)},
+				UsageGetter: &testPodUsageGetter{usage: map[string]kapiv1.ResourceList{
+					"canary-2": newResourceList("100m", "300Mi"),
+				}},
+			},
+			want:       []string{"canary-2"},
+			wantValues: map[string]float64{"canary-2/memory": 300 * 1024 * 1024},
+		},
+		{
+			name: "excluded pods are not compared",
+			config: ResourceUsageConfig{
+				Memory: &ResourceUsageThresholdConfig{MaxIncreasePercent: floatPtr(10)},
+				UsageGetter: &testPodUsageGetter{usage: map[string]kapiv1.ResourceList{
+					"stable-0": newResourceList("100m", "100Mi"),
+					"stable-1": newResourceList("100m", "100Mi"),
+					"canary-2": newResourceList("100m", "300Mi"),
+				}},
+			},
+			exclusion:  func(p *kapiv1.Pod) (bool, error) { return p.Name == "canary-2", nil },
+			want:       []string{},
+			wantValues: map[string]float64{"stable/memory": 100 * 1024 * 1024},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.IsCanary = isCanary
+			d, err := newResourceUsageAnalyser(Config{
+				Selector:      labels.SelectorFromSet(map[string]string{"app": "foo"}),
+				PodLister:     test.NewTestPodNamespaceLister(pods, "test-ns"),
+				Logger:        logf.Log,
+				ExclusionFunc: tt.exclusion,
+			}, tt.config)
+			if err != nil {
+				t.Fatalf("newResourceUsageAnalyser() error = %v", err)
+			}
+			got, err := d.GetPodsOutOfBounds()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResourceUsageAnalyser.GetPodsOutOfBounds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			names := []string{}
+			for _, p := range got {
+				names = append(names, p.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("ResourceUsageAnalyser.GetPodsOutOfBounds() = %v, want %v", names, tt.want)
+			}
+			if !reflect.DeepEqual(d.GetLastValues(), tt.wantValues) {
+				t.Errorf("ResourceUsageAnalyser.GetLastValues() = %v, want %v", d.GetLastValues(), tt.wantValues)
+			}
+		})
+	}
+}
+
+func floatPtr(f float64) *float64 {
+	return &f
+}
@@ -33,3 +33,18 @@ func PodByName(listOfPods []*kapiv1.Pod, exclusionFunc func(*kapiv1.Pod) (bool,
 	}
 	return podByName, podWithNoTraffic, nil
 }
+
+//expandGlobalValue returns a copy of the values where the GlobalQueryKey value is applied to all pods
+func expandGlobalValue(values map[string]float64, podByName map[string]*kapiv1.Pod) map[string]float64 {
+	result := map[string]float64{}
+	for k, v := range values {
+		result[k] = v
+	}
+	if v, ok := result[GlobalQueryKey]; ok && len(result) == 1 {
+		for name := range podByName {
+			result[name] = v
+		}
+		delete(result, GlobalQueryKey)
+	}
+	return result
+}
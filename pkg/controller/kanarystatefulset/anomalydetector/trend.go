@@ -0,0 +1,203 @@
+package anomalydetector
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/k8s-kanary/kanary/pkg/pod"
+	kapiv1 "k8s.io/api/core/v1"
+)
+
+var _ AnomalyDetector = &TrendAnalyser{}
+var _ ValuesReporter = &TrendAnalyser{}
+
+//trendMinSamples minimum number of samples to fit a linear regression with a meaningful standard error
+const trendMinSamples = 3
+
+//TrendConfig Configuration for TrendAnalyser
+type TrendConfig struct {
+	//Limit a pod is out of bounds if its value projected after Horizon is greater than the limit
+	Limit   *float64
+	Horizon time.Duration
+	//MaxSlopeDeviationPercent a canary pod is out of bounds if its slope is significantly steeper than the average slope of the stable pods
+	MaxSlopeDeviationPercent *float64
+	//IsCanary returns true if the pod runs the version under validation, if nil all the pods are checked against the limit
+	IsCanary func(*kapiv1.Pod) bool
+}
+
+//TrendAnalyser anomalyDetector that fits a linear regression on the values of each pod over the time range
+type TrendAnalyser struct {
+	ConfigSpecific TrendConfig
+	ConfigAnalyser Config
+
+	provider MetricsProvider
+	values   map[string]float64
+}
+
+//linearTrend result of the linear regression of a serie
+type linearTrend struct {
+	//slope per second
+	slope float64
+	//slopeStdErr standard error of the slope
+	slopeStdErr float64
+	//last value of the regression line, at the time of the last sample
+	last float64
+}
+
+func newTrendAnalyser(cfg Config, specific TrendConfig, provider MetricsProvider) (*TrendAnalyser, error) {
+	if specific.Limit == nil && specific.MaxSlopeDeviationPercent == nil {
+		return nil, fmt.Errorf("the trend analysis requires a limit or a maxSlopeDeviationPercent")
+	}
+	if specific.MaxSlopeDeviationPercent != nil && specific.IsCanary == nil {
+		return nil, fmt.Errorf("the trend analysis requires a canary pods filter to compare the slopes")
+	}
+	return &TrendAnalyser{ConfigSpecific: specific, ConfigAnalyser: cfg, provider: provider}, nil
+}
+
+//GetLastValues implements interface ValuesReporter, the slope (per second) and the projection of each pod
+func (d *TrendAnalyser) GetLastValues() map[string]float64 {
+	return d.values
+}
+
+//GetPodsOutOfBounds implements interface AnomalyDetector
+func (d *TrendAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod, error) {
+	listOfPods, err := d.ConfigAnalyser.PodLister.List(d.ConfigAnalyser.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("can't list pods, error:%v", err)
+	}
+	listOfPods, err = pod.PurgeNotReadyPods(listOfPods)
+	if err != nil {
+		return nil, fmt.Errorf("can't purge not ready pods, error:%v", err)
+	}
+	podByName, podWithNoTraffic, err := PodByName(listOfPods, d.ConfigAnalyser.ExclusionFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	samples, err := d.provider.GetSamples()
+	if err != nil {
+		return nil, err
+	}
+	trendByPodName, err := d.fitSamples(samples, podByName, podWithNoTraffic)
+	if err != nil {
+		return nil, err
+	}
+
+	var stableSlope float64
+	stableCount := 0
+	if d.ConfigSpecific.IsCanary != nil {
+		for podName, trend := range trendByPodName {
+			if !d.ConfigSpecific.IsCanary(podByName[podName]) {
+				stableSlope += trend.slope
+				stableCount++
+			}
+		}
+	}
+	if stableCount > 0 {
+		stableSlope = stableSlope / float64(stableCount)
+	}
+
+	d.values = map[string]float64{}
+	result := []*kapiv1.Pod{}
+	for podName, trend := range trendByPodName {
+		p := podByName[podName]
+		if d.ConfigSpecific.IsCanary != nil && !d.ConfigSpecific.IsCanary(p) {
+			continue
+		}
+		d.values[podName+"/slope"] = trend.slope
+		outOfBounds := false
+		if d.ConfigSpecific.Limit != nil {
+			projection := trend.last + trend.slope*d.ConfigSpecific.Horizon.Seconds()
+			d.values[podName+"/projection"] = projection
+			outOfBounds = projection > *d.ConfigSpecific.Limit
+		}
+		if d.ConfigSpecific.MaxSlopeDeviationPercent != nil && stableCount > 0 {
+			outOfBounds = outOfBounds || trend.isSteeper(stableSlope, *d.ConfigSpecific.MaxSlopeDeviationPercent)
+		}
+		if outOfBounds {
+			result = append(result, p)
+		}
+	}
+	if d.ConfigSpecific.MaxSlopeDeviationPercent != nil && stableCount > 0 {
+		d.values["stable/slope"] = stableSlope
+	}
+	return result, nil
+}
+
+//fitSamples fits the series of the pods that are ready and not excluded. If a pod has several series, the steepest is kept
+func (d *TrendAnalyser) fitSamples(samples []Sample, podByName, podWithNoTraffic map[string]*kapiv1.Pod) (map[string]linearTrend, error) {
+	trendByPodName := map[string]linearTrend{}
+	for _, sample := range samples {
+		podName, err := sample.podName()
+		if err != nil {
+			return nil, err
+		}
+		if podName == GlobalQueryKey {
+			return nil, fmt.Errorf("the trend analysis requires a serie per pod, the query should not be marked as global")
+		}
+		if _, ok := podByName[podName]; !ok {
+			continue
+		}
+		if _, ok := podWithNoTraffic[podName]; ok {
+			continue
+		}
+		trend, ok, err := fitLinearTrend(sample)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+		if previous, found := trendByPodName[podName]; !found || trend.slope > previous.slope {
+			trendByPodName[podName] = trend
+		}
+	}
+	return trendByPodName, nil
+}
+
+//isSteeper the slope is steeper if it exceeds the stable slope by more than the tolerance and by more than twice its standard error
+func (t linearTrend) isSteeper(stableSlope, maxDeviationPercent float64) bool {
+	diff := t.slope - stableSlope
+	return diff > math.Abs(stableSlope)*maxDeviationPercent/100 && diff > 2*t.slopeStdErr
+}
+
+//fitLinearTrend least squares linear regression of the values over time, returns false if there are not enough samples
+func fitLinearTrend(sample Sample) (linearTrend, bool, error) {
+	if len(sample.Timestamps) != len(sample.Values) {
+		return linearTrend{}, false, fmt.Errorf("the trend analysis requires the timestamps of the samples")
+	}
+	n := len(sample.Values)
+	if n < trendMinSamples {
+		return linearTrend{}, false, nil
+	}
+	start := sample.Timestamps[0]
+	var sumX, sumY float64
+	xs := make([]float64, n)
+	for i, v := range sample.Values {
+		xs[i] = sample.Timestamps[i].Sub(start).Seconds()
+		sumX += xs[i]
+		sumY += v
+	}
+	meanX, meanY := sumX/float64(n), sumY/float64(n)
+	var sxx, sxy float64
+	for i, v := range sample.Values {
+		sxx += (xs[i] - meanX) * (xs[i] - meanX)
+		sxy += (xs[i] - meanX) * (v - meanY)
+	}
+	if sxx == 0 {
+		return linearTrend{}, false, nil
+	}
+	slope := sxy / sxx
+	intercept := meanY - slope*meanX
+	var sse float64
+	for i, v := range sample.Values {
+		residual := v - (intercept + slope*xs[i])
+		sse += residual * residual
+	}
+	return linearTrend{
+		slope:       slope,
+		slopeStdErr: math.Sqrt(sse/float64(n-2)) / math.Sqrt(sxx),
+		last:        intercept + slope*xs[n-1],
+	}, true, nil
+}
@@ -0,0 +1,187 @@
+package anomalydetector
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	test "github.com/k8s-kanary/kanary/test"
+	kapiv1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+type testMetricsProvider struct {
+	samples []Sample
+	err     error
+}
+
+func (t *testMetricsProvider) GetSamples() ([]Sample, error) {
+	return t.samples, t.err
+}
+
+//linearSample returns a sample with one value per minute, starting at start and increasing by slope per second
+func linearSample(podName string, start, slope float64, count int) Sample {
+	s := Sample{PodName: podName, Labels: map[string]string{}}
+	t0 := time.Unix(1000, 0)
+	for i := 0; i < count; i++ {
+		s.Timestamps = append(s.Timestamps, t0.Add(time.Duration(i)*time.Minute))
+		s.Values = append(s.Values, start+slope*float64(i*60))
+	}
+	return s
+}
+
+func Test_fitLinearTrend(t *testing.T) {
+	noisy := linearSample("A", 100, 2, 5)
+	noisy.Values[1] += 30
+	noisy.Values[3] += 30
+
+	tests := []struct {
+		name       string
+		sample     Sample
+		wantOK     bool
+		wantSlope  float64
+		wantLast   float64
+		wantStdErr bool
+		wantErr    bool
+	}{
+		{name: "not enough samples", sample: linearSample("A", 100, 2, 2), wantOK: false},
+		{name: "missing timestamps", sample: Sample{PodName: "A", Values: []float64{1, 2, 3}}, wantErr: true},
+		{name: "perfect line", sample: linearSample("A", 100, 2, 5), wantOK: true, wantSlope: 2, wantLast: 580},
+		{name: "noisy line", sample: noisy, wantOK: true, wantSlope: 2, wantLast: 592, wantStdErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok, err := fitLinearTrend(tt.sample)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("fitLinearTrend() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if ok != tt.wantOK {
+				t.Errorf("fitLinearTrend() ok = %v, want %v", ok, tt.wantOK)
+				return
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(got.slope-tt.wantSlope) > 1e-9 || math.Abs(got.last-tt.wantLast) > 1e-6 {
+				t.Errorf("fitLinearTrend() = %+v, want slope %v last %v", got, tt.wantSlope, tt.wantLast)
+			}
+			if (got.slopeStdErr > 1e-9) != tt.wantStdErr {
+				t.Errorf("fitLinearTrend() slopeStdErr = %v", got.slopeStdErr)
+			}
+		})
+	}
+}
+
+func TestTrendAnalyser_GetPodsOutOfBounds(t *testing.T) {
+	pods := []*kapiv1.Pod{
+		test.PodGen("stable-0", "test-ns", map[string]string{"app": "foo", "rev": "v1"}, nil, true, true),
+		test.PodGen("stable-1", "test-ns", map[string]string{"app": "foo", "rev": "v1"}, nil, true, true),
+		test.PodGen("canary-2", "test-ns", map[string]string{"app": "foo", "rev": "v2"}, nil, true, true),
+	}
+	isCanary := func(p *kapiv1.Pod) bool { return p.Labels["rev"] == "v2" }
+
+	tests := []struct {
+		name       string
+		config     TrendConfig
+		provider   MetricsProvider
+		want       []string
+		wantValues map[string]float64
+		wantErr    bool
+	}{
+		{
+			name:     "provider error",
+			config:   TrendConfig{Limit: floatPtr(1000), Horizon: time.Hour},
+			provider: &testMetricsProvider{err: fmt.Errorf("prometheus not available")},
+			wantErr:  true,
+		},
+		{
+			name:     "global query",
+			config:   TrendConfig{Limit: floatPtr(1000), Horizon: time.Hour},
+			provider: &testMetricsProvider{samples: []Sample{linearSample(GlobalQueryKey, 0, 1, 5)}},
+			wantErr:  true,
+		},
+		{
+			name:   "projection crosses the limit",
+			config: TrendConfig{Limit: floatPtr(1000), Horizon: time.Hour, IsCanary: isCanary},
+			provider: &testMetricsProvider{samples: []Sample{
+				linearSample("stable-0", 100, 0.5, 5),
+				linearSample("canary-2", 100, 0.5, 5),
+			}},
+			want:       []string{"canary-2"},
+			wantValues: map[string]float64{"canary-2/slope": 0.5, "canary-2/projection": 2020},
+		},
+		{
+			name:   "projection below the limit",
+			config: TrendConfig{Limit: floatPtr(1000), Horizon: time.Hour},
+			provider: &testMetricsProvider{samples: []Sample{
+				linearSample("canary-2", 100, 0.1, 5),
+			}},
+			want:       []string{},
+			wantValues: map[string]float64{"canary-2/slope": 0.1, "canary-2/projection": 484},
+		},
+		{
+			name:   "slope steeper than stable",
+			config: TrendConfig{MaxSlopeDeviationPercent: floatPtr(50), IsCanary: isCanary},
+			provider: &testMetricsProvider{samples: []Sample{
+				linearSample("stable-0", 100, 1, 5),
+				linearSample("stable-1", 100, 1, 5),
+				linearSample("canary-2", 100, 2, 5),
+			}},
+			want:       []string{"canary-2"},
+			wantValues: map[string]float64{"canary-2/slope": 2, "stable/slope": 1},
+		},
+		{
+			name:   "slope within the deviation",
+			config: TrendConfig{MaxSlopeDeviationPercent: floatPtr(50), IsCanary: isCanary},
+			provider: &testMetricsProvider{samples: []Sample{
+				linearSample("stable-0", 100, 1, 5),
+				linearSample("stable-1", 100, 1, 5),
+				linearSample("canary-2", 100, 1.25, 5),
+			}},
+			want:       []string{},
+			wantValues: map[string]float64{"canary-2/slope": 1.25, "stable/slope": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := newTrendAnalyser(Config{
+				Selector:  labels.SelectorFromSet(map[string]string{"app": "foo"}),
+				PodLister: test.NewTestPodNamespaceLister(pods, "test-ns"),
+				Logger:    logf.Log,
+			}, tt.config, tt.provider)
+			if err != nil {
+				t.Fatalf("newTrendAnalyser() error = %v", err)
+			}
+			got, err := d.GetPodsOutOfBounds()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TrendAnalyser.GetPodsOutOfBounds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			names := []string{}
+			for _, p := range got {
+				names = append(names, p.Name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.want) {
+				t.Errorf("TrendAnalyser.GetPodsOutOfBounds() = %v, want %v", names, tt.want)
+			}
+			values := d.GetLastValues()
+			if len(values) != len(tt.wantValues) {
+				t.Errorf("TrendAnalyser.GetLastValues() = %v, want %v", values, tt.wantValues)
+			}
+			for k, v := range tt.wantValues {
+				if math.Abs(values[k]-v) > 1e-6 {
+					t.Errorf("TrendAnalyser.GetLastValues()[%s] = %v, want %v", k, values[k], v)
+				}
+			}
+		})
+	}
+}
@@ -8,11 +8,13 @@ import (
 )
 
 var _ AnomalyDetector = &ValueInRangeAnalyser{}
+var _ ValuesReporter = &ValueInRangeAnalyser{}
 
 //inRangeByPodName true means in range
 type inRangeByPodName map[string]bool
 type valueInRangeAnalyser interface {
 	doAnalysis() (inRangeByPodName, error)
+	lastValues() map[string]float64
 }
 
 //ValueInRangeConfig Configuration for ValueInRangeAnalyser
@@ -27,6 +29,12 @@ type ValueInRangeAnalyser struct {
 	ConfigAnalyser Config
 
 	analyser valueInRangeAnalyser
+	values   map[string]float64
+}
+
+//GetLastValues implements interface ValuesReporter
+func (d *ValueInRangeAnalyser) GetLastValues() map[string]float64 {
+	return d.values
 }
 
 //GetPodsOutOfBounds implements interface AnomalyDetector
@@ -49,6 +57,7 @@ func (d *ValueInRangeAnalyser) GetPodsOutOfBounds() ([]*kapiv1.Pod, error) {
 	if err != nil {
 		return nil, err
 	}
+	d.values = expandGlobalValue(d.analyser.lastValues(), podByName)
 
 	//check if the key is GlobalKeyQuery that means that the result is applicable to all pods
 	if len(inRangeByPods) == 1 {
@@ -8,19 +8,24 @@ import (
 	"github.com/go-logr/logr"
 
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
 	kuriseclient "github.com/openkruise/kruise/pkg/client"
 
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -28,6 +33,7 @@ import (
 	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
 	"github.com/k8s-kanary/kanary/pkg/config"
 	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies/validation"
 	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils"
 	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/comparison"
 	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/enqueue"
@@ -60,16 +66,51 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Watch for changes to primary resource KanaryStatefulset
-	err = c.Watch(&source.Kind{Type: &kanaryv1alpha1.KanaryStatefulset{}}, &handler.EnqueueRequestForObject{})
+	err = c.Watch(&source.Kind{Type: &kanaryv1alpha1.KanaryStatefulset{}}, &handler.EnqueueRequestForObject{}, ignoreStatusUpdate)
 	if err != nil {
 		return err
 	}
 
 	// Watch for changes to secondary resource Pod and requeue the owner KanaryStatefulset
 	err = c.Watch(&source.Kind{Type: &corev1.Pod{}}, &enqueue.RequestForKanaryLabel{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for Warning Events involving the StatefulSet or its pods, used by the events validation
+	err = c.Watch(&source.Kind{Type: &corev1.Event{}}, &handler.EnqueueRequestsFromMapFunc{ToRequests: &enqueue.WarningEventMapper{Client: mgr.GetClient()}})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to secondary resource Job, created by the job validation, and requeue the owner KanaryStatefulset
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &kanaryv1alpha1.KanaryStatefulset{},
+	})
 	return err
 }
 
+// ignoreStatusUpdate filters out the KanaryStatefulset update events that only change the status.
+// The validation counters are saved in the status at each check, the reconcile already requeues
+// itself when needed so these events would only trigger unexpected validation checks.
+var ignoreStatusUpdate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldKd, ok := e.ObjectOld.(*kanaryv1alpha1.KanaryStatefulset)
+		if !ok {
+			return true
+		}
+		newKd, ok := e.ObjectNew.(*kanaryv1alpha1.KanaryStatefulset)
+		if !ok {
+			return true
+		}
+		return !apiequality.Semantic.DeepEqual(oldKd.Spec, newKd.Spec) ||
+			!apiequality.Semantic.DeepEqual(oldKd.ObjectMeta.Labels, newKd.ObjectMeta.Labels) ||
+			!apiequality.Semantic.DeepEqual(oldKd.ObjectMeta.Annotations, newKd.ObjectMeta.Annotations) ||
+			newKd.ObjectMeta.DeletionTimestamp != nil
+	},
+}
+
 var _ reconcile.Reconciler = &ReconcileKanaryStatefulset{}
 var subResourceDisabled = os.Getenv(config.KanaryStatusSubresourceDisabledEnvVar) == "1"
 
@@ -103,6 +144,10 @@ func (r *ReconcileKanaryStatefulset) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{}, err
 	}
 
+	if instance.DeletionTimestamp != nil {
+		return r.manageDeletion(reqLogger, instance)
+	}
+
 	if !kanaryv1alpha1.IsDefaultedKanaryStatefulset(instance) {
 		reqLogger.Info("Defaulting values")
 		defaultedInstance := kanaryv1alpha1.DefaultKanaryStatefulset(instance)
@@ -116,6 +161,13 @@ func (r *ReconcileKanaryStatefulset) Reconcile(request reconcile.Request) (recon
 		return reconcile.Result{Requeue: true}, nil
 	}
 
+	// An invalid spec is not processed, the errors are reported in the Errored condition
+	if errs := utils.ValidateKanaryStatefulset(instance); len(errs) > 0 {
+		err = utilerrors.NewAggregate(errs)
+		reqLogger.Error(err, "invalid KanaryStatefulset spec")
+		return updateKanaryStatefulsetStatus(r.client, reqLogger, instance, metav1.Now(), reconcile.Result{}, err)
+	}
+
 	// Check if the deployment already exists, if not create a new one
 	// deployment, needsReturn, result, err := r.manageDeploymentCreationFunc(reqLogger, instance, utils.GetDeploymentName(instance), utils.NewDeploymentFromKanaryStatefulsetTemplate)
 	// if needsReturn {
@@ -153,6 +205,15 @@ func (r *ReconcileKanaryStatefulset) Reconcile(request reconcile.Request) (recon
 	return strategy.Apply(r.client, reqLogger, instance, deployment, canarydeployment, statefulset)
 }
 
+// manageDeletion reverts the faults injected by the chaos phase, then removes the chaos finalizer
+func (r *ReconcileKanaryStatefulset) manageDeletion(reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset) (reconcile.Result, error) {
+	if err := validation.RevertChaos(r.client, reqLogger, kd, kd.Status.DeepCopy(), time.Now()); err != nil {
+		reqLogger.Error(err, "failed to revert the chaos actions")
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{}, nil
+}
+
 func (r *ReconcileKanaryStatefulset) manageCanaryDeploymentCreation(reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, name string) (*appsv1beta1.Deployment, bool, reconcile.Result, error) {
 	// check that the deployment template was not updated since the creation
 	currentHash, err := comparison.GenerateMD5DeploymentSpec(&kd.Spec.Template.Spec)
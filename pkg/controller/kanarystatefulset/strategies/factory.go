@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 
@@ -59,14 +60,28 @@ func NewStrategy(spec *kanaryv1alpha1.KanaryStatefulsetSpec) (Interface, error)
 	default:
 	}
 
-	var validationsImpls []validation.Interface
-	for _, v := range spec.Validations.Items {
+	var validationsImpls []validationItem
+	for i, v := range spec.Validations.Items {
 		if v.Manual != nil {
-			validationsImpls = append(validationsImpls, validation.NewManual(&spec.Validations, &v))
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewManual(&spec.Validations, &v, i)})
 		} else if v.LabelWatch != nil {
-			validationsImpls = append(validationsImpls, validation.NewLabelWatch(&spec.Validations, &v))
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewLabelWatch(&spec.Validations, &v)})
 		} else if v.PromQL != nil {
-			validationsImpls = append(validationsImpls, validation.NewPromql(&spec.Validations, &v))
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewPromql(&spec.Validations, &v, i)})
+		} else if v.Metrics != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewMetrics(&spec.Validations, &v)})
+		} else if v.ResourceUsage != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewResourceUsage(&spec.Validations, &v)})
+		} else if v.Logs != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewLogs(&spec.Validations, &v, i)})
+		} else if v.Events != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewEvents(&spec.Validations, &v)})
+		} else if v.Job != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewJob(&spec.Validations, &v, i)})
+		} else if v.Alerts != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewAlerts(&spec.Validations, &v)})
+		} else if v.SLO != nil {
+			validationsImpls = append(validationsImpls, validationItem{index: i, impl: validation.NewSLO(&spec.Validations, &v)})
 		}
 	}
 
@@ -81,10 +96,16 @@ func NewStrategy(spec *kanaryv1alpha1.KanaryStatefulsetSpec) (Interface, error)
 type strategy struct {
 	scale               map[scale.Interface]bool
 	traffic             map[traffic.Interface]bool
-	validations         []validation.Interface
+	validations         []validationItem
 	subResourceDisabled bool
 }
 
+// validationItem associates a validation implementation to its index in spec.validations.items
+type validationItem struct {
+	index int
+	impl  validation.Interface
+}
+
 func (s *strategy) Apply(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canarydep *appsv1beta1.Deployment, statefulst *kruisev1alpha1.StatefulSet) (result reconcile.Result, err error) {
 	var newStatus *kanaryv1alpha1.KanaryStatefulsetStatus
 	newStatus, result, err = s.process(kclient, reqLogger, kd, dep, canarydep, statefulst)
@@ -172,28 +193,91 @@ func (s *strategy) process(kclient client.Client, reqLogger logr.Logger, kd *kan
 
 		//Run validation for all strategies
 		var results []*validation.Result
+		var checked []validationItem
 		var errs []error
+		providerErrors := map[int]error{}
 		for _, validationItem := range s.validations {
 			var result *validation.Result
-			result, err := validationItem.Validation(kclient, reqLogger, kd, dep, canarydep, sts)
+			result, err := validationItem.impl.Validation(kclient, reqLogger, kd, dep, canarydep, sts)
 			if err != nil {
+				// with an onProviderError policy, the error is recorded as an outage and the item is skipped
+				if kd.Spec.Validations.Items[validationItem.index].OnProviderError != nil {
+					providerErrors[validationItem.index] = err
+					continue
+				}
 				errs = append(errs, err)
 			}
+			checked = append(checked, validationItem)
 			results = append(results, result)
 		}
 		if len(errs) > 0 {
-			return &kd.Status, reconcile.Result{Requeue: true}, utilerrors.NewAggregate(errs)
+			// return a copy of the status, in order to save the Errored condition
+			return kd.Status.DeepCopy(), reconcile.Result{Requeue: true}, utilerrors.NewAggregate(errs)
+		}
+
+		// Record the checks and update the failure tolerance counters, a tolerated failure is not reported as failed
+		status := kd.Status.DeepCopy()
+		now := time.Now()
+		var outageFailures []string
+		for _, item := range s.validations {
+			vstatus := utils.GetValidationStatus(status, item.index)
+			err, ok := providerErrors[item.index]
+			if !ok {
+				validation.RecordProviderRecovery(vstatus, now)
+				continue
+			}
+			reqLogger.Info("Check Validation", "Provider-Error", err.Error(), "index", item.index)
+			validation.RecordProviderError(vstatus, err, now)
+			if failure := validation.GetOutageFailure(&kd.Spec.Validations.Items[item.index], vstatus); failure != "" {
+				if validation.IsAdvisory(&kd.Spec.Validations, item.index) {
+					vstatus.Warning = failure
+					continue
+				}
+				outageFailures = append(outageFailures, failure)
+			}
+		}
+		counted := false
+		for i, result := range results {
+			index := checked[i].index
+			vstatus := utils.GetValidationStatus(status, index)
+			// a check between two periodic checks is not recorded, its failure is only reported if the next one would not be tolerated
+			if !validation.IsCheckDue(&kd.Spec.Validations, vstatus, now) {
+				validation.ApplyTolerance(&kd.Spec.Validations.Items[index], vstatus.DeepCopy(), result)
+				continue
+			}
+			counted = true
+			validation.RecordCheck(&kd.Spec.Validations.Items[index], vstatus, result, now)
+			validation.ApplyTolerance(&kd.Spec.Validations.Items[index], vstatus, result)
+			setWarning(&kd.Spec.Validations, index, vstatus, result)
+		}
+		validation.RecordApproval(kd, status)
+		if kd.Spec.Validations.Score != nil && counted {
+			if score, ok := validation.ComputeScore(&kd.Spec.Validations, getIndexes(checked), results); ok {
+				validation.RecordScore(status, score, now)
+			}
+		}
+		validation.RecordValidationDeadline(kd, status)
+
+		// The chaos phase runs alongside the validation items
+		chaosFailures, err := validation.RunChaos(kclient, reqLogger, kd, sts, status, now)
+		if err != nil {
+			return kd.Status.DeepCopy(), reconcile.Result{Requeue: true}, err
 		}
 
 		var forceSucceededNow bool
 		var failMessages string
-		failMessages, forceSucceededNow = computeStatus(results)
+		failMessages, forceSucceededNow = computeStatus(&kd.Spec.Validations, checked, results)
+		if failures := append(chaosFailures, outageFailures...); len(failures) > 0 {
+			failMessages = strings.Join(append(failures, failMessages), ",")
+			failMessages = strings.TrimSuffix(failMessages, ",")
+		}
+		// an item without result can't agree with the forced success
+		forceSucceededNow = forceSucceededNow && len(providerErrors) == 0
 		failed := failMessages != ""
 
 		// If any strategy fails, the kanary should fail
 		if failed {
 			reqLogger.Info("Check Validation failed")
-			status := kd.Status.DeepCopy()
 			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.FailedKanaryStatefulsetConditionType, corev1.ConditionTrue, fmt.Sprintf("KanaryStatefulset failed, %s", failMessages), false)
 			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with failure detected", false)
 			reqLogger.Info("Check Validation", "in failed", failMessages, "updated status", fmt.Sprintf("%#v", status))
@@ -203,35 +287,134 @@ func (s *strategy) process(kclient client.Client, reqLogger logr.Logger, kd *kan
 		// So there is no failure, does someone force for an early Success ?
 		if forceSucceededNow {
 			reqLogger.Info("Check Validation success")
-			status := kd.Status.DeepCopy()
 			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.SucceededKanaryStatefulsetConditionType, corev1.ConditionTrue, "Forced Success", false)
 			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with success forced", false)
 			return status, reconcile.Result{Requeue: true}, nil
 		}
 
+		// No failure, are the early success conditions met ?
+		earlySucceeded, earlySuccessDetails, err := validation.CheckEarlySuccess(kclient, kd, sts, status, now)
+		if err != nil {
+			// the early success is an optimization, the validation continues without it
+			reqLogger.Error(err, "Check Validation early success")
+		}
+		if earlySucceeded {
+			reqLogger.Info("Check Validation early success", "conditions", strings.Join(earlySuccessDetails, ", "))
+			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.SucceededKanaryStatefulsetConditionType, corev1.ConditionTrue, fmt.Sprintf("Early Success, %s", strings.Join(earlySuccessDetails, ", ")), false)
+			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with early success", false)
+			return status, reconcile.Result{Requeue: true}, nil
+		}
+
 		// No failure, so if we have not reached the validation deadline, let's requeue for next validation
 		if !validationDeadlineDone && !failed {
 			reqLogger.Info("Check Validation others")
 			d := validation.GetNextValidationCheckDuration(kd)
 			reqLogger.Info("Check Validation", "Periodic-Requeue", d)
-			return &kd.Status, reconcile.Result{RequeueAfter: d}, nil
+			return status, reconcile.Result{RequeueAfter: d}, nil
 		}
 
 		// Validation completed and everything is ok while we have reached the end of the validation period...
 
+		// A metrics provider is unreachable, let's wait for its recovery or for the end of the tolerated outage
+		if validation.IsWaitingForProvider(&kd.Spec.Validations, status) {
+			reqLogger.Info("Check Validation", "Outage-Requeue", kd.Spec.Validations.MaxIntervalPeriod.Duration)
+			return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+		}
+
+		// The chaos phase is not over, let's wait for the end of its actions
+		if validation.IsChaosPending(kd, status) {
+			reqLogger.Info("Check Validation", "Chaos-Requeue", kd.Spec.Validations.MaxIntervalPeriod.Duration)
+			return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+		}
+
+		// A validation item is still recovering from tolerated failures, let's wait for the next check
+		if validation.IsRecovering(&kd.Spec.Validations, status) {
+			reqLogger.Info("Check Validation", "Recovering-Requeue", kd.Spec.Validations.MaxIntervalPeriod.Duration)
+			return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+		}
+
+		// The confidence criterion is not met, let's extend the validation period up to maxValidationPeriod
+		reasons, err := validation.CheckConfidence(kclient, kd, sts, status)
+		if err != nil {
+			return kd.Status.DeepCopy(), reconcile.Result{Requeue: true}, err
+		}
+		if len(reasons) > 0 {
+			if extension := validation.GetConfidenceExtension(kd, status); extension > 0 {
+				validation.RecordConfidenceExtension(status, extension, reasons, now)
+				validation.RecordValidationDeadline(kd, status)
+				reqLogger.Info("Check Validation", "Confidence-Extension", extension, "reason", strings.Join(reasons, ", "))
+				return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+			}
+			reqLogger.Info("Check Validation", "Confidence-Not-Met", strings.Join(reasons, ", "))
+		}
+
+		// A validation item could not conclude, let's apply the inconclusive action
+		if inconclusive := validation.GetInconclusiveItems(&kd.Spec.Validations, status); len(inconclusive) > 0 {
+			action := validation.GetInconclusiveAction(&kd.Spec.Validations)
+			if validation.CanExtendInconclusive(&kd.Spec.Validations, status) {
+				status.InconclusiveExtensions++
+				validation.RecordValidationDeadline(kd, status)
+				reqLogger.Info("Check Validation", "Inconclusive-Extension", status.InconclusiveExtensions)
+				return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+			}
+			if action != kanaryv1alpha1.PassKanaryStatefulsetSpecValidationInconclusiveAction {
+				failMessage := fmt.Sprintf("validation items %s are inconclusive at the end of the validation period", strings.Join(inconclusive, ","))
+				if status.InconclusiveExtensions > 0 {
+					failMessage = fmt.Sprintf("%s, after %d extension(s)", failMessage, status.InconclusiveExtensions)
+				}
+				utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.FailedKanaryStatefulsetConditionType, corev1.ConditionTrue, fmt.Sprintf("KanaryStatefulset failed, %s", failMessage), false)
+				utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with inconclusive items", false)
+				return status, reconcile.Result{Requeue: true}, nil
+			}
+			reqLogger.Info("Check Validation", "Inconclusive-Pass", strings.Join(inconclusive, ","))
+		}
+
+		// The score is below the pass threshold, let's apply the marginal action
+		if validation.IsScoreMarginal(&kd.Spec.Validations, status) {
+			score, _ := validation.GetAverageScore(status)
+			if validation.CanExtendValidation(&kd.Spec.Validations, status) {
+				status.Score.Extensions++
+				validation.RecordValidationDeadline(kd, status)
+				reqLogger.Info("Check Validation", "Marginal-Score-Extension", status.Score.Extensions)
+				return status, reconcile.Result{RequeueAfter: kd.Spec.Validations.MaxIntervalPeriod.Duration}, nil
+			}
+			if kd.Spec.Validations.Score.MarginalAction == kanaryv1alpha1.ManualApprovalKanaryStatefulsetSpecValidationScoreMarginalAction {
+				// No automation, no requeue, wait for the decision of the manual validation item
+				utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionTrue, fmt.Sprintf("Waiting for a manual approval, score %s is marginal", utils.FormatScore(score)), false)
+				return status, reconcile.Result{}, nil
+			}
+			failMessage := fmt.Sprintf("score %s is marginal after %d extension(s) of the validation period", utils.FormatScore(score), status.Score.Extensions)
+			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.FailedKanaryStatefulsetConditionType, corev1.ConditionTrue, fmt.Sprintf("KanaryStatefulset failed, %s", failMessage), false)
+			utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with a marginal score", false)
+			return status, reconcile.Result{Requeue: true}, nil
+		}
+
 		//Particular case of the manual strategy with None as StatusAfterDeadline
 		if validation.IsStatusAfterDeadlineNone(kd) {
 			// No automation, no requeue, wait for manual input
-			return &kd.Status, reconcile.Result{}, nil
+			return status, reconcile.Result{}, nil
 		}
 
 		//Looks like it is a success for the kanary!
-		status := kd.Status.DeepCopy()
 		utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.SucceededKanaryStatefulsetConditionType, corev1.ConditionTrue, "Validation ended with success", false)
 		utils.UpdateKanaryStatefulsetStatusCondition(status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionFalse, "Validation ended with success", false)
 		return status, reconcile.Result{Requeue: true}, nil
 	}
 
+	// The validation is over, the faults still injected by the chaos phase are reverted
+	if validation.HasRunningChaos(&kd.Status) {
+		status := kd.Status.DeepCopy()
+		if err := validation.RevertChaos(kclient, reqLogger, kd, status, time.Now()); err != nil {
+			return kd.Status.DeepCopy(), reconcile.Result{Requeue: true}, err
+		}
+		return status, reconcile.Result{Requeue: true}, nil
+	}
+
+	// The validation is over, the Jobs of the job validation items are deleted
+	if err := validation.DeleteJobs(kclient, reqLogger, kd); err != nil {
+		return kd.Status.DeepCopy(), reconcile.Result{Requeue: true}, err
+	}
+
 	//In case of succeeded kanary, we may need to update the deployment
 	if utils.IsKanaryStatefulsetSucceeded(&kd.Status) {
 		reqLogger.Info("check kanary success")
@@ -259,32 +442,87 @@ const (
 	unknownFailureReason = "unknown failure reason"
 )
 
-func computeStatus(results []*validation.Result) (failMessages string, forceSuccessNow bool) {
-	if len(results) == 0 {
-		return "", forceSuccessNow
-	}
+// computeStatus combines the results of the validation items, the advisory items are ignored.
+// If a policy is defined, it is evaluated to know if the failed items fail the KanaryStatefulset.
+// If a score is defined, the KanaryStatefulset fails when the score is below the marginal threshold
+// or when a manual item fails, and only the manual items can force an early success.
+func computeStatus(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, validations []validationItem, results []*validation.Result) (failMessages string, forceSuccessNow bool) {
 	forceSuccessNow = true
 
+	count, manualCount := 0, 0
+	manualFailed := false
 	comments := []string{}
-	for _, result := range results {
+	failedByName := map[string]bool{}
+	for i, result := range results {
+		index := validations[i].index
+		if validation.IsAdvisory(list, index) {
+			continue
+		}
+		count++
+		isManual := list.Items[index].Manual != nil
+		if isManual {
+			manualCount++
+		}
 
-		if !result.ForceSuccessNow {
+		if !result.ForceSuccessNow && (list.Score == nil || isManual) {
 			forceSuccessNow = false
 		}
 		if result.IsFailed {
-			if result.Comment != "" {
-				comments = append(comments, result.Comment)
-			} else {
-				comments = append(comments, unknownFailureReason)
-			}
+			failedByName[list.Items[index].Name] = true
+			manualFailed = manualFailed || isManual
+			comments = append(comments, getFailureComment(result))
+		}
+	}
+	if count == 0 || (list.Score != nil && manualCount == 0) {
+		forceSuccessNow = false
+	}
+	if count == 0 {
+		return "", false
+	}
+
+	failed := len(comments) > 0
+	switch {
+	case list.Score != nil:
+		failed = manualFailed
+		if score, ok := validation.ComputeScore(list, getIndexes(validations), results); ok && list.Score.Marginal != nil && score < *list.Score.Marginal {
+			failed = true
+			comments = append([]string{fmt.Sprintf("score %s is below marginal %v", utils.FormatScore(score), *list.Score.Marginal)}, comments...)
 		}
+	case list.Policy != nil:
+		failed = validation.IsPolicyFailed(list.Policy, failedByName)
 	}
-	if len(comments) > 0 {
+	if failed {
+		if len(comments) == 0 {
+			comments = append(comments, unknownFailureReason)
+		}
 		failMessages = strings.Join(comments, ",")
 	}
 	return failMessages, forceSuccessNow
 }
 
+// setWarning reports the failure of an advisory item in its status
+func setWarning(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, index int, status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, result *validation.Result) {
+	status.Warning = ""
+	if result != nil && result.IsFailed && validation.IsAdvisory(list, index) {
+		status.Warning = getFailureComment(result)
+	}
+}
+
+func getIndexes(validations []validationItem) []int {
+	indexes := make([]int, len(validations))
+	for i, v := range validations {
+		indexes[i] = v.index
+	}
+	return indexes
+}
+
+func getFailureComment(result *validation.Result) string {
+	if result.Comment != "" {
+		return result.Comment
+	}
+	return unknownFailureReason
+}
+
 func needReturn(result *reconcile.Result) bool {
 	if result.Requeue || int64(result.RequeueAfter) > int64(0) {
 		return true
@@ -2,13 +2,28 @@ package strategies
 
 import (
 	"testing"
+	"time"
 
+	"github.com/go-logr/logr"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
 	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies/validation"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils"
 )
 
 func Test_computeStatus(t *testing.T) {
 	type args struct {
-		results []*validation.Result
+		list        *kanaryv1alpha1.KanaryStatefulsetSpecValidationList
+		validations []validationItem
+		results     []*validation.Result
 	}
 	tests := []struct {
 		name               string
@@ -122,10 +137,164 @@ func Test_computeStatus(t *testing.T) {
 			wantFailureMessage: "",
 			wantForceSuccess:   true,
 		},
+		{
+			name: "advisory failure",
+			args: args{
+				list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+					{Name: "latency"},
+					{Name: "logs", Advisory: true},
+				}},
+				results: []*validation.Result{
+					{IsFailed: false, ForceSuccessNow: true},
+					{IsFailed: true, Comment: "too many errors"},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   true,
+		},
+		{
+			name: "only advisory items",
+			args: args{
+				list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+					{Name: "logs", Advisory: true},
+				}},
+				results: []*validation.Result{
+					{IsFailed: false, ForceSuccessNow: true},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "policy latency or (errors and saturation), errors failed",
+			args: args{
+				list: newPolicyValidationList(),
+				results: []*validation.Result{
+					{IsFailed: false},
+					{IsFailed: true, Comment: "errors"},
+					{IsFailed: false},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "policy latency or (errors and saturation), errors and saturation failed",
+			args: args{
+				list: newPolicyValidationList(),
+				results: []*validation.Result{
+					{IsFailed: false},
+					{IsFailed: true, Comment: "errors"},
+					{IsFailed: true, Comment: "saturation"},
+				},
+			},
+			wantFailureMessage: "errors,saturation",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "policy latency or (errors and saturation), latency failed",
+			args: args{
+				list: newPolicyValidationList(),
+				results: []*validation.Result{
+					{IsFailed: true, Comment: "latency"},
+					{IsFailed: false},
+					{IsFailed: false},
+				},
+			},
+			wantFailureMessage: "latency",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "quorum 2 of 3, one failed",
+			args: args{
+				list: newQuorumValidationList(),
+				results: []*validation.Result{
+					{IsFailed: true, Comment: "latency"},
+					{IsFailed: false},
+					{IsFailed: false},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "quorum 2 of 3, two failed",
+			args: args{
+				list: newQuorumValidationList(),
+				results: []*validation.Result{
+					{IsFailed: true, Comment: "latency"},
+					{IsFailed: false},
+					{IsFailed: true},
+				},
+			},
+			wantFailureMessage: "latency," + unknownFailureReason,
+			wantForceSuccess:   false,
+		},
+		{
+			name: "score above marginal",
+			args: args{
+				list: newScoreValidationList(),
+				results: []*validation.Result{
+					{IsFailed: false},
+					{IsFailed: true, Comment: "errors"},
+					{IsFailed: false},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "score below marginal",
+			args: args{
+				list: newScoreValidationList(),
+				results: []*validation.Result{
+					{IsFailed: true, Comment: "latency"},
+					{IsFailed: false},
+					{IsFailed: false},
+				},
+			},
+			wantFailureMessage: "score 25 is below marginal 75,latency",
+			wantForceSuccess:   false,
+		},
+		{
+			name: "score with manual approval",
+			args: args{
+				list: newScoreValidationList(),
+				results: []*validation.Result{
+					{IsFailed: false},
+					{IsFailed: true, Comment: "errors"},
+					{ForceSuccessNow: true},
+				},
+			},
+			wantFailureMessage: "",
+			wantForceSuccess:   true,
+		},
+		{
+			name: "score with manual rejection",
+			args: args{
+				list: newScoreValidationList(),
+				results: []*validation.Result{
+					{IsFailed: false},
+					{IsFailed: false},
+					{IsFailed: true, Comment: "manual.status=invalid"},
+				},
+			},
+			wantFailureMessage: "manual.status=invalid",
+			wantForceSuccess:   false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotFailMessage, gotForceSuccessNow := computeStatus(tt.args.results)
+			list, validations := tt.args.list, tt.args.validations
+			if list == nil {
+				list = &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: make([]kanaryv1alpha1.KanaryStatefulsetSpecValidation, len(tt.args.results))}
+			}
+			if validations == nil {
+				for i := range tt.args.results {
+					validations = append(validations, validationItem{index: i})
+				}
+			}
+			gotFailMessage, gotForceSuccessNow := computeStatus(list, validations, tt.args.results)
 			if gotFailMessage != tt.wantFailureMessage {
 				t.Errorf("computeStatus() success = %v, want %v", gotFailMessage, tt.wantFailureMessage)
 			}
@@ -135,3 +304,117 @@ func Test_computeStatus(t *testing.T) {
 		})
 	}
 }
+
+// fakeValidation returns the same result at each check
+type fakeValidation struct {
+	result validation.Result
+}
+
+func (f *fakeValidation) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*validation.Result, error) {
+	result := f.result
+	return &result, nil
+}
+
+func Test_strategy_process_deadline(t *testing.T) {
+	log := logf.Log.WithName("Test_strategy_process_deadline")
+	// the queries are not run, the checks return the results of the test case
+	errorsPromQL := &kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{Query: "errors"}
+	latencyPromQL := &kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{Query: "latency"}
+	tests := []struct {
+		name          string
+		validations   kanaryv1alpha1.KanaryStatefulsetSpecValidationList
+		results       []validation.Result
+		wantSucceeded bool
+	}{
+		{
+			name: "advisory item failing after the deadline",
+			validations: kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "errors", PromQL: errorsPromQL}, {Name: "latency", Advisory: true, PromQL: latencyPromQL}},
+			},
+			results:       []validation.Result{{}, {IsFailed: true, Comment: "latency too high"}},
+			wantSucceeded: true,
+		},
+		{
+			name: "low weight item failing after the deadline with a passing score",
+			validations: kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+					{Name: "latency", Weight: kanaryv1alpha1.NewInt32(3), PromQL: latencyPromQL},
+					{Name: "errors", PromQL: errorsPromQL},
+				},
+				Score: &kanaryv1alpha1.KanaryStatefulsetSpecValidationScore{Pass: kanaryv1alpha1.NewFloat64(70), Marginal: kanaryv1alpha1.NewFloat64(50)},
+			},
+			results:       []validation.Result{{}, {IsFailed: true, Comment: "errors too high"}},
+			wantSucceeded: true,
+		},
+		{
+			name: "item recovering from a tolerated failure after the deadline",
+			validations: kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "errors", FailureThreshold: kanaryv1alpha1.NewInt32(3), PromQL: errorsPromQL}},
+			},
+			results:       []validation.Result{{IsFailed: true, Comment: "errors too high"}},
+			wantSucceeded: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := metav1.NewTime(time.Now().Add(-time.Hour))
+			kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "bar", "", 1, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{StartTime: &start, Validations: &tt.validations})
+			utils.UpdateKanaryStatefulsetStatusCondition(&kd.Status, metav1.Now(), kanaryv1alpha1.RunningKanaryStatefulsetConditionType, corev1.ConditionTrue, "Validation Started", false)
+			s := &strategy{}
+			for i := range tt.results {
+				s.validations = append(s.validations, validationItem{index: i, impl: &fakeValidation{result: tt.results[i]}})
+			}
+			status, _, err := s.process(fake.NewFakeClient(), log, kd, nil, nil, nil)
+			if err != nil {
+				t.Fatalf("strategy.process() error = %v", err)
+			}
+			if got := utils.IsKanaryStatefulsetSucceeded(status); got != tt.wantSucceeded {
+				t.Errorf("strategy.process() succeeded = %v, want %v, conditions = %#v", got, tt.wantSucceeded, status.Conditions)
+			}
+			if utils.IsKanaryStatefulsetFailed(status) {
+				t.Errorf("strategy.process() should not fail, conditions = %#v", status.Conditions)
+			}
+		})
+	}
+}
+
+// newPolicyValidationList returns a validation list that fails if latency fails or if errors and saturation fail
+func newPolicyValidationList() *kanaryv1alpha1.KanaryStatefulsetSpecValidationList {
+	return &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}, {Name: "errors"}, {Name: "saturation"}},
+		Policy: &kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+			Operator: kanaryv1alpha1.OrKanaryStatefulsetSpecValidationPolicyOperator,
+			Items:    []string{"latency"},
+			Groups: []kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+				{Operator: kanaryv1alpha1.AndKanaryStatefulsetSpecValidationPolicyOperator, Items: []string{"errors", "saturation"}},
+			},
+		},
+	}
+}
+
+// newQuorumValidationList returns a validation list where 2 of the 3 items must pass
+func newQuorumValidationList() *kanaryv1alpha1.KanaryStatefulsetSpecValidationList {
+	return &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}, {Name: "errors"}, {Name: "saturation"}},
+		Policy: &kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+			Operator: kanaryv1alpha1.QuorumKanaryStatefulsetSpecValidationPolicyOperator,
+			Items:    []string{"latency", "errors", "saturation"},
+			Quorum:   kanaryv1alpha1.NewInt32(2),
+		},
+	}
+}
+
+// newScoreValidationList returns a validation list with a weighted score, latency weighs 3 times more than errors
+func newScoreValidationList() *kanaryv1alpha1.KanaryStatefulsetSpecValidationList {
+	return &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+			{Name: "latency", Weight: kanaryv1alpha1.NewInt32(3)},
+			{Name: "errors"},
+			{Name: "approval", Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}},
+		},
+		Score: &kanaryv1alpha1.KanaryStatefulsetSpecValidationScore{
+			Pass:     kanaryv1alpha1.NewFloat64(95),
+			Marginal: kanaryv1alpha1.NewFloat64(75),
+		},
+	}
+}
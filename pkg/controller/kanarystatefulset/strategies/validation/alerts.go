@@ -0,0 +1,226 @@
+package validation
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// alertsAPIPath path of the alerts endpoint of the Alertmanager v2 API
+	alertsAPIPath = "/api/v2/alerts"
+	// alertsRequestTimeout timeout of the requests to the Alertmanager
+	alertsRequestTimeout = 10 * time.Second
+	// alertSummaryAnnotation annotation of the alert reported in the failure message
+	alertSummaryAnnotation = "summary"
+)
+
+// NewAlerts returns new validation.Alerts instance
+func NewAlerts(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+	return &alertsImpl{
+		validationSpec: *s.Alerts,
+		dryRun:         list.NoUpdate,
+		client:         &http.Client{Timeout: alertsRequestTimeout},
+	}
+}
+
+type alertsImpl struct {
+	validationSpec kanaryv1alpha1.KanaryStatefulsetSpecValidationAlerts
+	dryRun         bool
+	client         *http.Client
+}
+
+// alert fields of an alert returned by the Alertmanager v2 API
+type alert struct {
+	Labels      map[string]string `json:"labels"`
+	Annotations map[string]string `json:"annotations"`
+	StartsAt    time.Time         `json:"startsAt"`
+	EndsAt      time.Time         `json:"endsAt"`
+	Status      struct {
+		State string `json:"state"`
+	} `json:"status"`
+}
+
+// alertMatcher rendered alert matcher
+type alertMatcher struct {
+	name    string
+	value   string
+	isRegex bool
+	isEqual bool
+	regex   *regexp.Regexp
+}
+
+func (a *alertsImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{}
+
+	templateData, err := NewQueryTemplateData(kclient, kd, sts)
+	if err != nil {
+		return result, err
+	}
+	matchers, err := renderAlertMatchers(a.validationSpec.Matchers, templateData)
+	if err != nil {
+		return result, err
+	}
+	result.Query = formatAlertMatchers(matchers)
+
+	headers, err := getHTTPHeaders(kclient, kd.Namespace, a.validationSpec.Headers, a.validationSpec.SecretHeaders)
+	if err != nil {
+		return result, err
+	}
+	alerts, err := a.getAlerts(matchers, headers)
+	if err != nil {
+		return result, err
+	}
+
+	start := GetValidationStart(kd)
+	countByName := map[string]int{}
+	summaryByName := map[string]string{}
+	for _, al := range alerts {
+		if al.Status.State != "" && al.Status.State != "active" {
+			continue
+		}
+		if !al.EndsAt.IsZero() && al.EndsAt.Before(start) {
+			continue
+		}
+		if !matchAlert(matchers, al.Labels) {
+			continue
+		}
+		name := al.Labels["alertname"]
+		countByName[name]++
+		if summaryByName[name] == "" {
+			summaryByName[name] = al.Annotations[alertSummaryAnnotation]
+		}
+	}
+
+	names := []string{}
+	for name := range countByName {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result.Values = map[string]string{}
+	var comments []string
+	for _, name := range names {
+		result.Values[name] = strconv.Itoa(countByName[name])
+		comment := name
+		if summaryByName[name] != "" {
+			comment = fmt.Sprintf("%s: %s", name, summaryByName[name])
+		}
+		comments = append(comments, comment)
+	}
+
+	if len(comments) > 0 {
+		result.IsFailed = true
+		result.Comment = "firing alerts, " + strings.Join(comments, "; ")
+		reqLogger.Info("Alerts validation", "detection", len(comments))
+	}
+	return result, nil
+}
+
+// getAlerts returns the active alerts matching the matchers, not silenced nor inhibited. The alerts resolved
+// since the previous check are not returned by the Alertmanager.
+func (a *alertsImpl) getAlerts(matchers []alertMatcher, headers map[string]string) ([]alert, error) {
+	query := url.Values{}
+	query.Set("active", "true")
+	query.Set("silenced", "false")
+	query.Set("inhibited", "false")
+	for _, matcher := range matchers {
+		query.Add("filter", matcher.String())
+	}
+	request, err := http.NewRequest(http.MethodGet, strings.TrimSuffix(a.validationSpec.URL, "/")+alertsAPIPath+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create the alertmanager request: %v", err)
+	}
+	for k, v := range headers {
+		request.Header.Set(k, v)
+	}
+	response, err := a.client.Do(request)
+	if err != nil {
+		return nil, fmt.Errorf("error while contacting the alertmanager: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the alertmanager did not respond Ok (200) but %d", response.StatusCode)
+	}
+	body, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("can't read response buffer %v", err)
+	}
+	alerts := []alert{}
+	if err = json.Unmarshal(body, &alerts); err != nil {
+		return nil, fmt.Errorf("decoding alertmanager response failed: %v", err)
+	}
+	return alerts, nil
+}
+
+// renderAlertMatchers renders the values of the matchers as go templates
+func renderAlertMatchers(matchers []kanaryv1alpha1.AlertMatcher, data *QueryTemplateData) ([]alertMatcher, error) {
+	rendered := []alertMatcher{}
+	for _, m := range matchers {
+		value, err := RenderQuery(m.Value, data)
+		if err != nil {
+			return nil, fmt.Errorf("alerts matcher %s: %v", m.Name, err)
+		}
+		matcher := alertMatcher{name: m.Name, value: value, isRegex: m.IsRegex, isEqual: m.IsEqual == nil || *m.IsEqual}
+		if matcher.isRegex {
+			if matcher.regex, err = regexp.Compile("^(?:" + value + ")$"); err != nil {
+				return nil, fmt.Errorf("alerts matcher %s: invalid regular expression: %v", m.Name, err)
+			}
+		}
+		rendered = append(rendered, matcher)
+	}
+	return rendered, nil
+}
+
+// matchAlert returns true if the labels of the alert match all the matchers. The matchers are also sent to
+// the Alertmanager, they are checked again in case the filter is not supported.
+func matchAlert(matchers []alertMatcher, alertLabels map[string]string) bool {
+	for _, matcher := range matchers {
+		value := alertLabels[matcher.name]
+		matched := value == matcher.value
+		if matcher.isRegex {
+			matched = matcher.regex.MatchString(value)
+		}
+		if matched != matcher.isEqual {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the matcher with the Alertmanager filter syntax, for example pod=~"myapp-3|myapp-4"
+func (m alertMatcher) String() string {
+	var operator string
+	switch {
+	case m.isEqual && m.isRegex:
+		operator = "=~"
+	case m.isEqual:
+		operator = "="
+	case m.isRegex:
+		operator = "!~"
+	default:
+		operator = "!="
+	}
+	return m.name + operator + strconv.Quote(m.value)
+}
+
+func formatAlertMatchers(matchers []alertMatcher) string {
+	filters := []string{}
+	for _, matcher := range matchers {
+		filters = append(filters, matcher.String())
+	}
+	return "{" + strings.Join(filters, ",") + "}"
+}
@@ -0,0 +1,138 @@
+package validation
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	test "github.com/k8s-kanary/kanary/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func Test_alertsImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_alertsImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+	)
+	now := time.Now()
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	podLabels := func(revision string) map[string]string {
+		return map[string]string{"app": name, appsv1.ControllerRevisionHashLabelKey: revision}
+	}
+	kclient := fake.NewFakeClient([]runtime.Object{
+		test.PodGen(name+"-0", namespace, podLabels("v1"), nil, true, true),
+		test.PodGen(name+"-1", namespace, podLabels("v2"), nil, true, true),
+		test.PodGen(name+"-2", namespace, podLabels("v2"), nil, true, true),
+	}...)
+
+	newAlert := func(alertName, pod, state, summary string, endsAt time.Duration) map[string]interface{} {
+		return map[string]interface{}{
+			"labels":      map[string]string{"alertname": alertName, "namespace": namespace, "pod": pod},
+			"annotations": map[string]string{"summary": summary},
+			"startsAt":    now.Add(-time.Hour).Format(time.RFC3339),
+			"endsAt":      now.Add(endsAt).Format(time.RFC3339),
+			"status":      map[string]interface{}{"state": state},
+		}
+	}
+	alerts := []map[string]interface{}{
+		newAlert("HighLatency", name+"-1", "active", "p99 latency above 500ms", 5*time.Minute),
+		newAlert("HighLatency", name+"-2", "active", "p99 latency above 500ms", 5*time.Minute),
+		newAlert("HighErrorRate", name+"-2", "active", "", 5*time.Minute),
+		// ignored: stable pod, silenced, ended before the validation start
+		newAlert("PodCrashLooping", name+"-0", "active", "pod is crash looping", 5*time.Minute),
+		newAlert("PodCrashLooping", name+"-1", "suppressed", "pod is crash looping", 5*time.Minute),
+		newAlert("PodNotReady", name+"-1", "active", "pod is not ready", -20*time.Minute),
+	}
+
+	var filters []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != alertsAPIPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		filters = r.URL.Query()["filter"]
+		_ = json.NewEncoder(w).Encode(alerts)
+	}))
+	defer server.Close()
+
+	canaryMatchers := []kanaryv1alpha1.AlertMatcher{
+		{Name: "namespace", Value: "{{.Namespace}}"},
+		{Name: "pod", Value: "{{.CanaryPodsRegex}}", IsRegex: true},
+	}
+	tests := []struct {
+		name        string
+		spec        kanaryv1alpha1.KanaryStatefulsetSpecValidationAlerts
+		want        *Result
+		wantFilters []string
+		wantErr     bool
+	}{
+		{
+			name: "alerts firing on the canary pods",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationAlerts{URL: server.URL, Matchers: canaryMatchers},
+			want: &Result{
+				IsFailed: true,
+				Comment:  "firing alerts, HighErrorRate; HighLatency: p99 latency above 500ms",
+				Query:    `{namespace="kanary",pod=~"foo-1|foo-2"}`,
+				Values:   map[string]string{"HighErrorRate": "1", "HighLatency": "2"},
+			},
+			wantFilters: []string{`namespace="kanary"`, `pod=~"foo-1|foo-2"`},
+		},
+		{
+			name: "negative matcher",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationAlerts{URL: server.URL, Matchers: append(canaryMatchers,
+				kanaryv1alpha1.AlertMatcher{Name: "alertname", Value: "High.*", IsRegex: true, IsEqual: kanaryv1alpha1.NewBool(false)},
+			)},
+			want: &Result{
+				Query:  `{namespace="kanary",pod=~"foo-1|foo-2",alertname!~"High.*"}`,
+				Values: map[string]string{},
+			},
+			wantFilters: []string{`namespace="kanary"`, `pod=~"foo-1|foo-2"`, `alertname!~"High.*"`},
+		},
+		{
+			name:    "alertmanager error",
+			spec:    kanaryv1alpha1.KanaryStatefulsetSpecValidationAlerts{URL: server.URL + "/unknown", Matchers: canaryMatchers},
+			want:    &Result{Query: `{namespace="kanary",pod=~"foo-1|foo-2"}`},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filters = nil
+			a := &alertsImpl{validationSpec: tt.spec, client: http.DefaultClient}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, name, defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.Spec.StatefulSetName = name
+			kd.CreationTimestamp = metav1.Time{Time: now.Add(-10 * time.Minute)}
+			got, err := a.Validation(kclient, log.WithValues("test:", tt.name), kd, nil, nil, sts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("alertsImpl.Validation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("alertsImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(filters, tt.wantFilters) {
+				t.Errorf("alertsImpl.Validation() filters = %v, want %v", filters, tt.wantFilters)
+			}
+		})
+	}
+}
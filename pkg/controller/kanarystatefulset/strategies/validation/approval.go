@@ -0,0 +1,159 @@
+package validation
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// approvalKey key of the signature of the decisions recorded by the admission webhook, nil if the webhook is not active
+var approvalKey []byte
+
+// ConfigureApprovalKey sets the key of the signature of the decisions recorded by the admission webhook.
+// Without key, the admission webhook is not active and the recorded decisions are ignored.
+func ConfigureApprovalKey(key []byte) {
+	approvalKey = key
+}
+
+// SignApprovals returns the signature of the decisions recorded in the annotations of the KanaryStatefulset
+func SignApprovals(key []byte, kd *kanaryv1alpha1.KanaryStatefulset) string {
+	mac := hmac.New(sha256.New, key)
+	for _, value := range []string{
+		kd.Namespace,
+		kd.Name,
+		kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey],
+		kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey],
+		kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey],
+		kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey],
+		kd.Annotations[kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey],
+	} {
+		mac.Write([]byte(value))
+		mac.Write([]byte{0})
+	}
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// IsApprovalSigned returns true if the decisions in the annotations of the KanaryStatefulset have been recorded by
+// the admission webhook, with the given key. It returns false without key.
+func IsApprovalSigned(key []byte, kd *kanaryv1alpha1.KanaryStatefulset) bool {
+	if len(key) == 0 {
+		return false
+	}
+	signature, err := base64.StdEncoding.DecodeString(kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey])
+	if err != nil || len(signature) == 0 {
+		return false
+	}
+	expected, _ := base64.StdEncoding.DecodeString(SignApprovals(key, kd))
+	return hmac.Equal(signature, expected)
+}
+
+// GetApproval returns the manual approval decision provided with the approval annotation. The decision is only
+// returned if it has been recorded by the admission webhook, with the approved-by and approved-at annotations
+// and a valid signature.
+func GetApproval(kd *kanaryv1alpha1.KanaryStatefulset) *kanaryv1alpha1.KanaryStatefulsetApproval {
+	if !IsApprovalSigned(approvalKey, kd) {
+		return nil
+	}
+	decision := kanaryv1alpha1.KanaryStatefulsetSpecValidationManualStatus(kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey])
+	if decision != kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus && decision != kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus {
+		return nil
+	}
+	approver := kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey]
+	if approver == "" {
+		return nil
+	}
+	approvedAt, err := time.Parse(time.RFC3339, kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey])
+	if err != nil {
+		return nil
+	}
+	return &kanaryv1alpha1.KanaryStatefulsetApproval{
+		Approver: approver,
+		Decision: decision,
+		Time:     metav1.NewTime(approvedAt),
+		Comment:  kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey],
+	}
+}
+
+// GetManualItemID returns the identifier of a manual validation item in the recorded decisions: its name, or its index
+func GetManualItemID(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, index int) string {
+	if name := list.Items[index].Name; name != "" {
+		return name
+	}
+	return fmt.Sprintf("items[%d]", index)
+}
+
+// ParseManualDecisions returns the decisions recorded in the manual decisions annotation, without checking the signature
+func ParseManualDecisions(kd *kanaryv1alpha1.KanaryStatefulset) []kanaryv1alpha1.KanaryStatefulsetApproval {
+	var decisions []kanaryv1alpha1.KanaryStatefulsetApproval
+	if value := kd.Annotations[kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey]; value != "" {
+		if err := json.Unmarshal([]byte(value), &decisions); err != nil {
+			return nil
+		}
+	}
+	return decisions
+}
+
+// GetManualDecisions returns the decisions taken with the status of the manual validation items. A decision is only
+// returned if it has been recorded by the admission webhook with a valid signature, and if the item still has its status.
+func GetManualDecisions(kd *kanaryv1alpha1.KanaryStatefulset) []kanaryv1alpha1.KanaryStatefulsetApproval {
+	if !IsApprovalSigned(approvalKey, kd) {
+		return nil
+	}
+	statusByID := map[string]kanaryv1alpha1.KanaryStatefulsetSpecValidationManualStatus{}
+	for i, item := range kd.Spec.Validations.Items {
+		if item.Manual != nil {
+			statusByID[GetManualItemID(&kd.Spec.Validations, i)] = item.Manual.Status
+		}
+	}
+	var decisions []kanaryv1alpha1.KanaryStatefulsetApproval
+	for _, decision := range ParseManualDecisions(kd) {
+		if status, ok := statusByID[decision.Item]; ok && status == decision.Decision {
+			decisions = append(decisions, decision)
+		}
+	}
+	return decisions
+}
+
+// getManualDecision returns the recorded decision taken with the status of the manual validation item, nil if none
+func getManualDecision(kd *kanaryv1alpha1.KanaryStatefulset, index int) *kanaryv1alpha1.KanaryStatefulsetApproval {
+	if index >= len(kd.Spec.Validations.Items) {
+		return nil
+	}
+	id := GetManualItemID(&kd.Spec.Validations, index)
+	for _, decision := range GetManualDecisions(kd) {
+		if decision.Item == id {
+			return &decision
+		}
+	}
+	return nil
+}
+
+// RecordApproval appends the current approval decisions to the audit trail of the status, if not already recorded:
+// the decision of the approval annotation and the decisions taken with the status of the manual items.
+func RecordApproval(kd *kanaryv1alpha1.KanaryStatefulset, status *kanaryv1alpha1.KanaryStatefulsetStatus) {
+	approvals := GetManualDecisions(kd)
+	if approval := GetApproval(kd); approval != nil {
+		approvals = append([]kanaryv1alpha1.KanaryStatefulsetApproval{*approval}, approvals...)
+	}
+	for _, approval := range approvals {
+		if !isApprovalRecorded(status, &approval) {
+			status.Approvals = append(status.Approvals, approval)
+		}
+	}
+}
+
+func isApprovalRecorded(status *kanaryv1alpha1.KanaryStatefulsetStatus, approval *kanaryv1alpha1.KanaryStatefulsetApproval) bool {
+	for _, recorded := range status.Approvals {
+		if recorded.Approver == approval.Approver && recorded.Time.Equal(&approval.Time) && recorded.Item == approval.Item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestRecordApproval(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	key := []byte("webhook-key")
+	ConfigureApprovalKey(key)
+	defer ConfigureApprovalKey(nil)
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	status := &kanaryv1alpha1.KanaryStatefulsetStatus{}
+
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 0 {
+		t.Errorf("RecordApproval() without approval, got %d approvals", len(status.Approvals))
+	}
+
+	kd.Annotations = map[string]string{
+		kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:        "valid",
+		kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey: "looks good",
+	}
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 0 {
+		t.Errorf("RecordApproval() with an approval not recorded by the webhook, got %d approvals", len(status.Approvals))
+	}
+
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey] = "alice"
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey] = now.Format(time.RFC3339)
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 0 {
+		t.Errorf("RecordApproval() with an approval not signed by the webhook, got %d approvals", len(status.Approvals))
+	}
+
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = SignApprovals(key, kd)
+	ConfigureApprovalKey(nil)
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 0 {
+		t.Errorf("RecordApproval() without active webhook, got %d approvals", len(status.Approvals))
+	}
+
+	ConfigureApprovalKey(key)
+	RecordApproval(kd, status)
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 1 {
+		t.Fatalf("RecordApproval() should record the approval once, got %d approvals", len(status.Approvals))
+	}
+	got := status.Approvals[0]
+	if got.Approver != "alice" || got.Decision != kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus || !got.Time.Time.Equal(now) || got.Comment != "looks good" {
+		t.Errorf("RecordApproval() = %#v", got)
+	}
+
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey] = "invalid"
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey] = "bob"
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey] = now.Add(time.Minute).Format(time.RFC3339)
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = SignApprovals(key, kd)
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 2 || status.Approvals[1].Approver != "bob" {
+		t.Errorf("RecordApproval() should append the new decision, got %#v", status.Approvals)
+	}
+}
+
+func TestRecordApproval_manualDecisions(t *testing.T) {
+	key := []byte("webhook-key")
+	ConfigureApprovalKey(key)
+	defer ConfigureApprovalKey(nil)
+
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	kd.Spec.Validations.Items = []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+		{Name: "qa", Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{Status: kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus}},
+		{Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{Status: kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus}},
+	}
+	kd.Annotations = map[string]string{
+		kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: `[{"approver":"alice","decision":"valid","time":"2019-04-01T10:00:00Z","item":"qa"},` +
+			`{"approver":"bob","decision":"valid","time":"2019-04-01T09:00:00Z","item":"items[1]"}]`,
+	}
+	status := &kanaryv1alpha1.KanaryStatefulsetStatus{}
+
+	RecordApproval(kd, status)
+	if len(status.Approvals) != 0 {
+		t.Errorf("RecordApproval() with decisions not signed by the webhook, got %d approvals", len(status.Approvals))
+	}
+
+	kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = SignApprovals(key, kd)
+	RecordApproval(kd, status)
+	RecordApproval(kd, status)
+	// the decision of items[1] does not match its current status
+	if len(status.Approvals) != 1 {
+		t.Fatalf("RecordApproval() should record the decision of the qa item once, got %#v", status.Approvals)
+	}
+	if got := status.Approvals[0]; got.Approver != "alice" || got.Item != "qa" || got.Decision != kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus {
+		t.Errorf("RecordApproval() = %#v", got)
+	}
+}
@@ -0,0 +1,372 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/pod"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	chaosPodKillType   = "podKill"
+	chaosHTTPFaultType = "httpFault"
+)
+
+var virtualServiceGVK = schema.GroupVersionKind{Group: "networking.istio.io", Version: "v1alpha3", Kind: "VirtualService"}
+
+// RunChaos runs the chaos phase: it checks the outcome of the running action, or starts the next one.
+// It returns the failure messages of the failed actions.
+func RunChaos(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, status *kanaryv1alpha1.KanaryStatefulsetStatus, now time.Time) ([]string, error) {
+	chaos := kd.Spec.Validations.Chaos
+	if chaos == nil {
+		return nil, nil
+	}
+
+	if n := len(status.Chaos); n > 0 && n <= len(chaos.Actions) && status.Chaos[n-1].Outcome == kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome {
+		if err := checkChaosAction(kclient, reqLogger, kd, &chaos.Actions[n-1], &status.Chaos[n-1], now); err != nil {
+			return nil, err
+		}
+	} else if n < len(chaos.Actions) && !now.Before(GetValidationStart(kd).Add(chaos.StartDelay.Duration)) {
+		actionStatus, err := startChaosAction(kclient, reqLogger, kd, sts, &chaos.Actions[n], now)
+		if err != nil {
+			return nil, err
+		}
+		status.Chaos = append(status.Chaos, *actionStatus)
+	}
+
+	var failures []string
+	for _, action := range status.Chaos {
+		if action.Outcome == kanaryv1alpha1.FailedKanaryStatefulsetChaosOutcome {
+			failures = append(failures, fmt.Sprintf("chaos action %s failed: %s", action.Name, action.Message))
+		}
+	}
+	return failures, nil
+}
+
+// IsChaosPending returns true if some chaos actions are running or not started yet
+func IsChaosPending(kd *kanaryv1alpha1.KanaryStatefulset, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	if kd.Spec.Validations.Chaos == nil {
+		return false
+	}
+	return len(status.Chaos) < len(kd.Spec.Validations.Chaos.Actions) || HasRunningChaos(status)
+}
+
+// HasRunningChaos returns true if a chaos action is running
+func HasRunningChaos(status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	for _, action := range status.Chaos {
+		if action.Outcome == kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome {
+			return true
+		}
+	}
+	return false
+}
+
+// RevertChaos aborts the running chaos actions: the injected faults are reverted, then the chaos finalizer is removed.
+func RevertChaos(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, status *kanaryv1alpha1.KanaryStatefulsetStatus, now time.Time) error {
+	for i := range status.Chaos {
+		action := &status.Chaos[i]
+		if action.Outcome != kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome {
+			continue
+		}
+		if action.Type == chaosHTTPFaultType {
+			if err := revertHTTPFault(kclient, kd, action.Target, getChaosFaultSubset(kd, action.Name)); err != nil {
+				return err
+			}
+		}
+		reqLogger.Info("Chaos", "aborted", action.Name)
+		endChaosAction(action, kanaryv1alpha1.AbortedKanaryStatefulsetChaosOutcome, "the validation ended before the end of the action", now)
+	}
+	return removeChaosFinalizer(kclient, kd)
+}
+
+func startChaosAction(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, action *kanaryv1alpha1.KanaryStatefulsetSpecValidationChaosAction, now time.Time) (*kanaryv1alpha1.KanaryStatefulsetChaosActionStatus, error) {
+	status := &kanaryv1alpha1.KanaryStatefulsetChaosActionStatus{
+		Name:    action.Name,
+		Start:   metav1.NewTime(now),
+		Outcome: kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome,
+	}
+	switch {
+	case action.PodKill != nil:
+		status.Type = chaosPodKillType
+		target, err := getChaosPodKillTarget(kclient, kd, sts)
+		if err != nil {
+			return nil, err
+		}
+		if target == nil {
+			endChaosAction(status, kanaryv1alpha1.FailedKanaryStatefulsetChaosOutcome, "no canary pod to delete", now)
+			return status, nil
+		}
+		// the target is recorded in the status first, the pod is deleted at the next check:
+		// a status update lost can't lead to the deletion of another pod
+		status.Target = target.Name
+		status.TargetUID = string(target.UID)
+		reqLogger.Info("Chaos", "action", action.Name, "target pod", target.Name)
+	case action.HTTPFault != nil:
+		status.Type = chaosHTTPFaultType
+		status.Target = action.HTTPFault.VirtualService
+		// the finalizer is set first, a fault injected can't be left behind
+		if err := addChaosFinalizer(kclient, kd); err != nil {
+			return nil, err
+		}
+		problem, err := injectHTTPFault(kclient, kd, action.HTTPFault)
+		if err != nil {
+			return nil, err
+		}
+		if problem != "" {
+			endChaosAction(status, kanaryv1alpha1.FailedKanaryStatefulsetChaosOutcome, problem, now)
+			return status, removeChaosFinalizer(kclient, kd)
+		}
+		reqLogger.Info("Chaos", "action", action.Name, "fault injected in", action.HTTPFault.VirtualService)
+	default:
+		endChaosAction(status, kanaryv1alpha1.FailedKanaryStatefulsetChaosOutcome, "no fault defined", now)
+	}
+	return status, nil
+}
+
+func checkChaosAction(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, action *kanaryv1alpha1.KanaryStatefulsetSpecValidationChaosAction, status *kanaryv1alpha1.KanaryStatefulsetChaosActionStatus, now time.Time) error {
+	elapsed := now.Sub(status.Start.Time)
+	switch status.Type {
+	case chaosPodKillType:
+		if action.PodKill == nil {
+			endChaosAction(status, kanaryv1alpha1.AbortedKanaryStatefulsetChaosOutcome, "the action has been changed in the spec", now)
+			break
+		}
+		p := &corev1.Pod{}
+		err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: kd.Namespace, Name: status.Target}, p)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		// the recorded target is not deleted yet, the timeout starts with its deletion
+		if err == nil && string(p.UID) == status.TargetUID && p.DeletionTimestamp == nil {
+			if err = kclient.Delete(context.TODO(), p); err != nil && !apierrors.IsNotFound(err) {
+				return fmt.Errorf("unable to delete the pod %s: %v", p.Name, err)
+			}
+			status.Start = metav1.NewTime(now)
+			reqLogger.Info("Chaos", "action", status.Name, "deleted pod", p.Name)
+			break
+		}
+		// the pod is recreated by the statefulset with the same name
+		if err == nil && string(p.UID) != status.TargetUID && p.DeletionTimestamp == nil && pod.IsReady(p) {
+			endChaosAction(status, kanaryv1alpha1.SucceededKanaryStatefulsetChaosOutcome, fmt.Sprintf("pod %s Ready again after %v", status.Target, elapsed.Round(time.Second)), now)
+		} else if elapsed > action.PodKill.ReadyTimeout.Duration {
+			endChaosAction(status, kanaryv1alpha1.FailedKanaryStatefulsetChaosOutcome, fmt.Sprintf("pod %s not Ready within %v", status.Target, action.PodKill.ReadyTimeout.Duration), now)
+		}
+	case chaosHTTPFaultType:
+		if action.HTTPFault != nil && elapsed < action.HTTPFault.Duration.Duration {
+			break
+		}
+		if err := revertHTTPFault(kclient, kd, status.Target, getChaosFaultSubset(kd, status.Name)); err != nil {
+			return err
+		}
+		if err := removeChaosFinalizer(kclient, kd); err != nil {
+			return err
+		}
+		if action.HTTPFault == nil {
+			endChaosAction(status, kanaryv1alpha1.AbortedKanaryStatefulsetChaosOutcome, "the action has been changed in the spec", now)
+			break
+		}
+		endChaosAction(status, kanaryv1alpha1.SucceededKanaryStatefulsetChaosOutcome, fmt.Sprintf("fault injected during %v", action.HTTPFault.Duration.Duration), now)
+	}
+	if status.Outcome != kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome {
+		reqLogger.Info("Chaos", "action", status.Name, "outcome", status.Outcome, "message", status.Message)
+	}
+	return nil
+}
+
+func endChaosAction(status *kanaryv1alpha1.KanaryStatefulsetChaosActionStatus, outcome kanaryv1alpha1.KanaryStatefulsetChaosOutcome, message string, now time.Time) {
+	end := metav1.NewTime(now)
+	status.End = &end
+	status.Outcome = outcome
+	status.Message = message
+}
+
+// getChaosPodKillTarget returns the first Ready canary pod by name, nil if there is none
+func getChaosPodKillTarget(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet) (*corev1.Pod, error) {
+	if sts == nil || sts.Spec.Selector == nil {
+		return nil, nil
+	}
+	pods := &corev1.PodList{}
+	selector := labels.SelectorFromSet(sts.Spec.Selector.MatchLabels)
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: kd.Namespace, LabelSelector: selector}, pods); err != nil {
+		return nil, fmt.Errorf("unable to list the statefulset pods: %v", err)
+	}
+	var candidates []*corev1.Pod
+	for i := range pods.Items {
+		p := &pods.Items[i]
+		if selector.Matches(labels.Set(p.Labels)) && isCanaryPod(p, sts) && p.DeletionTimestamp == nil && pod.IsReady(p) {
+			candidates = append(candidates, p)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Name < candidates[j].Name })
+	return candidates[0], nil
+}
+
+// getChaosFaultSubset returns the subset of the http fault action with this name
+func getChaosFaultSubset(kd *kanaryv1alpha1.KanaryStatefulset, name string) string {
+	if kd.Spec.Validations.Chaos == nil {
+		return ""
+	}
+	for _, action := range kd.Spec.Validations.Chaos.Actions {
+		if action.Name == name && action.HTTPFault != nil {
+			return action.HTTPFault.Subset
+		}
+	}
+	return ""
+}
+
+// injectHTTPFault adds the fault to the http routes of the VirtualService that target only the canary subset.
+// It returns the reason why the fault can't be injected, if the VirtualService does not allow it.
+func injectHTTPFault(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, spec *kanaryv1alpha1.ChaosHTTPFault) (string, error) {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(virtualServiceGVK)
+	if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: kd.Namespace, Name: spec.VirtualService}, vs); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Sprintf("virtualService %s not found", spec.VirtualService), nil
+		}
+		return "", err
+	}
+	routes, _, err := unstructured.NestedSlice(vs.Object, "spec", "http")
+	if err != nil {
+		return "", fmt.Errorf("virtualService %s: %v", spec.VirtualService, err)
+	}
+	indexes := getSubsetRoutes(routes, spec.Subset)
+	if len(indexes) == 0 {
+		return fmt.Sprintf("virtualService %s has no http route targeting only the subset %s", spec.VirtualService, spec.Subset), nil
+	}
+
+	for _, i := range indexes {
+		route := routes[i].(map[string]interface{})
+		if _, ok := route["fault"]; ok {
+			return fmt.Sprintf("virtualService %s already defines a fault in the routes of the subset %s", spec.VirtualService, spec.Subset), nil
+		}
+		route["fault"] = newHTTPFault(spec)
+	}
+	if err = unstructured.SetNestedSlice(vs.Object, routes, "spec", "http"); err != nil {
+		return "", err
+	}
+	annotations := vs.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[kanaryv1alpha1.KanaryStatefulsetChaosAnnotationKey] = kd.Name
+	vs.SetAnnotations(annotations)
+	return "", kclient.Update(context.TODO(), vs)
+}
+
+// revertHTTPFault removes the fault from the routes of the VirtualService, if it was injected by this KanaryStatefulset
+func revertHTTPFault(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, name, subset string) error {
+	vs := &unstructured.Unstructured{}
+	vs.SetGroupVersionKind(virtualServiceGVK)
+	if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: kd.Namespace, Name: name}, vs); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	annotations := vs.GetAnnotations()
+	if annotations[kanaryv1alpha1.KanaryStatefulsetChaosAnnotationKey] != kd.Name {
+		return nil
+	}
+	routes, _, err := unstructured.NestedSlice(vs.Object, "spec", "http")
+	if err != nil {
+		return fmt.Errorf("virtualService %s: %v", name, err)
+	}
+	// the subset is unknown if the action has been removed from the spec, only the annotation is removed
+	if subset != "" {
+		for _, i := range getSubsetRoutes(routes, subset) {
+			delete(routes[i].(map[string]interface{}), "fault")
+		}
+	}
+	if err = unstructured.SetNestedSlice(vs.Object, routes, "spec", "http"); err != nil {
+		return err
+	}
+	delete(annotations, kanaryv1alpha1.KanaryStatefulsetChaosAnnotationKey)
+	vs.SetAnnotations(annotations)
+	return kclient.Update(context.TODO(), vs)
+}
+
+// getSubsetRoutes returns the indexes of the http routes whose destinations are all the subset
+func getSubsetRoutes(routes []interface{}, subset string) []int {
+	var indexes []int
+	for i, r := range routes {
+		route, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		destinations, _, _ := unstructured.NestedSlice(route, "route")
+		match := len(destinations) > 0
+		for _, d := range destinations {
+			destination, ok := d.(map[string]interface{})
+			if !ok {
+				match = false
+				break
+			}
+			if s, _, _ := unstructured.NestedString(destination, "destination", "subset"); s != subset {
+				match = false
+				break
+			}
+		}
+		if match {
+			indexes = append(indexes, i)
+		}
+	}
+	return indexes
+}
+
+// newHTTPFault returns the istio HTTPFaultInjection of the action
+func newHTTPFault(spec *kanaryv1alpha1.ChaosHTTPFault) map[string]interface{} {
+	fault := map[string]interface{}{}
+	if spec.Delay != nil {
+		fault["delay"] = map[string]interface{}{
+			"fixedDelay": fmt.Sprintf("%gs", spec.Delay.Duration.Seconds()),
+			"percentage": map[string]interface{}{"value": *spec.Percentage},
+		}
+	}
+	if spec.AbortHTTPStatus != nil {
+		fault["abort"] = map[string]interface{}{
+			"httpStatus": int64(*spec.AbortHTTPStatus),
+			"percentage": map[string]interface{}{"value": *spec.Percentage},
+		}
+	}
+	return fault
+}
+
+func addChaosFinalizer(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset) error {
+	for _, f := range kd.Finalizers {
+		if f == kanaryv1alpha1.KanaryStatefulsetChaosFinalizer {
+			return nil
+		}
+	}
+	kd.Finalizers = append(kd.Finalizers, kanaryv1alpha1.KanaryStatefulsetChaosFinalizer)
+	return kclient.Update(context.TODO(), kd)
+}
+
+func removeChaosFinalizer(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset) error {
+	var finalizers []string
+	for _, f := range kd.Finalizers {
+		if f != kanaryv1alpha1.KanaryStatefulsetChaosFinalizer {
+			finalizers = append(finalizers, f)
+		}
+	}
+	if len(finalizers) == len(kd.Finalizers) {
+		return nil
+	}
+	kd.Finalizers = finalizers
+	return kclient.Update(context.TODO(), kd)
+}
@@ -0,0 +1,272 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	test "github.com/k8s-kanary/kanary/test"
+)
+
+// virtualServiceClient keeps a VirtualService in memory, the other objects are handled by the embedded client
+type virtualServiceClient struct {
+	client.Client
+	vs *unstructured.Unstructured
+}
+
+func (c *virtualServiceClient) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return c.Client.Get(ctx, key, obj)
+	}
+	if c.vs == nil || c.vs.GetName() != key.Name {
+		return apierrors.NewNotFound(virtualServiceGVK.GroupVersion().WithResource("virtualservices").GroupResource(), key.Name)
+	}
+	c.vs.DeepCopyInto(u)
+	return nil
+}
+
+func (c *virtualServiceClient) Update(ctx context.Context, obj runtime.Object) error {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		c.vs = u.DeepCopy()
+		return nil
+	}
+	return c.Client.Update(ctx, obj)
+}
+
+func newVirtualService(name string, routes ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata": map[string]interface{}{"name": name, "namespace": "bar"},
+		"spec":     map[string]interface{}{"hosts": []interface{}{"foo"}, "http": routes},
+	}}
+}
+
+func newRoute(subsets ...string) interface{} {
+	destinations := []interface{}{}
+	for _, subset := range subsets {
+		destinations = append(destinations, map[string]interface{}{"destination": map[string]interface{}{"host": "foo", "subset": subset}})
+	}
+	return map[string]interface{}{"route": destinations}
+}
+
+func newChaosKanaryStatefulset(actions ...kanaryv1alpha1.KanaryStatefulsetSpecValidationChaosAction) *kanaryv1alpha1.KanaryStatefulset {
+	kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "bar", "", 3, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+	kd.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	kd.Spec.Validations.Chaos = &kanaryv1alpha1.KanaryStatefulsetSpecValidationChaos{StartDelay: &metav1.Duration{}, Actions: actions}
+	return kd
+}
+
+func TestRunChaos_podKill(t *testing.T) {
+	log := logf.Log.WithName("TestRunChaos_podKill")
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	newPod := func(name, revision, uid string) *corev1.Pod {
+		p := test.PodGen(name, "bar", map[string]string{"app": "foo", appsv1.ControllerRevisionHashLabelKey: revision}, nil, true, true)
+		p.UID = types.UID(uid)
+		return p
+	}
+	action := kanaryv1alpha1.KanaryStatefulsetSpecValidationChaosAction{
+		Name:    "kill",
+		PodKill: &kanaryv1alpha1.ChaosPodKill{ReadyTimeout: &metav1.Duration{Duration: 2 * time.Minute}},
+	}
+	now := time.Now()
+
+	t.Run("pod Ready again", func(t *testing.T) {
+		kclient := fake.NewFakeClient(newPod("foo-0", "v1", "0"), newPod("foo-1", "v2", "1"), newPod("foo-2", "v2", "2"))
+		kd := newChaosKanaryStatefulset(action)
+		status := kd.Status.DeepCopy()
+
+		if _, err := RunChaos(kclient, log, kd, sts, status, now); err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if len(status.Chaos) != 1 || status.Chaos[0].Target != "foo-1" || status.Chaos[0].Outcome != kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome {
+			t.Fatalf("the first canary pod should be the target, status = %#v", status.Chaos)
+		}
+		if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-1"}, &corev1.Pod{}); err != nil {
+			t.Errorf("the pod foo-1 should be deleted at the next check, err = %v", err)
+		}
+
+		if _, err := RunChaos(kclient, log, kd, sts, status, now.Add(time.Minute)); err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-1"}, &corev1.Pod{}); !apierrors.IsNotFound(err) {
+			t.Errorf("the pod foo-1 should be deleted, err = %v", err)
+		}
+		if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: "foo-2"}, &corev1.Pod{}); err != nil {
+			t.Errorf("the pod foo-2 should not be deleted, err = %v", err)
+		}
+
+		if err := kclient.Create(context.TODO(), newPod("foo-1", "v2", "1bis")); err != nil {
+			t.Fatal(err)
+		}
+		failures, err := RunChaos(kclient, log, kd, sts, status, now.Add(90*time.Second))
+		if err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if len(failures) > 0 || status.Chaos[0].Outcome != kanaryv1alpha1.SucceededKanaryStatefulsetChaosOutcome || status.Chaos[0].Message != "pod foo-1 Ready again after 30s" {
+			t.Errorf("the action should succeed, failures = %v, status = %#v", failures, status.Chaos[0])
+		}
+		if IsChaosPending(kd, status) {
+			t.Errorf("the chaos phase should be over")
+		}
+	})
+
+	t.Run("pod not Ready within the timeout", func(t *testing.T) {
+		kclient := fake.NewFakeClient(newPod("foo-1", "v2", "1"))
+		kd := newChaosKanaryStatefulset(action)
+		status := kd.Status.DeepCopy()
+
+		for _, d := range []time.Duration{0, time.Minute} {
+			if _, err := RunChaos(kclient, log, kd, sts, status, now.Add(d)); err != nil {
+				t.Fatalf("RunChaos() error = %v", err)
+			}
+		}
+		failures, err := RunChaos(kclient, log, kd, sts, status, now.Add(4*time.Minute))
+		if err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if want := []string{"chaos action kill failed: pod foo-1 not Ready within 2m0s"}; !reflect.DeepEqual(failures, want) {
+			t.Errorf("RunChaos() = %v, want %v", failures, want)
+		}
+	})
+
+	t.Run("target already recorded", func(t *testing.T) {
+		kclient := fake.NewFakeClient(newPod("foo-1", "v2", "1bis"), newPod("foo-2", "v2", "2"))
+		kd := newChaosKanaryStatefulset(action)
+		// the pod foo-1 has been deleted, then recreated
+		status := kd.Status.DeepCopy()
+		status.Chaos = []kanaryv1alpha1.KanaryStatefulsetChaosActionStatus{{Name: "kill", Type: chaosPodKillType, Target: "foo-1", TargetUID: "1", Start: metav1.NewTime(now), Outcome: kanaryv1alpha1.RunningKanaryStatefulsetChaosOutcome}}
+
+		if _, err := RunChaos(kclient, log, kd, sts, status, now.Add(30*time.Second)); err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		for _, name := range []string{"foo-1", "foo-2"} {
+			if err := kclient.Get(context.TODO(), client.ObjectKey{Namespace: "bar", Name: name}, &corev1.Pod{}); err != nil {
+				t.Errorf("the pod %s should not be deleted, err = %v", name, err)
+			}
+		}
+		if status.Chaos[0].Outcome != kanaryv1alpha1.SucceededKanaryStatefulsetChaosOutcome {
+			t.Errorf("the action should succeed, status = %#v", status.Chaos[0])
+		}
+	})
+
+	t.Run("no canary pod", func(t *testing.T) {
+		kclient := fake.NewFakeClient(newPod("foo-0", "v1", "0"))
+		kd := newChaosKanaryStatefulset(action)
+		status := kd.Status.DeepCopy()
+
+		failures, err := RunChaos(kclient, log, kd, sts, status, now)
+		if err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if want := []string{"chaos action kill failed: no canary pod to delete"}; !reflect.DeepEqual(failures, want) {
+			t.Errorf("RunChaos() = %v, want %v", failures, want)
+		}
+	})
+}
+
+func TestRunChaos_httpFault(t *testing.T) {
+	log := logf.Log.WithName("TestRunChaos_httpFault")
+	s := scheme.Scheme
+	s.AddKnownTypes(kanaryv1alpha1.SchemeGroupVersion, &kanaryv1alpha1.KanaryStatefulset{})
+
+	action := kanaryv1alpha1.KanaryStatefulsetSpecValidationChaosAction{
+		Name: "abort",
+		HTTPFault: &kanaryv1alpha1.ChaosHTTPFault{
+			VirtualService:  "foo",
+			Subset:          "canary",
+			AbortHTTPStatus: kanaryv1alpha1.NewInt32(503),
+			Percentage:      kanaryv1alpha1.NewFloat64(50),
+			Duration:        &metav1.Duration{Duration: time.Minute},
+		},
+	}
+	wantFault := map[string]interface{}{"abort": map[string]interface{}{"httpStatus": int64(503), "percentage": map[string]interface{}{"value": float64(50)}}}
+	getFaults := func(vs *unstructured.Unstructured) []interface{} {
+		routes, _, _ := unstructured.NestedSlice(vs.Object, "spec", "http")
+		faults := []interface{}{}
+		for _, r := range routes {
+			faults = append(faults, r.(map[string]interface{})["fault"])
+		}
+		return faults
+	}
+	now := time.Now()
+
+	for _, end := range []string{"duration", "revert"} {
+		t.Run(end, func(t *testing.T) {
+			kd := newChaosKanaryStatefulset(action)
+			kclient := &virtualServiceClient{
+				Client: fake.NewFakeClient(kd),
+				vs:     newVirtualService("foo", newRoute("canary"), newRoute("stable", "canary")),
+			}
+			status := kd.Status.DeepCopy()
+
+			if _, err := RunChaos(kclient, log, kd, nil, status, now); err != nil {
+				t.Fatalf("RunChaos() error = %v", err)
+			}
+			if got, want := getFaults(kclient.vs), []interface{}{wantFault, nil}; !reflect.DeepEqual(got, want) {
+				t.Errorf("the fault should be injected in the route of the canary subset only, faults = %#v", got)
+			}
+			if kclient.vs.GetAnnotations()[kanaryv1alpha1.KanaryStatefulsetChaosAnnotationKey] != "foo" || len(kd.Finalizers) != 1 {
+				t.Errorf("the VirtualService annotation and the finalizer should be set, annotations = %v, finalizers = %v", kclient.vs.GetAnnotations(), kd.Finalizers)
+			}
+
+			outcome := kanaryv1alpha1.SucceededKanaryStatefulsetChaosOutcome
+			if end == "duration" {
+				if _, err := RunChaos(kclient, log, kd, nil, status, now.Add(2*time.Minute)); err != nil {
+					t.Fatalf("RunChaos() error = %v", err)
+				}
+			} else {
+				outcome = kanaryv1alpha1.AbortedKanaryStatefulsetChaosOutcome
+				if err := RevertChaos(kclient, log, kd, status, now.Add(30*time.Second)); err != nil {
+					t.Fatalf("RevertChaos() error = %v", err)
+				}
+			}
+			if status.Chaos[0].Outcome != outcome {
+				t.Errorf("outcome = %s, want %s", status.Chaos[0].Outcome, outcome)
+			}
+			if got, want := getFaults(kclient.vs), []interface{}{nil, nil}; !reflect.DeepEqual(got, want) {
+				t.Errorf("the fault should be reverted, faults = %#v", got)
+			}
+			if len(kclient.vs.GetAnnotations()) != 0 || len(kd.Finalizers) != 0 {
+				t.Errorf("the VirtualService annotation and the finalizer should be removed, annotations = %v, finalizers = %v", kclient.vs.GetAnnotations(), kd.Finalizers)
+			}
+		})
+	}
+
+	t.Run("existing fault", func(t *testing.T) {
+		kd := newChaosKanaryStatefulset(action)
+		route := newRoute("canary").(map[string]interface{})
+		route["fault"] = map[string]interface{}{"delay": map[string]interface{}{"fixedDelay": "1s"}}
+		kclient := &virtualServiceClient{Client: fake.NewFakeClient(kd), vs: newVirtualService("foo", route)}
+		status := kd.Status.DeepCopy()
+
+		failures, err := RunChaos(kclient, log, kd, nil, status, now)
+		if err != nil {
+			t.Fatalf("RunChaos() error = %v", err)
+		}
+		if want := []string{"chaos action abort failed: virtualService foo already defines a fault in the routes of the subset canary"}; !reflect.DeepEqual(failures, want) {
+			t.Errorf("RunChaos() = %v, want %v", failures, want)
+		}
+		if len(kd.Finalizers) != 0 {
+			t.Errorf("the finalizer should be removed, finalizers = %v", kd.Finalizers)
+		}
+	})
+}
@@ -0,0 +1,83 @@
+package validation
+
+import (
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// MaxCheckHistory is the number of check outcomes kept in a validation item status
+const MaxCheckHistory = 10
+
+// RecordCheck stores the outcome of a check and the measured values in the validation item status.
+// It should be called before ApplyTolerance in order to record the raw outcome of the check.
+func RecordCheck(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation, status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, result *Result, now time.Time) {
+	if result == nil {
+		return
+	}
+
+	checkTime := metav1.NewTime(now)
+	status.Name = item.Name
+	status.Type = GetType(item)
+	status.LastCheckTime = &checkTime
+	status.Threshold = result.Threshold
+	status.Query = result.Query
+
+	status.Values = nil
+	for name, value := range result.Values {
+		status.Values = append(status.Values, kanaryv1alpha1.KanaryStatefulsetValidationValue{Name: name, Value: value})
+	}
+	sort.Slice(status.Values, func(i, j int) bool { return status.Values[i].Name < status.Values[j].Name })
+
+	status.ExcludedPods = nil
+	for name, reason := range result.ExcludedPods {
+		status.ExcludedPods = append(status.ExcludedPods, kanaryv1alpha1.KanaryStatefulsetExcludedPod{Name: name, Reason: reason})
+	}
+	sort.Slice(status.ExcludedPods, func(i, j int) bool { return status.ExcludedPods[i].Name < status.ExcludedPods[j].Name })
+
+	status.Inconclusive = result.IsInconclusive
+	for _, baseline := range result.Baselines {
+		if getBaseline(status, baseline.Name) == nil {
+			status.Baselines = append(status.Baselines, baseline)
+		}
+	}
+	status.History = append(status.History, kanaryv1alpha1.KanaryStatefulsetValidationCheck{
+		Time:         checkTime,
+		Failed:       result.IsFailed,
+		Comment:      result.Comment,
+		Inconclusive: result.IsInconclusive,
+	})
+	if len(status.History) > MaxCheckHistory {
+		status.History = status.History[len(status.History)-MaxCheckHistory:]
+	}
+}
+
+// GetType returns the type of the validation item
+func GetType(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation) string {
+	switch {
+	case item.Manual != nil:
+		return "manual"
+	case item.LabelWatch != nil:
+		return "labelWatch"
+	case item.PromQL != nil:
+		return "promQL"
+	case item.Metrics != nil:
+		return "metrics"
+	case item.ResourceUsage != nil:
+		return "resourceUsage"
+	case item.Logs != nil:
+		return "logs"
+	case item.Events != nil:
+		return "events"
+	case item.Job != nil:
+		return "job"
+	case item.Alerts != nil:
+		return "alerts"
+	case item.SLO != nil:
+		return "slo"
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestRecordCheck(t *testing.T) {
+	now := time.Now()
+	checkTime := metav1.NewTime(now)
+	fullHistory := []kanaryv1alpha1.KanaryStatefulsetValidationCheck{}
+	for i := 0; i < MaxCheckHistory; i++ {
+		fullHistory = append(fullHistory, kanaryv1alpha1.KanaryStatefulsetValidationCheck{Time: metav1.NewTime(now.Add(time.Duration(i-MaxCheckHistory) * time.Minute))})
+	}
+
+	tests := []struct {
+		name       string
+		item       *kanaryv1alpha1.KanaryStatefulsetSpecValidation
+		status     kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		result     *Result
+		wantStatus kanaryv1alpha1.KanaryStatefulsetValidationStatus
+	}{
+		{
+			name:       "nil result",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{Index: 1},
+			result:     nil,
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{Index: 1},
+		},
+		{
+			name: "promQL failed check with values",
+			item: &kanaryv1alpha1.KanaryStatefulsetSpecValidation{Name: "latency", PromQL: &kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{}},
+			result: &Result{
+				IsFailed:  true,
+				Comment:   "promQL query reported an issue with one of the kanary pod",
+				Values:    map[string]string{"pod-b": "2.5", "pod-a": "0.5"},
+				Threshold: "[0, 1]",
+			},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				Name:          "latency",
+				Type:          "promQL",
+				LastCheckTime: &checkTime,
+				Values: []kanaryv1alpha1.KanaryStatefulsetValidationValue{
+					{Name: "pod-a", Value: "0.5"},
+					{Name: "pod-b", Value: "2.5"},
+				},
+				Threshold: "[0, 1]",
+				History: []kanaryv1alpha1.KanaryStatefulsetValidationCheck{
+					{Time: checkTime, Failed: true, Comment: "promQL query reported an issue with one of the kanary pod"},
+				},
+			},
+		},
+		{
+			name: "history is truncated, values are reset",
+			item: &kanaryv1alpha1.KanaryStatefulsetSpecValidation{Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}},
+			status: kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				Values:  []kanaryv1alpha1.KanaryStatefulsetValidationValue{{Name: "status", Value: "invalid"}},
+				History: fullHistory,
+			},
+			result: &Result{},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				Type:          "manual",
+				LastCheckTime: &checkTime,
+				History:       append(append([]kanaryv1alpha1.KanaryStatefulsetValidationCheck{}, fullHistory[1:]...), kanaryv1alpha1.KanaryStatefulsetValidationCheck{Time: checkTime}),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			RecordCheck(tt.item, &status, tt.result, now)
+			if !reflect.DeepEqual(status, tt.wantStatus) {
+				t.Errorf("RecordCheck() status = %#v, want %#v", status, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -8,7 +8,10 @@ import (
 	"github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
 	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
 	"github.com/go-logr/logr"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -29,7 +32,19 @@ func GetNextValidationCheckDuration(kd *v1alpha1.KanaryStatefulset) time.Duratio
 
 //GetValidationDeadLine return the timestamp for the end validation period
 func GetValidationDeadLine(kd *v1alpha1.KanaryStatefulset) time.Time {
-	return kd.CreationTimestamp.Time.Add(kd.Spec.Validations.InitialDelay.Duration).Add(kd.Spec.Validations.ValidationPeriod.Duration)
+	return ComputeValidationDeadLine(kd, &kd.Status)
+}
+
+//ComputeValidationDeadLine return the timestamp for the end validation period, with the extensions recorded in the given status
+func ComputeValidationDeadLine(kd *v1alpha1.KanaryStatefulset, status *v1alpha1.KanaryStatefulsetStatus) time.Time {
+	list := &kd.Spec.Validations
+	return kd.CreationTimestamp.Time.Add(list.InitialDelay.Duration).Add(list.ValidationPeriod.Duration).Add(getScoreExtension(list, status)).Add(getInconclusiveExtension(list, status)).Add(getConfidenceExtension(status)).Add(getOutageExtension(list, status))
+}
+
+//RecordValidationDeadline stores the effective end of the validation period in the status
+func RecordValidationDeadline(kd *v1alpha1.KanaryStatefulset, status *v1alpha1.KanaryStatefulsetStatus) {
+	deadline := metav1.NewTime(ComputeValidationDeadLine(kd, status))
+	status.ValidationDeadline = &deadline
 }
 
 // IsDeadlinePeriodDone returns true if the InitialDelay validation periode is over.
@@ -37,10 +52,29 @@ func IsDeadlinePeriodDone(kd *v1alpha1.KanaryStatefulset) bool {
 	return GetValidationDeadLine(kd).Before(time.Now())
 }
 
+// GetValidationStart return the timestamp for the beginning of the validation period
+func GetValidationStart(kd *v1alpha1.KanaryStatefulset) time.Time {
+	if kd.Spec.Validations.InitialDelay == nil {
+		return kd.CreationTimestamp.Time
+	}
+	return kd.CreationTimestamp.Time.Add(kd.Spec.Validations.InitialDelay.Duration)
+}
+
+// GetLastCheckTime returns the time of the previous check of the validation item, or the validation start for the first check
+func GetLastCheckTime(kd *v1alpha1.KanaryStatefulset, index int) time.Time {
+	last := GetValidationStart(kd)
+	for _, status := range kd.Status.Validations {
+		if status.Index == index && status.LastCheckTime != nil && status.LastCheckTime.Time.After(last) {
+			last = status.LastCheckTime.Time
+		}
+	}
+	return last
+}
+
 // IsInitialDelayDone returns true if the InitialDelay validation periode is over.
 func IsInitialDelayDone(kd *v1alpha1.KanaryStatefulset) (time.Duration, bool) {
 	now := time.Now()
-	deadline := kd.CreationTimestamp.Time.Add(kd.Spec.Validations.InitialDelay.Duration)
+	deadline := GetValidationStart(kd)
 
 	if now.After(deadline) {
 		return deadline.Sub(now), true
@@ -65,3 +99,8 @@ func getPods(kclient client.Client, reqLogger logr.Logger, KanaryStatefulsetName
 	}
 	return pods.Items, nil
 }
+
+// isCanaryPod returns true if the pod runs the statefulset update revision
+func isCanaryPod(pod *corev1.Pod, sts *kruisev1alpha1.StatefulSet) bool {
+	return pod.Labels[appsv1.ControllerRevisionHashLabelKey] == sts.Status.UpdateRevision
+}
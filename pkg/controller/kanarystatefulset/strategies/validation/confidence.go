@@ -0,0 +1,131 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+// sumQueryProviderFactory creates the provider of the queries run by runSumQuery, for test purposes
+var sumQueryProviderFactory = anomalydetector.NewPrometheusMetricsProvider
+
+// CheckConfidence returns the reasons why the confidence criterion is not met, none if it is met or not configured
+func CheckConfidence(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, status *kanaryv1alpha1.KanaryStatefulsetStatus) ([]string, error) {
+	confidence := kd.Spec.Validations.Confidence
+	if kd.Spec.Validations.MaxValidationPeriod == nil || confidence == nil {
+		return nil, nil
+	}
+
+	var reasons []string
+	if confidence.NoInconclusive {
+		if inconclusive := GetInconclusiveItems(&kd.Spec.Validations, status); len(inconclusive) > 0 {
+			reasons = append(reasons, fmt.Sprintf("validation items %s are inconclusive", strings.Join(inconclusive, ",")))
+		}
+	}
+	if confidence.MinRequests != nil {
+		requests, err := getConfidenceRequests(kclient, kd, sts, confidence.MinRequests)
+		if err != nil {
+			return nil, err
+		}
+		if requests < confidence.MinRequests.Min {
+			reasons = append(reasons, fmt.Sprintf("%g requests received, %g required", requests, confidence.MinRequests.Min))
+		}
+	}
+	return reasons, nil
+}
+
+// getConfidenceRequests runs the requests query and returns the sum of the last value of each serie
+func getConfidenceRequests(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, spec *kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidenceRequests) (float64, error) {
+	templateData, err := NewQueryTemplateData(kclient, kd, sts)
+	if err != nil {
+		return 0, err
+	}
+	requests, _, err := runSumQuery(kclient, kd.Namespace, templateData, spec.PrometheusService, spec.URL, spec.Auth, spec.Query)
+	if err != nil {
+		return 0, fmt.Errorf("confidence requests query: %v", err)
+	}
+	return requests, nil
+}
+
+// runSumQuery renders and runs a promQL query, it returns the sum of the last value of each serie and the number of series
+func runSumQuery(kclient client.Client, namespace string, templateData *QueryTemplateData, prometheusService, url string, auth *kanaryv1alpha1.PrometheusAuth, query string) (float64, int, error) {
+	return runSumQueryAt(kclient, namespace, templateData, prometheusService, url, auth, query, time.Time{})
+}
+
+// runSumQueryAt is runSumQuery with the query evaluated at the given time, now if zero
+func runSumQueryAt(kclient client.Client, namespace string, templateData *QueryTemplateData, prometheusService, url string, auth *kanaryv1alpha1.PrometheusAuth, query string, at time.Time) (float64, int, error) {
+	rendered, err := RenderQuery(query, templateData)
+	if err != nil {
+		return 0, 0, err
+	}
+	authConfig, err := getPrometheusAuthConfig(kclient, namespace, auth)
+	if err != nil {
+		return 0, 0, err
+	}
+	provider, err := sumQueryProviderFactory(anomalydetector.ConfigPrometheusAnomalyDetector{
+		PrometheusService: prometheusService,
+		URL:               url,
+		Auth:              authConfig,
+		Query:             rendered,
+		AllPodsQuery:      true,
+		Time:              at,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	samples, err := provider.GetSamples()
+	if err != nil {
+		return 0, 0, err
+	}
+	sum, series := 0.0, 0
+	for _, sample := range samples {
+		if len(sample.Values) > 0 {
+			sum += sample.Values[len(sample.Values)-1]
+			series++
+		}
+	}
+	return sum, series, nil
+}
+
+// GetConfidenceExtension returns the duration of the next extension of the validation period,
+// 0 if the validation period already reached maxValidationPeriod.
+func GetConfidenceExtension(kd *kanaryv1alpha1.KanaryStatefulset, status *kanaryv1alpha1.KanaryStatefulsetStatus) time.Duration {
+	list := &kd.Spec.Validations
+	if list.MaxValidationPeriod == nil || list.Confidence == nil || list.Confidence.ExtensionPeriod == nil {
+		return 0
+	}
+	maxDeadline := GetValidationStart(kd).Add(list.MaxValidationPeriod.Duration)
+	remaining := maxDeadline.Sub(ComputeValidationDeadLine(kd, status))
+	if remaining <= 0 {
+		return 0
+	}
+	if remaining < list.Confidence.ExtensionPeriod.Duration {
+		return remaining
+	}
+	return list.Confidence.ExtensionPeriod.Duration
+}
+
+// RecordConfidenceExtension adds an extension of the validation period to the status
+func RecordConfidenceExtension(status *kanaryv1alpha1.KanaryStatefulsetStatus, extension time.Duration, reasons []string, now time.Time) {
+	status.ConfidenceExtensions = append(status.ConfidenceExtensions, kanaryv1alpha1.KanaryStatefulsetValidationExtension{
+		Time:     metav1.NewTime(now),
+		Duration: metav1.Duration{Duration: extension},
+		Reason:   strings.Join(reasons, ", "),
+	})
+}
+
+// getConfidenceExtension returns the duration added to the validation period because the confidence criterion was not met
+func getConfidenceExtension(status *kanaryv1alpha1.KanaryStatefulsetStatus) time.Duration {
+	extension := time.Duration(0)
+	for _, e := range status.ConfidenceExtensions {
+		extension += e.Duration.Duration
+	}
+	return extension
+}
@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func TestCheckConfidence(t *testing.T) {
+	defer func(f func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)) {
+		sumQueryProviderFactory = f
+	}(sumQueryProviderFactory)
+
+	inconclusiveStatus := &kanaryv1alpha1.KanaryStatefulsetStatus{
+		Validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, Name: "errors", Type: "promQL", Inconclusive: true}},
+	}
+	minRequests := &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidenceRequests{
+		PrometheusService: "prometheus:9090",
+		Query:             `sum(increase(requests{namespace="{{.Namespace}}"}[10m]))`,
+		Min:               100,
+	}
+	tests := []struct {
+		name        string
+		confidence  *kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence
+		maxPeriod   *metav1.Duration
+		status      *kanaryv1alpha1.KanaryStatefulsetStatus
+		samples     []anomalydetector.Sample
+		err         error
+		wantReasons []string
+		wantErr     bool
+	}{
+		{
+			name:       "no maxValidationPeriod",
+			confidence: &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{NoInconclusive: true},
+			status:     inconclusiveStatus,
+		},
+		{
+			name:        "inconclusive",
+			confidence:  &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{NoInconclusive: true},
+			maxPeriod:   &metav1.Duration{Duration: time.Hour},
+			status:      inconclusiveStatus,
+			wantReasons: []string{"validation items errors are inconclusive"},
+		},
+		{
+			name:       "conclusive",
+			confidence: &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{NoInconclusive: true},
+			maxPeriod:  &metav1.Duration{Duration: time.Hour},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+		},
+		{
+			name:        "not enough requests",
+			confidence:  &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{MinRequests: minRequests},
+			maxPeriod:   &metav1.Duration{Duration: time.Hour},
+			status:      &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			samples:     []anomalydetector.Sample{{PodName: anomalydetector.GlobalQueryKey, Values: []float64{10, 40}}, {PodName: anomalydetector.GlobalQueryKey, Values: []float64{20}}},
+			wantReasons: []string{"60 requests received, 100 required"},
+		},
+		{
+			name:       "enough requests",
+			confidence: &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{MinRequests: minRequests},
+			maxPeriod:  &metav1.Duration{Duration: time.Hour},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			samples:    []anomalydetector.Sample{{PodName: anomalydetector.GlobalQueryKey, Values: []float64{150}}},
+		},
+		{
+			name:       "both criteria not met",
+			confidence: &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{NoInconclusive: true, MinRequests: minRequests},
+			maxPeriod:  &metav1.Duration{Duration: time.Hour},
+			status:     inconclusiveStatus,
+			wantReasons: []string{
+				"validation items errors are inconclusive",
+				"0 requests received, 100 required",
+			},
+		},
+		{
+			name:       "requests query error",
+			confidence: &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{MinRequests: minRequests},
+			maxPeriod:  &metav1.Duration{Duration: time.Hour},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			err:        fmt.Errorf("unavailable"),
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sumQueryProviderFactory = func(cfg anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error) {
+				if cfg.Query != `sum(increase(requests{namespace="bar"}[10m]))` || !cfg.AllPodsQuery {
+					return nil, fmt.Errorf("unexpected requests query config: %#v", cfg)
+				}
+				return &fakeMetricsProvider{samples: tt.samples, err: tt.err}, nil
+			}
+			kd := &kanaryv1alpha1.KanaryStatefulset{}
+			kd.Namespace = "bar"
+			kd.Spec.Validations.Items = []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "errors"}}
+			kd.Spec.Validations.MaxValidationPeriod = tt.maxPeriod
+			kd.Spec.Validations.Confidence = tt.confidence
+			got, err := CheckConfidence(fake.NewFakeClient([]runtime.Object{}...), kd, nil, tt.status)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckConfidence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.wantReasons) {
+				t.Errorf("CheckConfidence() = %v, want %v", got, tt.wantReasons)
+			}
+		})
+	}
+}
+
+func TestGetConfidenceExtension(t *testing.T) {
+	now := time.Now()
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	kd.CreationTimestamp = metav1.NewTime(now)
+	kd.Spec.Validations.InitialDelay = &metav1.Duration{Duration: time.Minute}
+	kd.Spec.Validations.ValidationPeriod = &metav1.Duration{Duration: 10 * time.Minute}
+	kd.Spec.Validations.MaxValidationPeriod = &metav1.Duration{Duration: 22 * time.Minute}
+	kd.Spec.Validations.Confidence = &kanaryv1alpha1.KanaryStatefulsetSpecValidationConfidence{ExtensionPeriod: &metav1.Duration{Duration: 5 * time.Minute}}
+
+	status := &kanaryv1alpha1.KanaryStatefulsetStatus{}
+	for _, want := range []time.Duration{5 * time.Minute, 5 * time.Minute, 2 * time.Minute, 0} {
+		got := GetConfidenceExtension(kd, status)
+		if got != want {
+			t.Fatalf("GetConfidenceExtension() after %d extension(s) = %v, want %v", len(status.ConfidenceExtensions), got, want)
+		}
+		if got > 0 {
+			RecordConfidenceExtension(status, got, []string{"not enough requests"}, now)
+		}
+	}
+
+	RecordValidationDeadline(kd, status)
+	if want := now.Add(23 * time.Minute); !status.ValidationDeadline.Time.Equal(want) {
+		t.Errorf("ValidationDeadline = %v, want %v", status.ValidationDeadline.Time, want)
+	}
+	if got := status.ConfidenceExtensions[2]; got.Duration.Duration != 2*time.Minute || got.Reason != "not enough requests" {
+		t.Errorf("last extension = %#v", got)
+	}
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// checkDependencies compares the value of each dependency with its baseline, the result fails if a dependency regressed.
+// The baselines are captured at the first check, the queries are evaluated at the canary start.
+func (p *promqlImpl) checkDependencies(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, templateData *QueryTemplateData, result *Result) error {
+	if result.Values == nil {
+		result.Values = map[string]string{}
+	}
+	status := getItemStatus(kd, p.index)
+
+	queries := []string{result.Query}
+	var thresholds, regressions []string
+	for _, dependency := range p.validationSpec.Dependencies {
+		data := *templateData
+		data.Window = formatPromDuration(dependency.BaselineWindow.Duration)
+		rendered, err := RenderQuery(dependency.Query, &data)
+		if err != nil {
+			return fmt.Errorf("dependency %s query: %v", dependency.Name, err)
+		}
+		queries = append(queries, rendered)
+
+		var baseline *kanaryv1alpha1.KanaryStatefulsetValidationBaseline
+		if status != nil {
+			baseline = getBaseline(status, dependency.Name)
+		}
+		if baseline == nil {
+			start := kd.CreationTimestamp.Time
+			value, err := p.runDependencyQuery(kclient, kd, &data, dependency.Query, start)
+			if err != nil {
+				return fmt.Errorf("dependency %s baseline query: %v", dependency.Name, err)
+			}
+			baseline = &kanaryv1alpha1.KanaryStatefulsetValidationBaseline{Name: dependency.Name, Value: value, Time: metav1.NewTime(start)}
+			result.Baselines = append(result.Baselines, *baseline)
+		}
+
+		value, err := p.runDependencyQuery(kclient, kd, &data, dependency.Query, time.Time{})
+		if err != nil {
+			return fmt.Errorf("dependency %s query: %v", dependency.Name, err)
+		}
+		limit := GetDependencyLimit(&dependency, baseline.Value)
+		result.Values["dependency["+dependency.Name+"]"] = strconv.FormatFloat(value, 'f', -1, 64)
+		thresholds = append(thresholds, fmt.Sprintf("%s<=%v", dependency.Name, limit))
+		if value > limit {
+			regressions = append(regressions, fmt.Sprintf("%s %v, baseline %v", dependency.Name, value, baseline.Value))
+		}
+	}
+	result.Query = strings.Join(queries, "\n")
+	result.Threshold = strings.TrimPrefix(result.Threshold+" dependencies: "+strings.Join(thresholds, ","), " ")
+
+	if len(regressions) > 0 {
+		reqLogger.Info("Dependencies", "regression", regressions)
+		comment := "dependencies regressed: " + strings.Join(regressions, "; ")
+		if result.IsFailed {
+			comment = result.Comment + ", " + comment
+		}
+		result.IsFailed = true
+		result.Comment = comment
+		// a regression of a dependency fails the whole check, whatever the share of the pods within bounds
+		result.Score = nil
+	}
+	return nil
+}
+
+// runDependencyQuery returns the value of a dependency query evaluated at the given time, now if zero.
+// A query without data, or with a NaN value, returns 0: for instance no error during the window.
+func (p *promqlImpl) runDependencyQuery(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, data *QueryTemplateData, query string, at time.Time) (float64, error) {
+	value, _, err := runSumQueryAt(kclient, kd.Namespace, data, p.validationSpec.PrometheusService, p.validationSpec.URL, p.validationSpec.Auth, query, at)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, nil
+	}
+	return value, nil
+}
+
+// GetDependencyLimit returns the maximum value tolerated for a dependency with the given baseline
+func GetDependencyLimit(dependency *kanaryv1alpha1.PromQLDependency, baseline float64) float64 {
+	increase := 0.0
+	if dependency.MaxIncreasePercent != nil {
+		increase = math.Abs(baseline) * *dependency.MaxIncreasePercent / 100
+	}
+	if dependency.MinIncrease != nil && *dependency.MinIncrease > increase {
+		increase = *dependency.MinIncrease
+	}
+	return baseline + increase
+}
+
+// getItemStatus returns the status of the validation item, nil if the item was never checked
+func getItemStatus(kd *kanaryv1alpha1.KanaryStatefulset, index int) *kanaryv1alpha1.KanaryStatefulsetValidationStatus {
+	for i := range kd.Status.Validations {
+		if kd.Status.Validations[i].Index == index {
+			return &kd.Status.Validations[i]
+		}
+	}
+	return nil
+}
+
+// getBaseline returns the recorded baseline of the dependency, nil if it was not captured yet
+func getBaseline(status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, name string) *kanaryv1alpha1.KanaryStatefulsetValidationBaseline {
+	for i := range status.Baselines {
+		if status.Baselines[i].Name == name {
+			return &status.Baselines[i]
+		}
+	}
+	return nil
+}
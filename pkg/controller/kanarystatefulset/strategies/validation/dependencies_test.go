@@ -0,0 +1,139 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func TestGetDependencyLimit(t *testing.T) {
+	tests := []struct {
+		name       string
+		dependency kanaryv1alpha1.PromQLDependency
+		baseline   float64
+		want       float64
+	}{
+		{
+			name:       "max increase percent",
+			dependency: kanaryv1alpha1.PromQLDependency{MaxIncreasePercent: kanaryv1alpha1.NewFloat64(10)},
+			baseline:   200,
+			want:       220,
+		},
+		{
+			name:       "min increase with a baseline close to 0",
+			dependency: kanaryv1alpha1.PromQLDependency{MaxIncreasePercent: kanaryv1alpha1.NewFloat64(10), MinIncrease: kanaryv1alpha1.NewFloat64(0.01)},
+			baseline:   0.001,
+			want:       0.011,
+		},
+		{
+			name:       "min increase lower than the percent",
+			dependency: kanaryv1alpha1.PromQLDependency{MaxIncreasePercent: kanaryv1alpha1.NewFloat64(50), MinIncrease: kanaryv1alpha1.NewFloat64(1)},
+			baseline:   10,
+			want:       15,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetDependencyLimit(&tt.dependency, tt.baseline); got != tt.want {
+				t.Errorf("GetDependencyLimit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_promqlImpl_checkDependencies(t *testing.T) {
+	log := logf.Log.WithName("Test_promqlImpl_checkDependencies")
+	defer func(f func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)) {
+		sumQueryProviderFactory = f
+	}(sumQueryProviderFactory)
+
+	creation := time.Now().Add(-time.Hour).Truncate(time.Second)
+	spec := kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{
+		PrometheusService: "prometheus:9090",
+		Dependencies: []kanaryv1alpha1.PromQLDependency{
+			{
+				Name:               "frontend",
+				Query:              `sum(rate(http_errors{service="frontend"}[{{.Window}}]))`,
+				BaselineWindow:     &metav1.Duration{Duration: 10 * time.Minute},
+				MaxIncreasePercent: kanaryv1alpha1.NewFloat64(10),
+			},
+		},
+	}
+	tests := []struct {
+		name          string
+		recorded      []kanaryv1alpha1.KanaryStatefulsetValidationBaseline
+		baseline      float64
+		current       float64
+		err           error
+		wantFailed    bool
+		wantComment   string
+		wantBaselines int
+		wantErr       bool
+	}{
+		{
+			name:          "baseline captured at the canary start",
+			baseline:      2,
+			current:       2.1,
+			wantBaselines: 1,
+		},
+		{
+			name:        "regression against the recorded baseline",
+			recorded:    []kanaryv1alpha1.KanaryStatefulsetValidationBaseline{{Name: "frontend", Value: 1, Time: metav1.NewTime(creation)}},
+			baseline:    100,
+			current:     1.5,
+			wantFailed:  true,
+			wantComment: "dependencies regressed: frontend 1.5, baseline 1",
+		},
+		{
+			name:    "prometheus error",
+			err:     fmt.Errorf("unavailable"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sumQueryProviderFactory = func(cfg anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error) {
+				if cfg.Query != `sum(rate(http_errors{service="frontend"}[10m]))` {
+					return nil, fmt.Errorf("unexpected query: %s", cfg.Query)
+				}
+				if tt.err != nil {
+					return &fakeMetricsProvider{err: tt.err}, nil
+				}
+				value := tt.current
+				if cfg.Time.Equal(creation) {
+					value = tt.baseline
+				} else if !cfg.Time.IsZero() {
+					return nil, fmt.Errorf("unexpected evaluation time: %v", cfg.Time)
+				}
+				return &fakeMetricsProvider{samples: []anomalydetector.Sample{{PodName: anomalydetector.GlobalQueryKey, Values: []float64{value}}}}, nil
+			}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "bar", "", 1, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.CreationTimestamp = metav1.NewTime(creation)
+			kd.Status.Validations = []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, Baselines: tt.recorded}}
+			p := &promqlImpl{validationSpec: spec}
+			result := &Result{}
+			err := p.checkDependencies(fake.NewFakeClient([]runtime.Object{}...), log, kd, &QueryTemplateData{}, result)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("promqlImpl.checkDependencies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if result.IsFailed != tt.wantFailed || result.Comment != tt.wantComment || len(result.Baselines) != tt.wantBaselines {
+				t.Errorf("promqlImpl.checkDependencies() = %#v", result)
+			}
+			if result.Values["dependency[frontend]"] != fmt.Sprint(tt.current) {
+				t.Errorf("the current value of the dependency should be reported, values = %v", result.Values)
+			}
+		})
+	}
+}
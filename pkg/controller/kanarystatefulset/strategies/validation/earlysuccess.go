@@ -0,0 +1,82 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// CheckEarlySuccess returns true if all the early success conditions are met, with the evaluation of each condition.
+// There is no early success before minElapsed, while a validation item is inconclusive or recovering from tolerated failures,
+// while a metrics provider is unreachable, while the score is marginal, or before the end of the chaos phase.
+func CheckEarlySuccess(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, status *kanaryv1alpha1.KanaryStatefulsetStatus, now time.Time) (bool, []string, error) {
+	earlySuccess := kd.Spec.Validations.EarlySuccess
+	if earlySuccess == nil || len(earlySuccess.Conditions) == 0 {
+		return false, nil, nil
+	}
+	if earlySuccess.MinElapsed != nil && now.Sub(GetValidationStart(kd)) < earlySuccess.MinElapsed.Duration {
+		return false, nil, nil
+	}
+	if len(GetInconclusiveItems(&kd.Spec.Validations, status)) > 0 || IsRecovering(&kd.Spec.Validations, status) || IsWaitingForProvider(&kd.Spec.Validations, status) || IsScoreMarginal(&kd.Spec.Validations, status) || IsChaosPending(kd, status) {
+		return false, nil, nil
+	}
+
+	templateData, err := NewQueryTemplateData(kclient, kd, sts)
+	if err != nil {
+		return false, nil, err
+	}
+	var details []string
+	for i := range earlySuccess.Conditions {
+		condition := &earlySuccess.Conditions[i]
+		name := condition.Name
+		if name == "" {
+			name = fmt.Sprintf("conditions[%d]", i)
+		}
+		value, ok, err := runEarlySuccessQuery(kclient, kd.Namespace, templateData, earlySuccess, condition.Query)
+		if err != nil {
+			return false, nil, fmt.Errorf("early success %s query: %v", name, err)
+		}
+		if !ok {
+			return false, nil, nil
+		}
+		threshold := 0.0
+		if condition.Value != nil {
+			threshold = *condition.Value
+		} else {
+			if threshold, ok, err = runEarlySuccessQuery(kclient, kd.Namespace, templateData, earlySuccess, condition.BaselineQuery); err != nil {
+				return false, nil, fmt.Errorf("early success %s baseline query: %v", name, err)
+			}
+			if !ok {
+				return false, nil, nil
+			}
+		}
+		if !isEarlySuccessConditionMet(condition.Operator, value, threshold) {
+			return false, nil, nil
+		}
+		details = append(details, fmt.Sprintf("%s %g %s %g", name, value, condition.Operator, threshold))
+	}
+	return true, details, nil
+}
+
+// runEarlySuccessQuery returns the result of the query, false if the query returned no data
+func runEarlySuccessQuery(kclient client.Client, namespace string, templateData *QueryTemplateData, earlySuccess *kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccess, query string) (float64, bool, error) {
+	value, series, err := runSumQuery(kclient, namespace, templateData, earlySuccess.PrometheusService, earlySuccess.URL, earlySuccess.Auth, query)
+	if err != nil {
+		return 0, false, err
+	}
+	return value, series > 0, nil
+}
+
+func isEarlySuccessConditionMet(operator kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessOperator, value, threshold float64) bool {
+	switch operator {
+	case kanaryv1alpha1.LessThanKanaryStatefulsetSpecValidationEarlySuccessOperator:
+		return value < threshold
+	case kanaryv1alpha1.GreaterThanKanaryStatefulsetSpecValidationEarlySuccessOperator:
+		return value > threshold
+	}
+	return false
+}
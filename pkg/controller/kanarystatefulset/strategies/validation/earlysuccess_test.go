@@ -0,0 +1,120 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func TestCheckEarlySuccess(t *testing.T) {
+	defer func(f func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)) {
+		sumQueryProviderFactory = f
+	}(sumQueryProviderFactory)
+
+	now := time.Now()
+	requests := kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{
+		Name:     "requests",
+		Query:    "requests",
+		Operator: kanaryv1alpha1.GreaterThanKanaryStatefulsetSpecValidationEarlySuccessOperator,
+		Value:    kanaryv1alpha1.NewFloat64(50000),
+	}
+	latency := kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{
+		Query:         "canary_p99",
+		Operator:      kanaryv1alpha1.LessThanKanaryStatefulsetSpecValidationEarlySuccessOperator,
+		BaselineQuery: "stable_p99",
+	}
+	tests := []struct {
+		name        string
+		elapsed     time.Duration
+		conditions  []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition
+		status      *kanaryv1alpha1.KanaryStatefulsetStatus
+		values      map[string][]float64
+		want        bool
+		wantDetails []string
+		wantErr     bool
+	}{
+		{
+			name:       "before minElapsed",
+			elapsed:    5 * time.Minute,
+			conditions: []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			values:     map[string][]float64{"requests": {60000}},
+		},
+		{
+			name:        "conditions met",
+			elapsed:     15 * time.Minute,
+			conditions:  []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests, latency},
+			status:      &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			values:      map[string][]float64{"requests": {40000, 20000}, "canary_p99": {0.2}, "stable_p99": {0.25}},
+			want:        true,
+			wantDetails: []string{"requests 60000 GreaterThan 50000", "conditions[1] 0.2 LessThan 0.25"},
+		},
+		{
+			name:       "baseline not met",
+			elapsed:    15 * time.Minute,
+			conditions: []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests, latency},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			values:     map[string][]float64{"requests": {60000}, "canary_p99": {0.3}, "stable_p99": {0.25}},
+		},
+		{
+			name:       "no data",
+			elapsed:    15 * time.Minute,
+			conditions: []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests, latency},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			values:     map[string][]float64{"requests": {60000}, "canary_p99": {0.2}},
+		},
+		{
+			name:       "recovering",
+			elapsed:    15 * time.Minute,
+			conditions: []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests},
+			status: &kanaryv1alpha1.KanaryStatefulsetStatus{
+				Validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, ConsecutiveFailures: 1}},
+			},
+			values: map[string][]float64{"requests": {60000}},
+		},
+		{
+			name:       "query error",
+			elapsed:    15 * time.Minute,
+			conditions: []kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccessCondition{requests},
+			status:     &kanaryv1alpha1.KanaryStatefulsetStatus{},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sumQueryProviderFactory = func(cfg anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error) {
+				if tt.values == nil {
+					return &fakeMetricsProvider{err: fmt.Errorf("unavailable")}, nil
+				}
+				var samples []anomalydetector.Sample
+				for _, v := range tt.values[cfg.Query] {
+					samples = append(samples, anomalydetector.Sample{PodName: anomalydetector.GlobalQueryKey, Values: []float64{v}})
+				}
+				return &fakeMetricsProvider{samples: samples}, nil
+			}
+			kd := &kanaryv1alpha1.KanaryStatefulset{}
+			kd.CreationTimestamp = metav1.NewTime(now.Add(-tt.elapsed))
+			kd.Spec.Validations.Items = []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "errors"}}
+			kd.Spec.Validations.EarlySuccess = &kanaryv1alpha1.KanaryStatefulsetSpecValidationEarlySuccess{
+				MinElapsed:        &metav1.Duration{Duration: 10 * time.Minute},
+				PrometheusService: "prometheus:9090",
+				Conditions:        tt.conditions,
+			}
+			got, details, err := CheckEarlySuccess(fake.NewFakeClient([]runtime.Object{}...), kd, nil, tt.status, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckEarlySuccess() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want || !reflect.DeepEqual(details, tt.wantDetails) {
+				t.Errorf("CheckEarlySuccess() = %v %v, want %v %v", got, details, tt.want, tt.wantDetails)
+			}
+		})
+	}
+}
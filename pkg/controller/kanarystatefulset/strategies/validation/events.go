@@ -0,0 +1,167 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// NewEvents returns new validation.Events instance
+func NewEvents(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+	return &eventsImpl{
+		validationSpec:   *s.Events,
+		validationPeriod: list.ValidationPeriod.Duration,
+		dryRun:           list.NoUpdate,
+	}
+}
+
+type eventsImpl struct {
+	validationSpec   kanaryv1alpha1.KanaryStatefulsetSpecValidationEvents
+	validationPeriod time.Duration
+	dryRun           bool
+}
+
+// reasonOccurrences occurrences of the events of a reason, and the objects involved
+type reasonOccurrences struct {
+	count   int32
+	objects map[string]bool
+}
+
+func (e *eventsImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{Threshold: fmt.Sprintf("maxOccurrences=%d", e.getMaxOccurrences())}
+	if sts == nil {
+		return result, nil
+	}
+
+	canaryPods, err := e.listCanaryPodNames(kclient, kd.Namespace, sts)
+	if err != nil {
+		return result, err
+	}
+
+	events := &corev1.EventList{}
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: kd.Namespace}, events); err != nil {
+		return result, fmt.Errorf("unable to list the events: %v", err)
+	}
+
+	denied := map[string]bool{}
+	for _, reason := range e.validationSpec.Reasons {
+		denied[reason] = true
+	}
+	start := GetValidationStart(kd)
+	occurrencesByReason := map[string]*reasonOccurrences{}
+	for i := range events.Items {
+		ev := &events.Items[i]
+		if ev.Type != corev1.EventTypeWarning || !denied[ev.Reason] {
+			continue
+		}
+		if !isInvolvingCanary(ev, kd.Spec.StatefulSetName, canaryPods) {
+			continue
+		}
+		count := countEventOccurrencesSince(ev, start)
+		if count == 0 {
+			continue
+		}
+		occurrences, ok := occurrencesByReason[ev.Reason]
+		if !ok {
+			occurrences = &reasonOccurrences{objects: map[string]bool{}}
+			occurrencesByReason[ev.Reason] = occurrences
+		}
+		occurrences.count += count
+		occurrences.objects[ev.InvolvedObject.Name] = true
+	}
+
+	reasons := []string{}
+	for reason := range occurrencesByReason {
+		reasons = append(reasons, reason)
+	}
+	sort.Strings(reasons)
+	result.Values = map[string]string{}
+	var comments []string
+	for _, reason := range reasons {
+		occurrences := occurrencesByReason[reason]
+		result.Values[reason] = strconv.Itoa(int(occurrences.count))
+		if occurrences.count <= e.getMaxOccurrences() {
+			continue
+		}
+		objects := []string{}
+		for name := range occurrences.objects {
+			objects = append(objects, name)
+		}
+		sort.Strings(objects)
+		comments = append(comments, fmt.Sprintf("%s x%d (%s)", reason, occurrences.count, strings.Join(objects, ", ")))
+	}
+
+	if len(comments) > 0 {
+		result.IsFailed = true
+		result.Comment = "too many warning events on kanary pods, " + strings.Join(comments, "; ")
+		reqLogger.Info("Events validation", "detection", len(comments))
+	}
+	return result, nil
+}
+
+func (e *eventsImpl) getMaxOccurrences() int32 {
+	if e.validationSpec.MaxOccurrences == nil {
+		return 0
+	}
+	return *e.validationSpec.MaxOccurrences
+}
+
+// listCanaryPodNames returns the names of the statefulset pods running the version under validation
+func (e *eventsImpl) listCanaryPodNames(kclient client.Client, namespace string, sts *kruisev1alpha1.StatefulSet) (map[string]bool, error) {
+	names := map[string]bool{}
+	if sts.Spec.Selector == nil {
+		return names, nil
+	}
+	selector := labels.SelectorFromSet(sts.Spec.Selector.MatchLabels)
+	list := &corev1.PodList{}
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: namespace, LabelSelector: selector}, list); err != nil {
+		return nil, fmt.Errorf("unable to list the statefulset pods: %v", err)
+	}
+	for i := range list.Items {
+		p := &list.Items[i]
+		if selector.Matches(labels.Set(p.Labels)) && isCanaryPod(p, sts) {
+			names[p.Name] = true
+		}
+	}
+	return names, nil
+}
+
+// isInvolvingCanary returns true if the event involves the statefulset or one of its canary pods
+func isInvolvingCanary(ev *corev1.Event, statefulSetName string, canaryPods map[string]bool) bool {
+	switch ev.InvolvedObject.Kind {
+	case "StatefulSet":
+		return ev.InvolvedObject.Name == statefulSetName
+	case "Pod":
+		return canaryPods[ev.InvolvedObject.Name]
+	}
+	return false
+}
+
+// countEventOccurrencesSince returns the number of occurrences of the event since the start. The events are
+// aggregated by the apiserver, if the first occurrence is before the start only the last one is counted.
+func countEventOccurrencesSince(ev *corev1.Event, start time.Time) int32 {
+	last := ev.LastTimestamp.Time
+	if last.IsZero() {
+		last = ev.EventTime.Time
+	}
+	if last.Before(start) {
+		return 0
+	}
+	first := ev.FirstTimestamp.Time
+	if first.IsZero() || first.Before(start) || ev.Count < 1 {
+		return 1
+	}
+	return ev.Count
+}
@@ -0,0 +1,110 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	test "github.com/k8s-kanary/kanary/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func Test_eventsImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_eventsImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+	)
+	now := time.Now()
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	podLabels := func(revision string) map[string]string {
+		return map[string]string{"app": name, appsv1.ControllerRevisionHashLabelKey: revision}
+	}
+	newEvent := func(eventName, kind, object, reason, eventType string, first, last time.Duration, count int32) runtime.Object {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: eventName, Namespace: namespace},
+			InvolvedObject: corev1.ObjectReference{Kind: kind, Name: object, Namespace: namespace},
+			Reason:         reason,
+			Type:           eventType,
+			FirstTimestamp: metav1.Time{Time: now.Add(first)},
+			LastTimestamp:  metav1.Time{Time: now.Add(last)},
+			Count:          count,
+		}
+	}
+	kclient := fake.NewFakeClient([]runtime.Object{
+		test.PodGen(name+"-0", namespace, podLabels("v1"), nil, true, true),
+		test.PodGen(name+"-1", namespace, podLabels("v2"), nil, true, true),
+		test.PodGen(name+"-2", namespace, podLabels("v2"), nil, true, false),
+		// canary pods, counted
+		newEvent("e1", "Pod", name+"-1", "Unhealthy", corev1.EventTypeWarning, -4*time.Minute, -time.Minute, 3),
+		newEvent("e2", "Pod", name+"-2", "Unhealthy", corev1.EventTypeWarning, -3*time.Minute, -3*time.Minute, 1),
+		newEvent("e3", "StatefulSet", name, "FailedCreate", corev1.EventTypeWarning, -3*time.Minute, -2*time.Minute, 2),
+		// first occurrence before the validation start, only the last one is counted
+		newEvent("e4", "Pod", name+"-1", "BackOff", corev1.EventTypeWarning, -20*time.Minute, -time.Minute, 5),
+		// ignored: stable pod, before the validation start, normal event, other statefulset
+		newEvent("e5", "Pod", name+"-0", "Unhealthy", corev1.EventTypeWarning, -2*time.Minute, -time.Minute, 4),
+		newEvent("e6", "Pod", name+"-1", "Unhealthy", corev1.EventTypeWarning, -20*time.Minute, -15*time.Minute, 4),
+		newEvent("e7", "Pod", name+"-1", "BackOff", corev1.EventTypeNormal, -2*time.Minute, -time.Minute, 4),
+		newEvent("e8", "StatefulSet", "bar", "FailedCreate", corev1.EventTypeWarning, -2*time.Minute, -time.Minute, 4),
+	}...)
+
+	tests := []struct {
+		name string
+		spec kanaryv1alpha1.KanaryStatefulsetSpecValidationEvents
+		want *Result
+	}{
+		{
+			name: "reasons not in the deny-list",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationEvents{Reasons: []string{"FailedMount"}, MaxOccurrences: kanaryv1alpha1.NewInt32(0)},
+			want: &Result{Threshold: "maxOccurrences=0", Values: map[string]string{}},
+		},
+		{
+			name: "occurrences below the limit",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationEvents{Reasons: []string{"Unhealthy", "BackOff"}, MaxOccurrences: kanaryv1alpha1.NewInt32(4)},
+			want: &Result{Threshold: "maxOccurrences=4", Values: map[string]string{"Unhealthy": "4", "BackOff": "1"}},
+		},
+		{
+			name: "occurrences above the limit",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationEvents{Reasons: []string{"Unhealthy", "BackOff", "FailedCreate"}, MaxOccurrences: kanaryv1alpha1.NewInt32(1)},
+			want: &Result{
+				IsFailed:  true,
+				Comment:   "too many warning events on kanary pods, FailedCreate x2 (foo); Unhealthy x4 (foo-1, foo-2)",
+				Threshold: "maxOccurrences=1",
+				Values:    map[string]string{"Unhealthy": "4", "BackOff": "1", "FailedCreate": "2"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := &eventsImpl{validationSpec: tt.spec}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, name, defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.Spec.StatefulSetName = name
+			kd.CreationTimestamp = metav1.Time{Time: now.Add(-10 * time.Minute)}
+			got, err := e.Validation(kclient, log.WithValues("test:", tt.name), kd, nil, nil, sts)
+			if err != nil {
+				t.Errorf("eventsImpl.Validation() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("eventsImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
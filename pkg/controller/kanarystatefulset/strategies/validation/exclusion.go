@@ -0,0 +1,96 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+const (
+	// ExclusionReasonWarmUp the pod became Ready less than warmUp ago
+	ExclusionReasonWarmUp = "warming up"
+	// ExclusionReasonMinRequests the pod received fewer requests than minRequests
+	ExclusionReasonMinRequests = "not enough requests"
+	// ExclusionReasonLabel the pod carries an exclusion label
+	ExclusionReasonLabel = "exclusion label"
+)
+
+// podExclusions decides which pods are excluded from the anomaly detection, and records the reason
+type podExclusions struct {
+	config   kanaryv1alpha1.PromQLExclusions
+	now      time.Time
+	selector labels.Selector
+	// requests number of requests by pod name, nil if the requests are not checked
+	requests map[string]float64
+	// excluded reason of the exclusion by pod name
+	excluded map[string]string
+}
+
+func newPodExclusions(config kanaryv1alpha1.PromQLExclusions, requests map[string]float64, now time.Time) (*podExclusions, error) {
+	e := &podExclusions{config: config, now: now, requests: requests, excluded: map[string]string{}}
+	if config.Labels != nil {
+		selector, err := metav1.LabelSelectorAsSelector(config.Labels)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create the label selector from exclusions.labels: %v", err)
+		}
+		e.selector = selector
+	}
+	return e, nil
+}
+
+// isExcluded implements the anomalydetector.Config ExclusionFunc
+func (e *podExclusions) isExcluded(pod *corev1.Pod) (bool, error) {
+	reason := e.getReason(pod)
+	if reason == "" {
+		return false, nil
+	}
+	e.excluded[pod.Name] = reason
+	return true, nil
+}
+
+func (e *podExclusions) getReason(pod *corev1.Pod) string {
+	if e.selector != nil && e.selector.Matches(labels.Set(pod.Labels)) {
+		return ExclusionReasonLabel
+	}
+	if e.config.WarmUp != nil {
+		if readyTime := getPodReadyTime(pod); readyTime != nil && e.now.Sub(readyTime.Time) < e.config.WarmUp.Duration {
+			return ExclusionReasonWarmUp
+		}
+	}
+	if e.requests != nil && e.config.MinRequests != nil && e.requests[pod.Name] < *e.config.MinRequests {
+		return ExclusionReasonMinRequests
+	}
+	return ""
+}
+
+// getPodReadyTime returns the time of the last Ready transition of a ready pod, nil if the pod is not ready
+func getPodReadyTime(pod *corev1.Pod) *metav1.Time {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Status == corev1.ConditionTrue {
+			return &condition.LastTransitionTime
+		}
+	}
+	return nil
+}
+
+// getRequestsByPod returns the last value of each serie of the companion requests query, indexed by pod name
+func getRequestsByPod(provider anomalydetector.MetricsProvider) (map[string]float64, error) {
+	samples, err := provider.GetSamples()
+	if err != nil {
+		return nil, fmt.Errorf("exclusions requests query: %v", err)
+	}
+	requests := map[string]float64{}
+	for _, sample := range samples {
+		if sample.PodName == "" || len(sample.Values) == 0 {
+			continue
+		}
+		requests[sample.PodName] += sample.Values[len(sample.Values)-1]
+	}
+	return requests, nil
+}
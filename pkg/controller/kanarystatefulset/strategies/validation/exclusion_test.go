@@ -0,0 +1,136 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func Test_podExclusions_getReason(t *testing.T) {
+	now := time.Now()
+	newPod := func(readySince *time.Duration, podLabels map[string]string) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod", Labels: podLabels}}
+		if readySince != nil {
+			pod.Status.Conditions = []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: now.Add(-*readySince)}},
+			}
+		}
+		return pod
+	}
+	minute, hour := time.Minute, time.Hour
+	config := kanaryv1alpha1.PromQLExclusions{
+		WarmUp:        &metav1.Duration{Duration: 5 * time.Minute},
+		MinRequests:   kanaryv1alpha1.NewFloat64(10),
+		RequestsQuery: "requests",
+		Labels:        &metav1.LabelSelector{MatchLabels: map[string]string{"exclude": "true"}},
+	}
+
+	tests := []struct {
+		name     string
+		config   kanaryv1alpha1.PromQLExclusions
+		requests map[string]float64
+		pod      *corev1.Pod
+		want     string
+	}{
+		{
+			name:     "not excluded",
+			config:   config,
+			requests: map[string]float64{"pod": 10},
+			pod:      newPod(&hour, nil),
+			want:     "",
+		},
+		{
+			name:     "exclusion label",
+			config:   config,
+			requests: map[string]float64{"pod": 0},
+			pod:      newPod(&minute, map[string]string{"exclude": "true"}),
+			want:     ExclusionReasonLabel,
+		},
+		{
+			name:     "warming up",
+			config:   config,
+			requests: map[string]float64{"pod": 0},
+			pod:      newPod(&minute, nil),
+			want:     ExclusionReasonWarmUp,
+		},
+		{
+			name:   "not ready, warm-up not applicable",
+			config: kanaryv1alpha1.PromQLExclusions{WarmUp: config.WarmUp},
+			pod:    newPod(nil, nil),
+			want:   "",
+		},
+		{
+			name:     "not enough requests",
+			config:   config,
+			requests: map[string]float64{"pod": 9},
+			pod:      newPod(&hour, nil),
+			want:     ExclusionReasonMinRequests,
+		},
+		{
+			name:     "no requests serie",
+			config:   config,
+			requests: map[string]float64{},
+			pod:      newPod(&hour, nil),
+			want:     ExclusionReasonMinRequests,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := newPodExclusions(tt.config, tt.requests, now)
+			if err != nil {
+				t.Fatalf("newPodExclusions() error = %v", err)
+			}
+			excluded, _ := e.isExcluded(tt.pod)
+			if got := e.getReason(tt.pod); got != tt.want {
+				t.Errorf("podExclusions.getReason() = %q, want %q", got, tt.want)
+			}
+			if excluded != (tt.want != "") || (excluded && e.excluded[tt.pod.Name] != tt.want) {
+				t.Errorf("podExclusions.isExcluded() = %v, excluded = %v", excluded, e.excluded)
+			}
+		})
+	}
+}
+
+func Test_getRequestsByPod(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider anomalydetector.MetricsProvider
+		want     map[string]float64
+		wantErr  bool
+	}{
+		{
+			name: "last value summed by pod",
+			provider: &fakeMetricsProvider{samples: []anomalydetector.Sample{
+				{PodName: "pod-0", Values: []float64{1, 5}},
+				{PodName: "pod-0", Values: []float64{2}},
+				{PodName: "pod-1", Values: []float64{}},
+				{PodName: "", Values: []float64{3}},
+			}},
+			want: map[string]float64{"pod-0": 7},
+		},
+		{
+			name:     "provider error",
+			provider: &fakeMetricsProvider{err: fmt.Errorf("unreachable")},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getRequestsByPod(tt.provider)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getRequestsByPod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getRequestsByPod() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// GetInconclusiveItems returns the validation items whose last check is inconclusive, the advisory items are ignored.
+// An item is identified by its name, or by its type and index if it has no name.
+func GetInconclusiveItems(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) []string {
+	var items []string
+	for _, v := range status.Validations {
+		if !v.Inconclusive || v.Index >= len(list.Items) || IsAdvisory(list, v.Index) {
+			continue
+		}
+		if v.Name != "" {
+			items = append(items, v.Name)
+		} else {
+			items = append(items, fmt.Sprintf("%s[%d]", v.Type, v.Index))
+		}
+	}
+	return items
+}
+
+// GetInconclusiveAction returns the action applied to the inconclusive items at the end of the validation period, Fail if not configured
+func GetInconclusiveAction(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList) kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusiveAction {
+	if list.Inconclusive == nil || list.Inconclusive.Action == "" {
+		return kanaryv1alpha1.FailKanaryStatefulsetSpecValidationInconclusiveAction
+	}
+	return list.Inconclusive.Action
+}
+
+// CanExtendInconclusive returns true if the inconclusive action is Extend and the maximum number of extensions is not reached
+func CanExtendInconclusive(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	if GetInconclusiveAction(list) != kanaryv1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction {
+		return false
+	}
+	return list.Inconclusive.MaxExtensions == nil || status.InconclusiveExtensions < *list.Inconclusive.MaxExtensions
+}
+
+// getInconclusiveExtension returns the duration added to the validation period due to inconclusive items
+func getInconclusiveExtension(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) time.Duration {
+	if list.Inconclusive == nil || list.Inconclusive.ExtensionPeriod == nil {
+		return 0
+	}
+	return time.Duration(status.InconclusiveExtensions) * list.Inconclusive.ExtensionPeriod.Duration
+}
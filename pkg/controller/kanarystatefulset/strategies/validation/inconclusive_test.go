@@ -0,0 +1,150 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func TestGetInconclusiveItems(t *testing.T) {
+	list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+			{Name: "errors"},
+			{},
+			{Name: "latency", Advisory: true},
+			{Name: "saturation"},
+		},
+	}
+	status := &kanaryv1alpha1.KanaryStatefulsetStatus{
+		Validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+			{Index: 0, Name: "errors", Type: "promQL", Inconclusive: true},
+			{Index: 1, Type: "metrics", Inconclusive: true},
+			{Index: 2, Name: "latency", Type: "promQL", Inconclusive: true},
+			{Index: 3, Name: "saturation", Type: "promQL"},
+		},
+	}
+	want := []string{"errors", "metrics[1]"}
+	if got := GetInconclusiveItems(list, status); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetInconclusiveItems() = %v, want %v", got, want)
+	}
+}
+
+func TestCanExtendInconclusive(t *testing.T) {
+	tests := []struct {
+		name       string
+		list       *kanaryv1alpha1.KanaryStatefulsetSpecValidationList
+		extensions int32
+		want       bool
+		wantAction kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusiveAction
+	}{
+		{
+			name:       "not configured",
+			list:       &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{},
+			want:       false,
+			wantAction: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationInconclusiveAction,
+		},
+		{
+			name: "pass",
+			list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Inconclusive: &kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusive{Action: kanaryv1alpha1.PassKanaryStatefulsetSpecValidationInconclusiveAction},
+			},
+			want:       false,
+			wantAction: kanaryv1alpha1.PassKanaryStatefulsetSpecValidationInconclusiveAction,
+		},
+		{
+			name: "extend",
+			list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Inconclusive: &kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusive{Action: kanaryv1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction, MaxExtensions: kanaryv1alpha1.NewInt32(2)},
+			},
+			extensions: 1,
+			want:       true,
+			wantAction: kanaryv1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction,
+		},
+		{
+			name: "extend, max extensions reached",
+			list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Inconclusive: &kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusive{Action: kanaryv1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction, MaxExtensions: kanaryv1alpha1.NewInt32(2)},
+			},
+			extensions: 2,
+			want:       false,
+			wantAction: kanaryv1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &kanaryv1alpha1.KanaryStatefulsetStatus{InconclusiveExtensions: tt.extensions}
+			if got := CanExtendInconclusive(tt.list, status); got != tt.want {
+				t.Errorf("CanExtendInconclusive() = %v, want %v", got, tt.want)
+			}
+			if got := GetInconclusiveAction(tt.list); got != tt.wantAction {
+				t.Errorf("GetInconclusiveAction() = %v, want %v", got, tt.wantAction)
+			}
+		})
+	}
+}
+
+func Test_getInconclusiveExtension(t *testing.T) {
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	kd.Spec.Validations.Inconclusive = &kanaryv1alpha1.KanaryStatefulsetSpecValidationInconclusive{ExtensionPeriod: &metav1.Duration{Duration: 5 * time.Minute}}
+	kd.Status.InconclusiveExtensions = 2
+	if got := getInconclusiveExtension(&kd.Spec.Validations, &kd.Status); got != 10*time.Minute {
+		t.Errorf("getInconclusiveExtension() = %v, want %v", got, 10*time.Minute)
+	}
+}
+
+// fakeInconclusiveDetector anomaly detector reporting inconclusive pods
+type fakeInconclusiveDetector struct {
+	anomalydetector.Fake
+	inconclusive []string
+}
+
+func (f *fakeInconclusiveDetector) GetInconclusivePods() []string {
+	return f.inconclusive
+}
+
+func Test_setResultInconclusive(t *testing.T) {
+	tests := []struct {
+		name     string
+		result   *Result
+		detector anomalydetector.AnomalyDetector
+		want     *Result
+	}{
+		{
+			name:     "not a reporter",
+			result:   &Result{},
+			detector: &anomalydetector.Fake{},
+			want:     &Result{},
+		},
+		{
+			name:     "conclusive",
+			result:   &Result{},
+			detector: &fakeInconclusiveDetector{},
+			want:     &Result{},
+		},
+		{
+			name:     "inconclusive",
+			result:   &Result{},
+			detector: &fakeInconclusiveDetector{inconclusive: []string{"pod-0", "pod-1"}},
+			want:     &Result{IsInconclusive: true, Comment: "inconclusive, not enough samples for the pods: pod-0,pod-1"},
+		},
+		{
+			name:     "failed takes precedence",
+			result:   &Result{IsFailed: true, Comment: "failed"},
+			detector: &fakeInconclusiveDetector{inconclusive: []string{"pod-0"}},
+			want:     &Result{IsFailed: true, Comment: "failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setResultInconclusive(tt.result, tt.detector)
+			if !reflect.DeepEqual(tt.result, tt.want) {
+				t.Errorf("setResultInconclusive() = %#v, want %#v", tt.result, tt.want)
+			}
+		})
+	}
+}
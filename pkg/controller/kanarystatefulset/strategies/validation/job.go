@@ -0,0 +1,241 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// JobKanaryServiceEnvVar name of the environment variable containing the DNS name of the kanary service
+	JobKanaryServiceEnvVar = "KANARY_SERVICE"
+	// JobKanaryPodIPsEnvVar name of the environment variable containing the comma separated IPs of the canary pods
+	JobKanaryPodIPsEnvVar = "KANARY_POD_IPS"
+
+	// jobValueKey key of the job state in the validation values, it is used to keep the outcome of the
+	// run once the Job has been deleted
+	jobValueKey = "job"
+
+	jobStateRunning          = "running"
+	jobStateSucceeded        = "succeeded"
+	jobStateFailed           = "failed"
+	jobStateDeadlineExceeded = "deadlineExceeded"
+)
+
+// NewJob returns new validation.Job instance
+func NewJob(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation, index int) Interface {
+	return &jobImpl{
+		validationSpec: *s.Job,
+		index:          index,
+		dryRun:         list.NoUpdate,
+	}
+}
+
+type jobImpl struct {
+	validationSpec kanaryv1alpha1.KanaryStatefulsetSpecValidationJob
+	index          int
+	dryRun         bool
+}
+
+func (j *jobImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{}
+
+	// the outcome of the run is kept in the validation status, the Job is deleted once this outcome has been recorded
+	if state := j.getPreviousState(kd); state != "" && state != jobStateRunning {
+		if err := deleteJob(kclient, reqLogger, kd, j.index); err != nil {
+			return result, err
+		}
+		return j.setResult(result, state), nil
+	}
+
+	job := &batchv1.Job{}
+	err := kclient.Get(context.TODO(), types.NamespacedName{Name: GetJobName(kd, j.index), Namespace: kd.Namespace}, job)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return result, fmt.Errorf("unable to get the validation job: %v", err)
+	}
+	if apierrors.IsNotFound(err) {
+		if job, err = j.newJob(kclient, kd, sts); err != nil {
+			return result, err
+		}
+		if err = kclient.Create(context.TODO(), job); err != nil {
+			return result, fmt.Errorf("unable to create the validation job: %v", err)
+		}
+		reqLogger.Info("Job validation", "created", job.Name)
+		return j.setResult(result, jobStateRunning), nil
+	}
+
+	state := getJobState(job)
+	if state == jobStateRunning {
+		if IsDeadlinePeriodDone(kd) {
+			result.IsFailed = true
+			result.Comment = fmt.Sprintf("validation job %s not completed before the end of the validation", job.Name)
+		}
+		return j.setResult(result, state), nil
+	}
+
+	// the Job is only deleted by the next check: if the outcome can't be recorded, the Job is not run again
+	reqLogger.Info("Job validation", "completed", job.Name, "state", state)
+	return j.setResult(result, state), nil
+}
+
+// DeleteJobs deletes the Jobs of the job validation items, it is used once the validation is over
+// to clean up the Jobs still running or whose outcome has not been checked again.
+func DeleteJobs(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset) error {
+	for i, item := range kd.Spec.Validations.Items {
+		if item.Job == nil {
+			continue
+		}
+		if err := deleteJob(kclient, reqLogger, kd, i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteJob deletes the Job of a job validation item, if it still exists
+func deleteJob(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, index int) error {
+	job := &batchv1.Job{}
+	err := kclient.Get(context.TODO(), types.NamespacedName{Name: GetJobName(kd, index), Namespace: kd.Namespace}, job)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get the validation job: %v", err)
+	}
+	if job.DeletionTimestamp != nil {
+		return nil
+	}
+	if err = kclient.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("unable to delete the validation job: %v", err)
+	}
+	reqLogger.Info("Job validation", "deleted", job.Name)
+	return nil
+}
+
+// setResult maps the state of the Job to the validation result
+func (j *jobImpl) setResult(result *Result, state string) *Result {
+	result.Values = map[string]string{jobValueKey: state}
+	switch state {
+	case jobStateFailed:
+		result.IsFailed = true
+		result.Comment = "validation job failed"
+	case jobStateDeadlineExceeded:
+		result.IsFailed = true
+		result.Comment = "validation job deadline exceeded"
+	}
+	return result
+}
+
+// getPreviousState returns the state of the Job recorded by the previous check of this validation item
+func (j *jobImpl) getPreviousState(kd *kanaryv1alpha1.KanaryStatefulset) string {
+	for _, status := range kd.Status.Validations {
+		if status.Index != j.index {
+			continue
+		}
+		for _, value := range status.Values {
+			if value.Name == jobValueKey {
+				return value.Value
+			}
+		}
+	}
+	return ""
+}
+
+// newJob returns the Job created from the template, owned by the KanaryStatefulset
+func (j *jobImpl) newJob(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet) (*batchv1.Job, error) {
+	podIPs, err := listCanaryPodIPs(kclient, kd.Namespace, sts)
+	if err != nil {
+		return nil, err
+	}
+	env := []corev1.EnvVar{
+		{Name: JobKanaryServiceEnvVar, Value: fmt.Sprintf("%s.%s.svc", utils.GetCanaryServiceName(kd), kd.Namespace)},
+		{Name: JobKanaryPodIPsEnvVar, Value: strings.Join(podIPs, ",")},
+	}
+
+	template := j.validationSpec.Template.DeepCopy()
+	job := &batchv1.Job{
+		ObjectMeta: template.ObjectMeta,
+		Spec:       template.Spec,
+	}
+	job.Name = GetJobName(kd, j.index)
+	job.Namespace = kd.Namespace
+	job.GenerateName = ""
+	if job.Labels == nil {
+		job.Labels = map[string]string{}
+	}
+	job.Labels[kanaryv1alpha1.KanaryStatefulsetKanaryNameLabelKey] = kd.Name
+	if job.Spec.Template.Spec.RestartPolicy == "" {
+		job.Spec.Template.Spec.RestartPolicy = corev1.RestartPolicyNever
+	}
+	// the variables are added first, so the ones defined in the template take precedence
+	for i := range job.Spec.Template.Spec.Containers {
+		container := &job.Spec.Template.Spec.Containers[i]
+		container.Env = append(append([]corev1.EnvVar{}, env...), container.Env...)
+	}
+
+	if err := controllerutil.SetControllerReference(kd, job, utils.PrepareSchemeForOwnerRef()); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetJobName returns the name of the Job created by a job validation item
+func GetJobName(kd *kanaryv1alpha1.KanaryStatefulset, index int) string {
+	return fmt.Sprintf("%s-validation-%d", kd.Name, index)
+}
+
+// getJobState returns the state of the Job from its conditions
+func getJobState(job *batchv1.Job) string {
+	for _, condition := range job.Status.Conditions {
+		if condition.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch condition.Type {
+		case batchv1.JobComplete:
+			return jobStateSucceeded
+		case batchv1.JobFailed:
+			if condition.Reason == "DeadlineExceeded" {
+				return jobStateDeadlineExceeded
+			}
+			return jobStateFailed
+		}
+	}
+	return jobStateRunning
+}
+
+// listCanaryPodIPs returns the sorted IPs of the statefulset pods running the version under validation
+func listCanaryPodIPs(kclient client.Client, namespace string, sts *kruisev1alpha1.StatefulSet) ([]string, error) {
+	ips := []string{}
+	if sts == nil || sts.Spec.Selector == nil {
+		return ips, nil
+	}
+	selector := labels.SelectorFromSet(sts.Spec.Selector.MatchLabels)
+	list := &corev1.PodList{}
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: namespace, LabelSelector: selector}, list); err != nil {
+		return nil, fmt.Errorf("unable to list the statefulset pods: %v", err)
+	}
+	for i := range list.Items {
+		p := &list.Items[i]
+		if selector.Matches(labels.Set(p.Labels)) && isCanaryPod(p, sts) && p.Status.PodIP != "" {
+			ips = append(ips, p.Status.PodIP)
+		}
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
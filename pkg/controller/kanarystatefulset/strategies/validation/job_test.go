@@ -0,0 +1,238 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	test "github.com/k8s-kanary/kanary/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func Test_jobImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_jobImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+		jobName         = "foo-validation-1"
+	)
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	newPod := func(podName, revision, ip string) runtime.Object {
+		p := test.PodGen(podName, namespace, map[string]string{"app": name, appsv1.ControllerRevisionHashLabelKey: revision}, nil, true, true)
+		p.Status.PodIP = ip
+		return p
+	}
+	pods := []runtime.Object{newPod(name+"-0", "v1", "10.0.0.1"), newPod(name+"-2", "v2", "10.0.0.3"), newPod(name+"-1", "v2", "10.0.0.2")}
+	newJob := func(conditions ...batchv1.JobCondition) runtime.Object {
+		return &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: jobName, Namespace: namespace},
+			Status:     batchv1.JobStatus{Conditions: conditions},
+		}
+	}
+	spec := kanaryv1alpha1.KanaryStatefulsetSpecValidationJob{
+		Template: batchv1beta1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "qa"}},
+			Spec: batchv1.JobSpec{
+				Template: corev1.PodTemplateSpec{
+					Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "tests", Image: "tests:latest", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}}}},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		objects       []runtime.Object
+		previousState string
+		deadlineDone  bool
+		want          *Result
+		wantJob       bool
+	}{
+		{
+			name:    "job created",
+			objects: pods,
+			want:    &Result{Values: map[string]string{"job": "running"}},
+			wantJob: true,
+		},
+		{
+			name:    "job running",
+			objects: append([]runtime.Object{newJob()}, pods...),
+			want:    &Result{Values: map[string]string{"job": "running"}},
+			wantJob: true,
+		},
+		{
+			name:         "job still running at the end of the validation",
+			objects:      append([]runtime.Object{newJob()}, pods...),
+			deadlineDone: true,
+			want:         &Result{IsFailed: true, Comment: "validation job foo-validation-1 not completed before the end of the validation", Values: map[string]string{"job": "running"}},
+			wantJob:      true,
+		},
+		{
+			name:    "job succeeded",
+			objects: []runtime.Object{newJob(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+			want:    &Result{Values: map[string]string{"job": "succeeded"}},
+			wantJob: true,
+		},
+		{
+			name:    "job failed",
+			objects: []runtime.Object{newJob(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"})},
+			want:    &Result{IsFailed: true, Comment: "validation job failed", Values: map[string]string{"job": "failed"}},
+			wantJob: true,
+		},
+		{
+			name:    "job deadline exceeded",
+			objects: []runtime.Object{newJob(batchv1.JobCondition{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded"})},
+			want:    &Result{IsFailed: true, Comment: "validation job deadline exceeded", Values: map[string]string{"job": "deadlineExceeded"}},
+			wantJob: true,
+		},
+		{
+			name:          "job outcome recorded",
+			objects:       []runtime.Object{newJob(batchv1.JobCondition{Type: batchv1.JobComplete, Status: corev1.ConditionTrue})},
+			previousState: "succeeded",
+			want:          &Result{Values: map[string]string{"job": "succeeded"}},
+		},
+		{
+			name:          "job already run and deleted",
+			objects:       pods,
+			previousState: "succeeded",
+			want:          &Result{Values: map[string]string{"job": "succeeded"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kclient := fake.NewFakeClient(tt.objects...)
+			j := &jobImpl{validationSpec: spec, index: 1}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, name, defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.CreationTimestamp = metav1.Now()
+			kd.Spec.Validations.ValidationPeriod = &metav1.Duration{Duration: time.Hour}
+			if tt.deadlineDone {
+				kd.CreationTimestamp = metav1.Time{Time: time.Now().Add(-2 * time.Hour)}
+			}
+			if tt.previousState != "" {
+				kd.Status.Validations = []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+					{Index: 1, Values: []kanaryv1alpha1.KanaryStatefulsetValidationValue{{Name: "job", Value: tt.previousState}}},
+				}
+			}
+			got, err := j.Validation(kclient, log.WithValues("test:", tt.name), kd, nil, nil, sts)
+			if err != nil {
+				t.Errorf("jobImpl.Validation() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("jobImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+
+			job := &batchv1.Job{}
+			err = kclient.Get(context.TODO(), types.NamespacedName{Name: jobName, Namespace: namespace}, job)
+			if tt.wantJob != (err == nil) {
+				t.Errorf("jobImpl.Validation() job found = %v, want %v", err == nil, tt.wantJob)
+			}
+			if err != nil && !apierrors.IsNotFound(err) {
+				t.Errorf("unable to get the job: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeleteJobs(t *testing.T) {
+	log := logf.Log.WithName("TestDeleteJobs")
+	kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "kanary", "foo", 1, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+	kd.Spec.Validations.Items = []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+		{Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}},
+		{Job: &kanaryv1alpha1.KanaryStatefulsetSpecValidationJob{}},
+		{Job: &kanaryv1alpha1.KanaryStatefulsetSpecValidationJob{}},
+	}
+	// the Job of the second job item has already been deleted
+	kclient := fake.NewFakeClient(&batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: "foo-validation-1", Namespace: "kanary"}})
+
+	if err := DeleteJobs(kclient, log, kd); err != nil {
+		t.Fatalf("DeleteJobs() error = %v", err)
+	}
+	if err := kclient.Get(context.TODO(), types.NamespacedName{Name: "foo-validation-1", Namespace: "kanary"}, &batchv1.Job{}); !apierrors.IsNotFound(err) {
+		t.Errorf("the running job should be deleted, err = %v", err)
+	}
+}
+
+func Test_jobImpl_newJob(t *testing.T) {
+	var (
+		name      = "foo"
+		namespace = "kanary"
+	)
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	var objects []runtime.Object
+	for podName, revision := range map[string]string{name + "-0": "v1", name + "-1": "v2", name + "-2": "v2"} {
+		p := test.PodGen(podName, namespace, map[string]string{"app": name, appsv1.ControllerRevisionHashLabelKey: revision}, nil, true, true)
+		p.Status.PodIP = "10.0.0." + podName[len(podName)-1:]
+		objects = append(objects, p)
+	}
+	kclient := fake.NewFakeClient(objects...)
+	j := &jobImpl{
+		index: 0,
+		validationSpec: kanaryv1alpha1.KanaryStatefulsetSpecValidationJob{
+			Template: batchv1beta1.JobTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"team": "qa"}},
+				Spec: batchv1.JobSpec{
+					Template: corev1.PodTemplateSpec{
+						Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "tests", Env: []corev1.EnvVar{{Name: "FOO", Value: "bar"}}}}},
+					},
+				},
+			},
+		},
+	}
+	kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, name, 3, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+	kd.Spec.ServiceName = name
+
+	job, err := j.newJob(kclient, kd, sts)
+	if err != nil {
+		t.Fatalf("jobImpl.newJob() error = %v", err)
+	}
+	if job.Name != "foo-validation-0" || job.Namespace != namespace {
+		t.Errorf("jobImpl.newJob() name = %s/%s", job.Namespace, job.Name)
+	}
+	wantLabels := map[string]string{"team": "qa", kanaryv1alpha1.KanaryStatefulsetKanaryNameLabelKey: name}
+	if !reflect.DeepEqual(job.Labels, wantLabels) {
+		t.Errorf("jobImpl.newJob() labels = %v, want %v", job.Labels, wantLabels)
+	}
+	if len(job.OwnerReferences) != 1 || job.OwnerReferences[0].Name != name || job.OwnerReferences[0].Kind != "KanaryStatefulset" {
+		t.Errorf("jobImpl.newJob() ownerReferences = %v", job.OwnerReferences)
+	}
+	if job.Spec.Template.Spec.RestartPolicy != corev1.RestartPolicyNever {
+		t.Errorf("jobImpl.newJob() restartPolicy = %v", job.Spec.Template.Spec.RestartPolicy)
+	}
+	wantEnv := []corev1.EnvVar{
+		{Name: JobKanaryServiceEnvVar, Value: "foo-kanary-foo.kanary.svc"},
+		{Name: JobKanaryPodIPsEnvVar, Value: "10.0.0.1,10.0.0.2"},
+		{Name: "FOO", Value: "bar"},
+	}
+	if !reflect.DeepEqual(job.Spec.Template.Spec.Containers[0].Env, wantEnv) {
+		t.Errorf("jobImpl.newJob() env = %v, want %v", job.Spec.Template.Spec.Containers[0].Env, wantEnv)
+	}
+	if len(j.validationSpec.Template.Spec.Template.Spec.Containers[0].Env) != 1 {
+		t.Errorf("jobImpl.newJob() the template has been modified")
+	}
+}
@@ -44,15 +44,50 @@ func (l *labelWatchImpl) Validation(kclient client.Client, reqLogger logr.Logger
 		}
 		if selector.Matches(labels.Set(canaryDep.Labels)) {
 			isSucceed = false
+			setValue(result, canaryDep.Name, selector.String())
 		}
 	}
 
-	// watch pods label
-	if l.config.PodInvalidationLabels != nil {
-		var selector labels.Selector
-		selector, err = metav1.LabelSelectorAsSelector(l.config.PodInvalidationLabels)
-		if err != nil {
-			return result, fmt.Errorf("unable to create the label selector from PodInvalidationLabels: %v", err)
+	// watch the StatefulSet labels and annotations
+	if sts != nil {
+		if l.config.StatefulSetInvalidationLabels != nil {
+			var selector labels.Selector
+			selector, err = metav1.LabelSelectorAsSelector(l.config.StatefulSetInvalidationLabels)
+			if err != nil {
+				return result, fmt.Errorf("unable to create the label selector from StatefulSetInvalidationLabels: %v", err)
+			}
+			if selector.Matches(labels.Set(sts.Labels)) {
+				isSucceed = false
+				setValue(result, sts.Name, selector.String())
+			}
+		}
+		if l.config.StatefulSetInvalidationAnnotations != nil {
+			var selector labels.Selector
+			selector, err = metav1.LabelSelectorAsSelector(l.config.StatefulSetInvalidationAnnotations)
+			if err != nil {
+				return result, fmt.Errorf("unable to create the selector from StatefulSetInvalidationAnnotations: %v", err)
+			}
+			if selector.Matches(labels.Set(sts.Annotations)) {
+				isSucceed = false
+				setValue(result, sts.Name, selector.String())
+			}
+		}
+	}
+
+	// watch pods labels and annotations
+	if l.config.PodInvalidationLabels != nil || l.config.PodInvalidationAnnotations != nil {
+		var labelSelector, annotationSelector labels.Selector
+		if l.config.PodInvalidationLabels != nil {
+			labelSelector, err = metav1.LabelSelectorAsSelector(l.config.PodInvalidationLabels)
+			if err != nil {
+				return result, fmt.Errorf("unable to create the label selector from PodInvalidationLabels: %v", err)
+			}
+		}
+		if l.config.PodInvalidationAnnotations != nil {
+			annotationSelector, err = metav1.LabelSelectorAsSelector(l.config.PodInvalidationAnnotations)
+			if err != nil {
+				return result, fmt.Errorf("unable to create the selector from PodInvalidationAnnotations: %v", err)
+			}
 		}
 		var pods []corev1.Pod
 		pods, err = getPods(kclient, reqLogger, kd.Name, kd.Namespace)
@@ -60,9 +95,13 @@ func (l *labelWatchImpl) Validation(kclient client.Client, reqLogger logr.Logger
 			return result, fmt.Errorf("unable to list pods: %v", err)
 		}
 		for _, pod := range pods {
-			if selector.Matches(labels.Set(pod.Labels)) {
+			if labelSelector != nil && labelSelector.Matches(labels.Set(pod.Labels)) {
 				isSucceed = false
-				break
+				setValue(result, pod.Name, labelSelector.String())
+			}
+			if annotationSelector != nil && annotationSelector.Matches(labels.Set(pod.Annotations)) {
+				isSucceed = false
+				setValue(result, pod.Name, annotationSelector.String())
 			}
 		}
 	}
@@ -74,3 +113,11 @@ func (l *labelWatchImpl) Validation(kclient client.Client, reqLogger logr.Logger
 
 	return result, err
 }
+
+// setValue reports the invalidation labels found on an object
+func setValue(result *Result, name, value string) {
+	if result.Values == nil {
+		result.Values = map[string]string{}
+	}
+	result.Values[name] = value
+}
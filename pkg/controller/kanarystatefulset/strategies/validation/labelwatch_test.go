@@ -9,6 +9,8 @@ import (
 	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
 	utilstest "github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/test"
 
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -56,6 +58,10 @@ func Test_labelWatchImpl_Validation(t *testing.T) {
 		}
 
 		mapFailed = map[string]string{"failed": "true"}
+
+		failedSts = &kruisev1alpha1.StatefulSet{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: mapFailed, Annotations: mapFailed},
+		}
 	)
 
 	type fields struct {
@@ -67,6 +73,7 @@ func Test_labelWatchImpl_Validation(t *testing.T) {
 		kd        *kanaryv1alpha1.KanaryStatefulset
 		dep       *appsv1beta1.Deployment
 		canaryDep *appsv1beta1.Deployment
+		sts       *kruisev1alpha1.StatefulSet
 	}
 	tests := []struct {
 		name    string
@@ -187,6 +194,7 @@ func Test_labelWatchImpl_Validation(t *testing.T) {
 			want: &Result{
 				IsFailed: true,
 				Comment:  "labelWatch has detected invalidation labels",
+				Values:   map[string]string{name + "-kanary": "failed=true"},
 			},
 			wantErr: false,
 		},
@@ -207,6 +215,92 @@ func Test_labelWatchImpl_Validation(t *testing.T) {
 			want: &Result{
 				IsFailed: true,
 				Comment:  "labelWatch has detected invalidation labels",
+				Values:   map[string]string{name: "failed=true"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "StatefulSet selector: label failed present",
+			fields: fields{
+				dryRun: false,
+				config: &kanaryv1alpha1.KanaryStatefulsetSpecValidationLabelWatch{
+					StatefulSetInvalidationLabels: &metav1.LabelSelector{MatchLabels: mapFailed},
+				},
+			},
+			args: args{
+				kclient:   fake.NewFakeClient(),
+				kd:        kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{StartTime: &metav1.Time{Time: now}, Validations: validatedLabelWatchPodSpec}),
+				dep:       &appsv1beta1.Deployment{},
+				canaryDep: &appsv1beta1.Deployment{},
+				sts:       failedSts,
+			},
+			want: &Result{
+				IsFailed: true,
+				Comment:  "labelWatch has detected invalidation labels",
+				Values:   map[string]string{name: "failed=true"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "StatefulSet selector: annotation failed present",
+			fields: fields{
+				dryRun: false,
+				config: &kanaryv1alpha1.KanaryStatefulsetSpecValidationLabelWatch{
+					StatefulSetInvalidationAnnotations: &metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "failed", Operator: metav1.LabelSelectorOpExists}}},
+				},
+			},
+			args: args{
+				kclient:   fake.NewFakeClient(),
+				kd:        kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{StartTime: &metav1.Time{Time: now}, Validations: validatedLabelWatchPodSpec}),
+				dep:       &appsv1beta1.Deployment{},
+				canaryDep: &appsv1beta1.Deployment{},
+				sts:       failedSts,
+			},
+			want: &Result{
+				IsFailed: true,
+				Comment:  "labelWatch has detected invalidation labels",
+				Values:   map[string]string{name: "failed"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "StatefulSet selector: label not present",
+			fields: fields{
+				dryRun: false,
+				config: &kanaryv1alpha1.KanaryStatefulsetSpecValidationLabelWatch{
+					StatefulSetInvalidationLabels:      &metav1.LabelSelector{MatchLabels: map[string]string{"failed": "false"}},
+					StatefulSetInvalidationAnnotations: &metav1.LabelSelector{MatchLabels: map[string]string{"kanary": "fail"}},
+				},
+			},
+			args: args{
+				kclient:   fake.NewFakeClient(),
+				kd:        kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{StartTime: &metav1.Time{Time: now}, Validations: validatedLabelWatchPodSpec}),
+				dep:       &appsv1beta1.Deployment{},
+				canaryDep: &appsv1beta1.Deployment{},
+				sts:       failedSts,
+			},
+			want:    &Result{},
+			wantErr: false,
+		},
+		{
+			name: "Pod selector: annotation failed present",
+			fields: fields{
+				dryRun: false,
+				config: &kanaryv1alpha1.KanaryStatefulsetSpecValidationLabelWatch{
+					PodInvalidationAnnotations: &metav1.LabelSelector{MatchLabels: mapFailed},
+				},
+			},
+			args: args{
+				kclient:   fake.NewFakeClient([]runtime.Object{utilstest.NewPod(name+"-0", namespace, "hash", &utilstest.NewPodOptions{Annotations: mapFailed})}...),
+				kd:        kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{StartTime: &metav1.Time{Time: now}, Validations: validatedLabelWatchPodSpec}),
+				dep:       &appsv1beta1.Deployment{},
+				canaryDep: &appsv1beta1.Deployment{},
+				sts:       &kruisev1alpha1.StatefulSet{},
+			},
+			want: &Result{
+				IsFailed: true,
+				Comment:  "labelWatch has detected invalidation labels",
+				Values:   map[string]string{name + "-0": "failed=true"},
 			},
 			wantErr: false,
 		},
@@ -218,7 +312,7 @@ func Test_labelWatchImpl_Validation(t *testing.T) {
 				dryRun: tt.fields.dryRun,
 				config: tt.fields.config,
 			}
-			got, err := l.Validation(tt.args.kclient, reqLogger, tt.args.kd, tt.args.dep, tt.args.canaryDep, nil)
+			got, err := l.Validation(tt.args.kclient, reqLogger, tt.args.kd, tt.args.dep, tt.args.canaryDep, tt.args.sts)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("labelWatchImpl.Validation() error = %v, wantErr %v", err, tt.wantErr)
 				return
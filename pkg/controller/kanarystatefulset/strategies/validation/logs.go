@@ -0,0 +1,306 @@
+package validation
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/pod"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+const (
+	// logsMaxQuotedLines number of matching lines quoted in the failure comment, per pod
+	logsMaxQuotedLines = 3
+	// logsMaxQuotedLineLength lines quoted in the failure comment are truncated to this length
+	logsMaxQuotedLineLength = 200
+	// logsLimitBytes maximum size of the logs read for a pod at each check
+	logsLimitBytes = 10 * 1024 * 1024
+	// logsMaxLineLength longer lines are truncated to this length before being matched
+	logsMaxLineLength = 1024 * 1024
+)
+
+// podLogsReader reads the logs of a pod container
+type podLogsReader interface {
+	ReadLogs(namespace, podName, container string, since time.Time) (io.ReadCloser, error)
+}
+
+// NewLogs returns new validation.Logs instance
+func NewLogs(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation, index int) Interface {
+	return &logsImpl{
+		validationSpec:   *s.Logs,
+		index:            index,
+		validationPeriod: list.ValidationPeriod.Duration,
+		dryRun:           list.NoUpdate,
+	}
+}
+
+type logsImpl struct {
+	validationSpec   kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs
+	index            int
+	validationPeriod time.Duration
+	dryRun           bool
+
+	logsReader podLogsReader //for test purposes
+	now        time.Time     //for test purposes
+}
+
+// podLogsMatches matching lines found in the logs of a pod
+type podLogsMatches struct {
+	count  int
+	quoted []string
+}
+
+func (l *logsImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{Threshold: l.getThreshold()}
+	if sts == nil || sts.Spec.Selector == nil {
+		return result, nil
+	}
+
+	patterns, err := l.compilePatterns()
+	if err != nil {
+		return result, err
+	}
+	if l.logsReader == nil {
+		coreClient, err := getCoreClient()
+		if err != nil {
+			return result, err
+		}
+		l.logsReader = &apiPodLogsReader{coreClient: coreClient}
+	}
+	now := l.now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	since := GetLastCheckTime(kd, l.index)
+	minutes := now.Sub(since).Minutes()
+	if minutes <= 0 {
+		return result, nil
+	}
+
+	pods, err := l.listReadyPods(kclient, kd.Namespace, sts)
+	if err != nil {
+		return result, err
+	}
+
+	var stableRate float64
+	stableCount := 0
+	canaryMatches := map[string]*podLogsMatches{}
+	for _, p := range pods {
+		matches, err := l.countMatches(p, since, patterns)
+		if err != nil {
+			return result, err
+		}
+		if isCanaryPod(p, sts) {
+			canaryMatches[p.Name] = matches
+		} else {
+			stableRate += float64(matches.count) / minutes
+			stableCount++
+		}
+	}
+	if stableCount > 0 {
+		stableRate = stableRate / float64(stableCount)
+	}
+
+	result.Values = map[string]string{}
+	if l.validationSpec.MaxIncreasePercent != nil && stableCount > 0 {
+		result.Values["stable"] = formatRate(stableRate)
+	}
+	var comments []string
+	podNames := []string{}
+	for podName := range canaryMatches {
+		podNames = append(podNames, podName)
+	}
+	sort.Strings(podNames)
+	for _, podName := range podNames {
+		matches := canaryMatches[podName]
+		rate := float64(matches.count) / minutes
+		result.Values[podName] = formatRate(rate)
+		if !l.isRateOutOfBounds(rate, stableRate, stableCount > 0) {
+			continue
+		}
+		comments = append(comments, fmt.Sprintf("%s: %d matching lines (%s/min): %s", podName, matches.count, formatRate(rate), strings.Join(matches.quoted, ", ")))
+	}
+
+	if len(comments) > 0 {
+		result.IsFailed = true
+		result.Comment = "logs of kanary pods contain too many matching lines, " + strings.Join(comments, "; ")
+		reqLogger.Info("Logs validation", "detection", len(comments))
+	}
+	result.Score = getPodsScore(result.Values, len(comments))
+	return result, nil
+}
+
+// isRateOutOfBounds the comparison with the stable rate is only done if at least one stable pod is ready
+func (l *logsImpl) isRateOutOfBounds(rate, stableRate float64, hasStable bool) bool {
+	if l.validationSpec.MaxLinesPerMinute != nil && rate > *l.validationSpec.MaxLinesPerMinute {
+		return true
+	}
+	if l.validationSpec.MaxIncreasePercent != nil && hasStable && rate > stableRate*(1+*l.validationSpec.MaxIncreasePercent/100) {
+		return true
+	}
+	return false
+}
+
+func (l *logsImpl) compilePatterns() ([]*regexp.Regexp, error) {
+	patterns := []*regexp.Regexp{}
+	for _, pattern := range l.validationSpec.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid logs pattern %q: %v", pattern, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+func (l *logsImpl) listReadyPods(kclient client.Client, namespace string, sts *kruisev1alpha1.StatefulSet) ([]*corev1.Pod, error) {
+	selector := labels.SelectorFromSet(sts.Spec.Selector.MatchLabels)
+	list := &corev1.PodList{}
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: namespace, LabelSelector: selector}, list); err != nil {
+		return nil, fmt.Errorf("unable to list the statefulset pods: %v", err)
+	}
+	pods := []*corev1.Pod{}
+	for i := range list.Items {
+		if selector.Matches(labels.Set(list.Items[i].Labels)) {
+			pods = append(pods, &list.Items[i])
+		}
+	}
+	return pod.PurgeNotReadyPods(pods)
+}
+
+// countMatches counts the lines of the pod logs that match one of the patterns, the first ones are quoted
+func (l *logsImpl) countMatches(p *corev1.Pod, since time.Time, patterns []*regexp.Regexp) (*podLogsMatches, error) {
+	container := l.validationSpec.Container
+	if container == "" && len(p.Spec.Containers) > 0 {
+		container = p.Spec.Containers[0].Name
+	}
+	logs, err := l.logsReader.ReadLogs(p.Namespace, p.Name, container, since)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read the logs of the pod %s: %v", p.Name, err)
+	}
+	defer logs.Close()
+
+	matches := &podLogsMatches{}
+	reader := bufio.NewReaderSize(logs, 64*1024)
+	for {
+		line, err := readLogLine(reader)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the logs of the pod %s: %v", p.Name, err)
+		}
+		for _, re := range patterns {
+			if !re.MatchString(line) {
+				continue
+			}
+			matches.count++
+			if len(matches.quoted) < logsMaxQuotedLines {
+				matches.quoted = append(matches.quoted, strconv.Quote(truncateLine(line)))
+			}
+			break
+		}
+	}
+	return matches, nil
+}
+
+// readLogLine returns the next line of the logs, without the end of line. A line longer than logsMaxLineLength
+// is truncated, a single oversized line should not fail the check.
+func readLogLine(reader *bufio.Reader) (string, error) {
+	var line []byte
+	for {
+		fragment, isPrefix, err := reader.ReadLine()
+		if err != nil {
+			if err == io.EOF && len(line) > 0 {
+				return string(line), nil
+			}
+			return "", err
+		}
+		if remaining := logsMaxLineLength - len(line); remaining > 0 {
+			if len(fragment) > remaining {
+				fragment = fragment[:remaining]
+			}
+			line = append(line, fragment...)
+		}
+		if !isPrefix {
+			return string(line), nil
+		}
+	}
+}
+
+// getThreshold returns a description of the bounds applied on the rate of matching lines
+func (l *logsImpl) getThreshold() string {
+	var threshold []string
+	if l.validationSpec.MaxLinesPerMinute != nil {
+		threshold = append(threshold, fmt.Sprintf("maxLinesPerMinute=%v", *l.validationSpec.MaxLinesPerMinute))
+	}
+	if l.validationSpec.MaxIncreasePercent != nil {
+		threshold = append(threshold, fmt.Sprintf("maxIncreasePercent=%v", *l.validationSpec.MaxIncreasePercent))
+	}
+	return strings.Join(threshold, " ")
+}
+
+func truncateLine(line string) string {
+	if len(line) <= logsMaxQuotedLineLength {
+		return line
+	}
+	return line[:logsMaxQuotedLineLength] + "..."
+}
+
+func formatRate(rate float64) string {
+	return strconv.FormatFloat(rate, 'f', 2, 64)
+}
+
+var _ podLogsReader = &apiPodLogsReader{}
+
+// apiPodLogsReader reads the logs through the pods/log API
+type apiPodLogsReader struct {
+	coreClient corev1client.CoreV1Interface
+}
+
+// ReadLogs implements podLogsReader
+func (r *apiPodLogsReader) ReadLogs(namespace, podName, container string, since time.Time) (io.ReadCloser, error) {
+	limitBytes := int64(logsLimitBytes)
+	return r.coreClient.Pods(namespace).GetLogs(podName, &corev1.PodLogOptions{
+		Container:  container,
+		SinceTime:  &metav1.Time{Time: since},
+		LimitBytes: &limitBytes,
+	}).Stream()
+}
+
+var (
+	coreClient     corev1client.CoreV1Interface
+	coreClientErr  error
+	coreClientOnce sync.Once
+)
+
+// getCoreClient returns a typed client for the pods subresources that are not supported by the controller-runtime client
+func getCoreClient() (corev1client.CoreV1Interface, error) {
+	coreClientOnce.Do(func() {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			coreClientErr = err
+			return
+		}
+		coreClient, coreClientErr = corev1client.NewForConfig(cfg)
+	})
+	return coreClient, coreClientErr
+}
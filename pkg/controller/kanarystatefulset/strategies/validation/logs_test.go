@@ -0,0 +1,170 @@
+package validation
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	test "github.com/k8s-kanary/kanary/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// fakePodLogsReader returns the logs by pod name and records the since parameter
+type fakePodLogsReader struct {
+	logs  map[string]string
+	since time.Time
+}
+
+func (f *fakePodLogsReader) ReadLogs(namespace, podName, container string, since time.Time) (io.ReadCloser, error) {
+	f.since = since
+	logs, ok := f.logs[podName]
+	if !ok {
+		return nil, fmt.Errorf("pod %s not found", podName)
+	}
+	return ioutil.NopCloser(strings.NewReader(logs)), nil
+}
+
+func Test_logsImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_logsImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+	)
+	now := time.Now()
+	lastCheck := now.Add(-2 * time.Minute)
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+	podLabels := func(revision string) map[string]string {
+		return map[string]string{"app": name, appsv1.ControllerRevisionHashLabelKey: revision}
+	}
+	kclient := fake.NewFakeClient([]runtime.Object{
+		test.PodGen(name+"-0", namespace, podLabels("v1"), nil, true, true),
+		test.PodGen(name+"-1", namespace, podLabels("v2"), nil, true, true),
+		test.PodGen(name+"-2", namespace, podLabels("v2"), nil, true, false),
+		test.PodGen("bar-0", namespace, map[string]string{"app": "bar"}, nil, true, true),
+	}...)
+	longLine := "ERROR " + strings.Repeat("x", 300)
+
+	tests := []struct {
+		name      string
+		spec      kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs
+		logs      map[string]string
+		want      *Result
+		wantSince time.Time
+		wantErr   bool
+	}{
+		{
+			name: "below the limit",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR"}, MaxLinesPerMinute: kanaryv1alpha1.NewFloat64(2)},
+			logs: map[string]string{name + "-0": "ERROR a\nERROR b\n", name + "-1": "INFO a\nERROR b\nERROR c\n"},
+			want: &Result{
+				Threshold: "maxLinesPerMinute=2",
+				Values:    map[string]string{name + "-1": "1.00"},
+				Score:     kanaryv1alpha1.NewFloat64(100),
+			},
+			wantSince: lastCheck,
+		},
+		{
+			name: "limit exceeded, lines quoted and truncated",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR", "^panic:"}, MaxLinesPerMinute: kanaryv1alpha1.NewFloat64(1)},
+			logs: map[string]string{name + "-0": "", name + "-1": "panic: boom\nINFO a\n" + longLine + "\nERROR b\nERROR c\n"},
+			want: &Result{
+				IsFailed:  true,
+				Comment:   `logs of kanary pods contain too many matching lines, foo-1: 4 matching lines (2.00/min): "panic: boom", "ERROR ` + strings.Repeat("x", 194) + `...", "ERROR b"`,
+				Threshold: "maxLinesPerMinute=1",
+				Values:    map[string]string{name + "-1": "2.00"},
+				Score:     kanaryv1alpha1.NewFloat64(0),
+			},
+			wantSince: lastCheck,
+		},
+		{
+			name: "line longer than the maximum length",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR"}, MaxLinesPerMinute: kanaryv1alpha1.NewFloat64(2)},
+			logs: map[string]string{name + "-0": "", name + "-1": "ERROR " + strings.Repeat("x", 2*logsMaxLineLength) + "\nINFO a\nERROR b"},
+			want: &Result{
+				Threshold: "maxLinesPerMinute=2",
+				Values:    map[string]string{name + "-1": "1.00"},
+				Score:     kanaryv1alpha1.NewFloat64(100),
+			},
+			wantSince: lastCheck,
+		},
+		{
+			name: "rate greater than the stable pods",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR"}, MaxIncreasePercent: kanaryv1alpha1.NewFloat64(50)},
+			logs: map[string]string{name + "-0": "ERROR a\nERROR b\n", name + "-1": "ERROR a\nERROR b\nERROR c\nERROR d\n"},
+			want: &Result{
+				IsFailed:  true,
+				Comment:   `logs of kanary pods contain too many matching lines, foo-1: 4 matching lines (2.00/min): "ERROR a", "ERROR b", "ERROR c"`,
+				Threshold: "maxIncreasePercent=50",
+				Values:    map[string]string{name + "-1": "2.00", "stable": "1.00"},
+				Score:     kanaryv1alpha1.NewFloat64(0),
+			},
+			wantSince: lastCheck,
+		},
+		{
+			name: "rate similar to the stable pods",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR"}, MaxIncreasePercent: kanaryv1alpha1.NewFloat64(50)},
+			logs: map[string]string{name + "-0": "ERROR a\nERROR b\n", name + "-1": "ERROR a\nERROR b\nERROR c\n"},
+			want: &Result{
+				Threshold: "maxIncreasePercent=50",
+				Values:    map[string]string{name + "-1": "1.50", "stable": "1.00"},
+				Score:     kanaryv1alpha1.NewFloat64(100),
+			},
+			wantSince: lastCheck,
+		},
+		{
+			name:    "logs not available",
+			spec:    kanaryv1alpha1.KanaryStatefulsetSpecValidationLogs{Patterns: []string{"ERROR"}, MaxLinesPerMinute: kanaryv1alpha1.NewFloat64(1)},
+			logs:    map[string]string{},
+			want:    &Result{Threshold: "maxLinesPerMinute=1"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader := &fakePodLogsReader{logs: tt.logs}
+			l := &logsImpl{
+				validationSpec: tt.spec,
+				index:          1,
+				logsReader:     reader,
+				now:            now,
+			}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.CreationTimestamp = metav1.Time{Time: now.Add(-10 * time.Minute)}
+			kd.Status.Validations = []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				{Index: 0, LastCheckTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+				{Index: 1, LastCheckTime: &metav1.Time{Time: lastCheck}},
+			}
+			got, err := l.Validation(kclient, log.WithValues("test:", tt.name), kd, nil, nil, sts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("logsImpl.Validation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("logsImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+			if !tt.wantErr && !reader.since.Equal(tt.wantSince) {
+				t.Errorf("logsImpl.Validation() since = %v, want %v", reader.since, tt.wantSince)
+			}
+		})
+	}
+}
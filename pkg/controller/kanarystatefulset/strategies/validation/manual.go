@@ -1,6 +1,8 @@
 package validation
 
 import (
+	"fmt"
+
 	"github.com/go-logr/logr"
 
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
@@ -11,17 +13,21 @@ import (
 )
 
 // NewManual returns new validation.Manual instance
-func NewManual(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+func NewManual(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation, index int) Interface {
 	return &manualImpl{
+		index:                  index,
 		deadlineStatus:         s.Manual.StatusAfterDealine,
 		validationManualStatus: s.Manual.Status,
+		approvers:              s.Manual.Approvers,
 		dryRun:                 list.NoUpdate,
 	}
 }
 
 type manualImpl struct {
+	index                  int
 	deadlineStatus         kanaryv1alpha1.KanaryStatefulsetSpecValidationManualDeadineStatus
 	validationManualStatus kanaryv1alpha1.KanaryStatefulsetSpecValidationManualStatus
+	approvers              *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers
 	dryRun                 bool
 }
 
@@ -29,16 +35,39 @@ func (m *manualImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd
 	var err error
 	result := &Result{}
 
+	// with an allow-list, only the decisions recorded by the admission webhook are considered: the decision of the
+	// approval annotation first, then manual.status if its change has been recorded.
+	var approval *kanaryv1alpha1.KanaryStatefulsetApproval
+	if m.approvers != nil {
+		if approval = GetApproval(kd); approval == nil {
+			approval = getManualDecision(kd, m.index)
+		}
+		m.validationManualStatus = ""
+		if approval != nil {
+			m.validationManualStatus = approval.Decision
+		}
+	}
+
 	if m.validationManualStatus == kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus {
 		result.ForceSuccessNow = true
 	}
 
+	if m.validationManualStatus != "" {
+		result.Values = map[string]string{"status": string(m.validationManualStatus)}
+		if approval != nil {
+			result.Values["approver"] = approval.Approver
+		}
+	}
+
 	deadlineReached := IsDeadlinePeriodDone(kd)
 
 	if m.validationManualStatus == kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus {
 	} else if m.validationManualStatus == kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus {
 		result.IsFailed = true
 		result.Comment = "manual.status=invalid"
+		if approval != nil {
+			result.Comment = fmt.Sprintf("manual approval: invalid by %s", approval.Approver)
+		}
 	} else if deadlineReached && m.deadlineStatus == kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualDeadineStatus {
 		result.IsFailed = true
 		result.Comment = "deadline activated with 'invalid' status"
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"encoding/json"
 	"reflect"
 	"testing"
 	"time"
@@ -55,11 +56,39 @@ func Test_manualImpl_Validation(t *testing.T) {
 			MaxIntervalPeriod: &metav1.Duration{Duration: 15 * time.Second},
 			ValidationPeriod:  &metav1.Duration{Duration: 30 * time.Second},
 		}
+		approvers = &kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers{Users: []string{"alice"}}
 	)
 
+	key := []byte("webhook-key")
+	ConfigureApprovalKey(key)
+	defer ConfigureApprovalKey(nil)
+
+	newApprovedKanaryStatefulset := func(decision, approvedBy string) *kanaryv1alpha1.KanaryStatefulset {
+		kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{Validations: defaultValidationSpec})
+		kd.Annotations = map[string]string{kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey: decision}
+		if approvedBy != "" {
+			kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey] = approvedBy
+			kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey] = now.UTC().Format(time.RFC3339)
+			kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = SignApprovals(key, kd)
+		}
+		return kd
+	}
+	forgedKanaryStatefulset := newApprovedKanaryStatefulset("valid", "alice")
+	forgedKanaryStatefulset.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey] = "mallory"
+	newManualDecisionKanaryStatefulset := func(signed bool) *kanaryv1alpha1.KanaryStatefulset {
+		kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{Validations: validatedManualSpec})
+		decisions, _ := json.Marshal([]kanaryv1alpha1.KanaryStatefulsetApproval{{Approver: "alice", Decision: "valid", Time: metav1.NewTime(now.Truncate(time.Second)), Item: "items[0]"}})
+		kd.Annotations = map[string]string{kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: string(decisions)}
+		if signed {
+			kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = SignApprovals(key, kd)
+		}
+		return kd
+	}
+
 	type fields struct {
 		deadlineStatus         kanaryv1alpha1.KanaryStatefulsetSpecValidationManualDeadineStatus
 		validationManualStatus kanaryv1alpha1.KanaryStatefulsetSpecValidationManualStatus
+		approvers              *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers
 		dryRun                 bool
 	}
 	type args struct {
@@ -106,6 +135,7 @@ func Test_manualImpl_Validation(t *testing.T) {
 			want: &Result{
 				IsFailed:        false,
 				ForceSuccessNow: true,
+				Values:          map[string]string{"status": "valid"},
 			},
 			wantErr: false,
 		},
@@ -127,6 +157,7 @@ func Test_manualImpl_Validation(t *testing.T) {
 			want: &Result{
 				IsFailed: true,
 				Comment:  "manual.status=invalid",
+				Values:   map[string]string{"status": "invalid"},
 			},
 			wantErr: false,
 		},
@@ -172,6 +203,115 @@ func Test_manualImpl_Validation(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "validation manual invalidated by an approver",
+			fields: fields{
+				deadlineStatus: kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				approvers:      approvers,
+			},
+			args: args{
+				kd:        newApprovedKanaryStatefulset("invalid", "alice"),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want: &Result{
+				IsFailed: true,
+				Comment:  "manual approval: invalid by alice",
+				Values:   map[string]string{"status": "invalid", "approver": "alice"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validation manual validated by an approver",
+			fields: fields{
+				deadlineStatus: kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				approvers:      approvers,
+			},
+			args: args{
+				kd:        newApprovedKanaryStatefulset("valid", "alice"),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want: &Result{
+				ForceSuccessNow: true,
+				Values:          map[string]string{"status": "valid", "approver": "alice"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validation manual approval not recorded by the webhook",
+			fields: fields{
+				deadlineStatus: kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				approvers:      approvers,
+			},
+			args: args{
+				kd:        newApprovedKanaryStatefulset("valid", ""),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want:    &Result{},
+			wantErr: false,
+		},
+		{
+			name: "validation manual approval not signed by the webhook",
+			fields: fields{
+				deadlineStatus: kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				approvers:      approvers,
+			},
+			args: args{
+				kd:        forgedKanaryStatefulset,
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want:    &Result{},
+			wantErr: false,
+		},
+		{
+			name: "validation manual status recorded by the webhook",
+			fields: fields{
+				deadlineStatus:         kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				validationManualStatus: kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus,
+				approvers:              approvers,
+			},
+			args: args{
+				kd:        newManualDecisionKanaryStatefulset(true),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want: &Result{
+				ForceSuccessNow: true,
+				Values:          map[string]string{"status": "valid", "approver": "alice"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "validation manual status not recorded by the webhook",
+			fields: fields{
+				deadlineStatus:         kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+				validationManualStatus: kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus,
+				approvers:              approvers,
+			},
+			args: args{
+				kd:        newManualDecisionKanaryStatefulset(false),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want:    &Result{},
+			wantErr: false,
+		},
+		{
+			name: "validation manual approval annotation without approvers",
+			fields: fields{
+				deadlineStatus: kanaryv1alpha1.NoneKanaryStatefulsetSpecValidationManualDeadineStatus,
+			},
+			args: args{
+				kd:        newApprovedKanaryStatefulset("valid", "alice"),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, nil),
+				canaryDep: utilstest.NewDeployment(name+"-kanary", namespace, 1, nil),
+			},
+			want:    &Result{},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -179,6 +319,7 @@ func Test_manualImpl_Validation(t *testing.T) {
 			m := &manualImpl{
 				deadlineStatus:         tt.fields.deadlineStatus,
 				validationManualStatus: tt.fields.validationManualStatus,
+				approvers:              tt.fields.approvers,
 				dryRun:                 tt.fields.dryRun,
 			}
 			got, err := m.Validation(tt.args.kclient, reqLogger, tt.args.kd, tt.args.dep, tt.args.canaryDep, nil)
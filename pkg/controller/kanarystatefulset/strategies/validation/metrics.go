@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// NewMetrics returns new validation.Metrics instance
+func NewMetrics(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+	return &metricsImpl{
+		validationSpec:   *s.Metrics,
+		validationPeriod: list.ValidationPeriod.Duration,
+		dryRun:           list.NoUpdate,
+	}
+}
+
+type metricsImpl struct {
+	validationSpec   kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics
+	validationPeriod time.Duration
+	dryRun           bool
+
+	anomalydetector        anomalydetector.AnomalyDetector
+	anomalydetectorFactory anomalydetector.Factory //for test purposes
+}
+
+func (m *metricsImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, labelSelector map[string]string, url string) error {
+	if m.validationSpec.HTTP == nil {
+		return fmt.Errorf("metrics provider not defined")
+	}
+	headers, err := getHTTPHeaders(kclient, kd.Namespace, m.validationSpec.HTTP.Headers, m.validationSpec.HTTP.SecretHeaders)
+	if err != nil {
+		return err
+	}
+
+	anomalyDetectorConfig := anomalydetector.FactoryConfig{
+		Config: anomalydetector.Config{
+			Logger: reqLogger,
+			PodLister: &promqlPodLister{
+				kclient:   kclient,
+				Namespace: kd.Namespace,
+			},
+			Selector: labels.SelectorFromSet(labelSelector),
+		},
+		HTTPConfig: &anomalydetector.ConfigHTTPMetricsProvider{
+			URL:          url,
+			Headers:      headers,
+			ItemsPath:    m.validationSpec.HTTP.ItemsPath,
+			PodNamePath:  m.validationSpec.HTTP.PodNamePath,
+			ValuePath:    m.validationSpec.HTTP.ValuePath,
+			LabelPaths:   m.validationSpec.HTTP.LabelPaths,
+			AllPodsQuery: m.validationSpec.HTTP.AllPodsQuery,
+			Aggregator:   string(m.validationSpec.HTTP.Aggregator),
+		},
+	}
+	setAnalyserConfig(&anomalyDetectorConfig, m.validationSpec.ValueInRange, m.validationSpec.DiscreteValueOutOfList, m.validationSpec.ContinuousValueDeviation)
+
+	if m.anomalydetectorFactory == nil {
+		m.anomalydetectorFactory = anomalydetector.New
+	}
+
+	if m.anomalydetector, err = m.anomalydetectorFactory(anomalyDetectorConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (m *metricsImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	var err error
+	result := &Result{}
+
+	var labelSelector map[string]string
+	if sts != nil && sts.Spec.Selector != nil {
+		labelSelector = sts.Spec.Selector.MatchLabels
+	}
+
+	var url string
+	if m.validationSpec.HTTP != nil {
+		templateData, err := NewQueryTemplateData(kclient, kd, sts)
+		if err != nil {
+			return result, err
+		}
+		if url, err = RenderQuery(m.validationSpec.HTTP.URL, templateData); err != nil {
+			return result, fmt.Errorf("metrics http url: %v", err)
+		}
+		result.Query = url
+	}
+
+	//re-init the anomaly detector at each validation in case some settings have changed in the kd
+	if err = m.initAnomalyDetector(kclient, reqLogger, kd, labelSelector, url); err != nil {
+		return result, err
+	}
+	pods, err := m.anomalydetector.GetPodsOutOfBounds()
+	if err != nil {
+		reqLogger.Error(err, "GetPodsOutOfBounds")
+		return result, err
+	}
+
+	result.Threshold = getThreshold(m.validationSpec.ValueInRange, m.validationSpec.DiscreteValueOutOfList, m.validationSpec.ContinuousValueDeviation)
+	setResultValues(result, m.anomalydetector)
+
+	if len(pods) > 0 {
+		result.IsFailed = true
+		result.Comment = "metrics provider reported an issue with one of the kanary pod"
+		reqLogger.Info("GetPodsOutOfBounds", "detection", len(pods))
+	}
+	setResultInconclusive(result, m.anomalydetector)
+
+	return result, nil
+}
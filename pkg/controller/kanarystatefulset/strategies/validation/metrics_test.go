@@ -0,0 +1,128 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+	utilstest "github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/test"
+
+	corev1 "k8s.io/api/core/v1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func Test_metricsImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_metricsImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+	)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "metrics", Namespace: namespace},
+		Data:       map[string][]byte{"token": []byte("secret-token")},
+	}
+
+	tests := []struct {
+		name        string
+		spec        kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics
+		kclient     client.Client
+		pods        []*corev1.Pod
+		want        *Result
+		wantHeaders map[string]string
+		wantErr     bool
+	}{
+		{
+			name: "no detection, url rendered",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics{
+				HTTP:         &kanaryv1alpha1.HTTPMetricsProvider{URL: "http://metrics/api?ns={{.Namespace}}", ItemsPath: "{.items[*]}", PodNamePath: "{.pod}", ValuePath: "{.value}"},
+				ValueInRange: &kanaryv1alpha1.ValueInRange{Min: kanaryv1alpha1.NewFloat64(0), Max: kanaryv1alpha1.NewFloat64(1)},
+			},
+			kclient: fake.NewFakeClient([]runtime.Object{}...),
+			want: &Result{
+				Query:     "http://metrics/api?ns=" + namespace,
+				Threshold: "[0, 1]",
+			},
+		},
+		{
+			name: "detection, secret header",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics{
+				HTTP: &kanaryv1alpha1.HTTPMetricsProvider{
+					URL:           "http://metrics/api",
+					Headers:       map[string]string{"X-Org": "kanary"},
+					SecretHeaders: map[string]corev1.SecretKeySelector{"X-Token": {LocalObjectReference: corev1.LocalObjectReference{Name: "metrics"}, Key: "token"}},
+					ItemsPath:     "{.items[*]}",
+					PodNamePath:   "{.pod}",
+					ValuePath:     "{.value}",
+				},
+				ValueInRange: &kanaryv1alpha1.ValueInRange{Min: kanaryv1alpha1.NewFloat64(0), Max: kanaryv1alpha1.NewFloat64(1)},
+			},
+			kclient: fake.NewFakeClient([]runtime.Object{secret}...),
+			pods:    []*corev1.Pod{utilstest.NewPod(name+"-kanary", namespace, "hash", nil)},
+			want: &Result{
+				IsFailed:  true,
+				Comment:   "metrics provider reported an issue with one of the kanary pod",
+				Query:     "http://metrics/api",
+				Threshold: "[0, 1]",
+			},
+			wantHeaders: map[string]string{"X-Org": "kanary", "X-Token": "secret-token"},
+		},
+		{
+			name: "missing secret",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics{
+				HTTP: &kanaryv1alpha1.HTTPMetricsProvider{
+					URL:           "http://metrics/api",
+					SecretHeaders: map[string]corev1.SecretKeySelector{"X-Token": {LocalObjectReference: corev1.LocalObjectReference{Name: "metrics"}, Key: "token"}},
+				},
+			},
+			kclient: fake.NewFakeClient([]runtime.Object{}...),
+			want:    &Result{Query: "http://metrics/api"},
+			wantErr: true,
+		},
+		{
+			name: "no provider",
+			spec: kanaryv1alpha1.KanaryStatefulsetSpecValidationMetrics{
+				ValueInRange: &kanaryv1alpha1.ValueInRange{Min: kanaryv1alpha1.NewFloat64(0), Max: kanaryv1alpha1.NewFloat64(1)},
+			},
+			kclient: fake.NewFakeClient([]runtime.Object{}...),
+			want:    &Result{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotConfig anomalydetector.FactoryConfig
+			m := &metricsImpl{
+				validationSpec:   tt.spec,
+				validationPeriod: 30 * time.Second,
+				anomalydetectorFactory: func(cfg anomalydetector.FactoryConfig) (anomalydetector.AnomalyDetector, error) {
+					gotConfig = cfg
+					return anomalydetector.FakeFactory(tt.pods, nil)(cfg)
+				},
+			}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			got, err := m.Validation(tt.kclient, log.WithValues("test:", tt.name), kd, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("metricsImpl.Validation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("metricsImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+			if tt.wantHeaders != nil && !reflect.DeepEqual(gotConfig.HTTPConfig.Headers, tt.wantHeaders) {
+				t.Errorf("metricsImpl.Validation() headers = %#v, want %#v", gotConfig.HTTPConfig.Headers, tt.wantHeaders)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package validation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// RecordProviderError records a failing check in the outages of the validation item
+func RecordProviderError(status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, err error, now time.Time) {
+	status.LastCheckTime = &metav1.Time{Time: now}
+	if outage := getOngoingOutage(status); outage != nil {
+		outage.End = metav1.NewTime(now)
+		outage.Error = err.Error()
+		return
+	}
+	status.Outages = append(status.Outages, kanaryv1alpha1.KanaryStatefulsetValidationOutage{
+		Start:   metav1.NewTime(now),
+		End:     metav1.NewTime(now),
+		Ongoing: true,
+		Error:   err.Error(),
+	})
+}
+
+// RecordProviderRecovery ends the ongoing outage of the validation item, if any
+func RecordProviderRecovery(status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, now time.Time) {
+	if outage := getOngoingOutage(status); outage != nil {
+		outage.End = metav1.NewTime(now)
+		outage.Ongoing = false
+	}
+}
+
+// GetOutageFailure returns the failure message if the ongoing outage of the validation item is not tolerated, empty otherwise
+func GetOutageFailure(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation, status *kanaryv1alpha1.KanaryStatefulsetValidationStatus) string {
+	outage := getOngoingOutage(status)
+	if item.OnProviderError == nil || outage == nil {
+		return ""
+	}
+	maxOutage := item.OnProviderError.MaxOutage
+	if maxOutage == nil {
+		if item.OnProviderError.Policy == kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy {
+			return fmt.Sprintf("provider error: %s", outage.Error)
+		}
+		return ""
+	}
+	if d := outage.End.Sub(outage.Start.Time); d > maxOutage.Duration {
+		return fmt.Sprintf("provider error for %s, more than maxOutage %s: %s", d, maxOutage.Duration, outage.Error)
+	}
+	return ""
+}
+
+// IsWaitingForProvider returns true if a validation item (not advisory) with the Fail or Pause policy has an ongoing outage,
+// the validation can't end before the provider recovers or the outage is not tolerated anymore.
+func IsWaitingForProvider(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	for i := range status.Validations {
+		index := status.Validations[i].Index
+		if index >= len(list.Items) || list.Items[index].OnProviderError == nil || IsAdvisory(list, index) {
+			continue
+		}
+		if list.Items[index].OnProviderError.Policy != kanaryv1alpha1.IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy && getOngoingOutage(&status.Validations[i]) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getOutageExtension returns the duration added to the validation period because the validation clock was paused,
+// the overlapping outages of several items are counted once
+func getOutageExtension(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) time.Duration {
+	var outages []kanaryv1alpha1.KanaryStatefulsetValidationOutage
+	for _, v := range status.Validations {
+		if isPauseItem(list, v.Index) {
+			outages = append(outages, v.Outages...)
+		}
+	}
+	sort.Slice(outages, func(i, j int) bool { return outages[i].Start.Before(&outages[j].Start) })
+
+	extension := time.Duration(0)
+	var start, end time.Time
+	for _, o := range outages {
+		if o.Start.Time.After(end) {
+			extension += end.Sub(start)
+			start = o.Start.Time
+		}
+		if o.End.Time.After(end) {
+			end = o.End.Time
+		}
+	}
+	return extension + end.Sub(start)
+}
+
+func isPauseItem(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, index int) bool {
+	if index >= len(list.Items) || list.Items[index].OnProviderError == nil {
+		return false
+	}
+	return list.Items[index].OnProviderError.Policy == kanaryv1alpha1.PauseKanaryStatefulsetSpecValidationProviderErrorPolicy
+}
+
+func getOngoingOutage(status *kanaryv1alpha1.KanaryStatefulsetValidationStatus) *kanaryv1alpha1.KanaryStatefulsetValidationOutage {
+	if n := len(status.Outages); n > 0 && status.Outages[n-1].Ongoing {
+		return &status.Outages[n-1]
+	}
+	return nil
+}
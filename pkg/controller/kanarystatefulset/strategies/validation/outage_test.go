@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestRecordProviderError(t *testing.T) {
+	now := time.Now()
+	status := &kanaryv1alpha1.KanaryStatefulsetValidationStatus{}
+
+	RecordProviderError(status, fmt.Errorf("connection refused"), now)
+	RecordProviderError(status, fmt.Errorf("timeout"), now.Add(time.Minute))
+	if len(status.Outages) != 1 || !status.Outages[0].Ongoing || status.Outages[0].Error != "timeout" || !status.Outages[0].End.Time.Equal(now.Add(time.Minute)) {
+		t.Fatalf("the failing checks should be recorded in the same ongoing outage, outages = %#v", status.Outages)
+	}
+
+	RecordProviderRecovery(status, now.Add(2*time.Minute))
+	if status.Outages[0].Ongoing || !status.Outages[0].End.Time.Equal(now.Add(2*time.Minute)) {
+		t.Errorf("the outage should end at the first successful check, outage = %#v", status.Outages[0])
+	}
+
+	RecordProviderError(status, fmt.Errorf("connection refused"), now.Add(3*time.Minute))
+	if len(status.Outages) != 2 || !status.Outages[1].Ongoing {
+		t.Errorf("a failing check after a recovery should start a new outage, outages = %#v", status.Outages)
+	}
+}
+
+func TestGetOutageFailure(t *testing.T) {
+	now := time.Now()
+	ongoing := &kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+		Outages: []kanaryv1alpha1.KanaryStatefulsetValidationOutage{
+			{Start: metav1.NewTime(now.Add(-10 * time.Minute)), End: metav1.NewTime(now), Ongoing: true, Error: "connection refused"},
+		},
+	}
+	tests := []struct {
+		name   string
+		policy kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderErrorPolicy
+		max    *metav1.Duration
+		status *kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		want   string
+	}{
+		{
+			name:   "fail without maxOutage",
+			policy: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy,
+			status: ongoing,
+			want:   "provider error: connection refused",
+		},
+		{
+			name:   "fail within maxOutage",
+			policy: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy,
+			max:    &metav1.Duration{Duration: 15 * time.Minute},
+			status: ongoing,
+		},
+		{
+			name:   "pause without maxOutage",
+			policy: kanaryv1alpha1.PauseKanaryStatefulsetSpecValidationProviderErrorPolicy,
+			status: ongoing,
+		},
+		{
+			name:   "ignore longer than maxOutage",
+			policy: kanaryv1alpha1.IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy,
+			max:    &metav1.Duration{Duration: 5 * time.Minute},
+			status: ongoing,
+			want:   "provider error for 10m0s, more than maxOutage 5m0s: connection refused",
+		},
+		{
+			name:   "no ongoing outage",
+			policy: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy,
+			status: &kanaryv1alpha1.KanaryStatefulsetValidationStatus{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			item := &kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+				OnProviderError: &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: tt.policy, MaxOutage: tt.max},
+			}
+			if got := GetOutageFailure(item, tt.status); got != tt.want {
+				t.Errorf("GetOutageFailure() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_getOutageExtension(t *testing.T) {
+	now := time.Now()
+	outage := func(start, end time.Duration) kanaryv1alpha1.KanaryStatefulsetValidationOutage {
+		return kanaryv1alpha1.KanaryStatefulsetValidationOutage{Start: metav1.NewTime(now.Add(start)), End: metav1.NewTime(now.Add(end))}
+	}
+	pause := &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.PauseKanaryStatefulsetSpecValidationProviderErrorPolicy}
+	ignore := &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy}
+	list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{OnProviderError: pause}, {OnProviderError: pause}, {OnProviderError: ignore}},
+	}
+	status := &kanaryv1alpha1.KanaryStatefulsetStatus{
+		Validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+			{Index: 0, Outages: []kanaryv1alpha1.KanaryStatefulsetValidationOutage{outage(0, 5*time.Minute), outage(20*time.Minute, 22*time.Minute)}},
+			{Index: 1, Outages: []kanaryv1alpha1.KanaryStatefulsetValidationOutage{outage(3*time.Minute, 8*time.Minute)}},
+			{Index: 2, Outages: []kanaryv1alpha1.KanaryStatefulsetValidationOutage{outage(10*time.Minute, 15*time.Minute)}},
+		},
+	}
+	// the overlapping outages of the paused items are counted once, the ignored item does not pause the validation
+	if got, want := getOutageExtension(list, status), 10*time.Minute; got != want {
+		t.Errorf("getOutageExtension() = %v, want %v", got, want)
+	}
+}
+
+func TestIsWaitingForProvider(t *testing.T) {
+	ongoing := []kanaryv1alpha1.KanaryStatefulsetValidationOutage{{Ongoing: true}}
+	tests := []struct {
+		name   string
+		item   kanaryv1alpha1.KanaryStatefulsetSpecValidation
+		status kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		want   bool
+	}{
+		{
+			name:   "pause",
+			item:   kanaryv1alpha1.KanaryStatefulsetSpecValidation{OnProviderError: &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.PauseKanaryStatefulsetSpecValidationProviderErrorPolicy}},
+			status: kanaryv1alpha1.KanaryStatefulsetValidationStatus{Outages: ongoing},
+			want:   true,
+		},
+		{
+			name:   "ignore",
+			item:   kanaryv1alpha1.KanaryStatefulsetSpecValidation{OnProviderError: &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy}},
+			status: kanaryv1alpha1.KanaryStatefulsetValidationStatus{Outages: ongoing},
+		},
+		{
+			name:   "advisory",
+			item:   kanaryv1alpha1.KanaryStatefulsetSpecValidation{Advisory: true, OnProviderError: &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy}},
+			status: kanaryv1alpha1.KanaryStatefulsetValidationStatus{Outages: ongoing},
+		},
+		{
+			name:   "recovered",
+			item:   kanaryv1alpha1.KanaryStatefulsetSpecValidation{OnProviderError: &kanaryv1alpha1.KanaryStatefulsetSpecValidationProviderError{Policy: kanaryv1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy}},
+			status: kanaryv1alpha1.KanaryStatefulsetValidationStatus{Outages: []kanaryv1alpha1.KanaryStatefulsetValidationOutage{{}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{tt.item}}
+			status := &kanaryv1alpha1.KanaryStatefulsetStatus{Validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{tt.status}}
+			if got := IsWaitingForProvider(list, status); got != tt.want {
+				t.Errorf("IsWaitingForProvider() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
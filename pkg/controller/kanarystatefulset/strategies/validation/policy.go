@@ -0,0 +1,68 @@
+package validation
+
+import (
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// IsAdvisory returns true if the failure of the validation item should only be reported as a warning:
+// the item is flagged as advisory, or a policy is defined and does not reference the item.
+func IsAdvisory(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, index int) bool {
+	item := &list.Items[index]
+	if item.Advisory {
+		return true
+	}
+	if list.Policy == nil {
+		return false
+	}
+	return item.Name == "" || !GetPolicyItemNames(list.Policy)[item.Name]
+}
+
+// GetPolicyItemNames returns the names of the validation items referenced by the policy and its nested groups
+func GetPolicyItemNames(policy *kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy) map[string]bool {
+	names := map[string]bool{}
+	addPolicyItemNames(policy, names)
+	return names
+}
+
+func addPolicyItemNames(policy *kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy, names map[string]bool) {
+	for _, name := range policy.Items {
+		names[name] = true
+	}
+	for i := range policy.Groups {
+		addPolicyItemNames(&policy.Groups[i], names)
+	}
+}
+
+// IsPolicyFailed evaluates the policy with the outcome of the validation items, by name.
+// An item without outcome is considered as passing.
+func IsPolicyFailed(policy *kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy, failedByName map[string]bool) bool {
+	var operands []bool
+	for _, name := range policy.Items {
+		operands = append(operands, failedByName[name])
+	}
+	for i := range policy.Groups {
+		operands = append(operands, IsPolicyFailed(&policy.Groups[i], failedByName))
+	}
+	if len(operands) == 0 {
+		return false
+	}
+
+	failed := 0
+	for _, operand := range operands {
+		if operand {
+			failed++
+		}
+	}
+	switch policy.Operator {
+	case kanaryv1alpha1.AndKanaryStatefulsetSpecValidationPolicyOperator:
+		return failed == len(operands)
+	case kanaryv1alpha1.QuorumKanaryStatefulsetSpecValidationPolicyOperator:
+		quorum := int32(len(operands))
+		if policy.Quorum != nil {
+			quorum = *policy.Quorum
+		}
+		return int32(len(operands)-failed) < quorum
+	default:
+		return failed > 0
+	}
+}
@@ -0,0 +1,93 @@
+package validation
+
+import (
+	"testing"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestIsPolicyFailed(t *testing.T) {
+	and := kanaryv1alpha1.AndKanaryStatefulsetSpecValidationPolicyOperator
+	or := kanaryv1alpha1.OrKanaryStatefulsetSpecValidationPolicyOperator
+	quorum := kanaryv1alpha1.QuorumKanaryStatefulsetSpecValidationPolicyOperator
+
+	tests := []struct {
+		name   string
+		policy kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy
+		failed map[string]bool
+		want   bool
+	}{
+		{
+			name:   "or, one failed",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: or, Items: []string{"a", "b"}},
+			failed: map[string]bool{"b": true},
+			want:   true,
+		},
+		{
+			name:   "and, one failed",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: and, Items: []string{"a", "b"}},
+			failed: map[string]bool{"b": true},
+			want:   false,
+		},
+		{
+			name:   "and, all failed",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: and, Items: []string{"a", "b"}},
+			failed: map[string]bool{"a": true, "b": true},
+			want:   true,
+		},
+		{
+			name:   "quorum reached",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: quorum, Items: []string{"a", "b", "c"}, Quorum: kanaryv1alpha1.NewInt32(2)},
+			failed: map[string]bool{"c": true},
+			want:   false,
+		},
+		{
+			name:   "quorum not reached",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: quorum, Items: []string{"a", "b", "c"}, Quorum: kanaryv1alpha1.NewInt32(2)},
+			failed: map[string]bool{"a": true, "c": true},
+			want:   true,
+		},
+		{
+			name: "nested group failed",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: or, Items: []string{"a"}, Groups: []kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+				{Operator: and, Items: []string{"b", "c"}},
+			}},
+			failed: map[string]bool{"b": true, "c": true},
+			want:   true,
+		},
+		{
+			name:   "empty group",
+			policy: kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{Operator: and},
+			failed: map[string]bool{"a": true},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPolicyFailed(&tt.policy, tt.failed); got != tt.want {
+				t.Errorf("IsPolicyFailed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsAdvisory(t *testing.T) {
+	list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "a"}, {Name: "b", Advisory: true}, {Name: "c"}, {}},
+	}
+	for index, want := range []bool{false, true, false, false} {
+		if got := IsAdvisory(list, index); got != want {
+			t.Errorf("IsAdvisory(%d) without policy = %v, want %v", index, got, want)
+		}
+	}
+
+	list.Policy = &kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+		Operator: kanaryv1alpha1.OrKanaryStatefulsetSpecValidationPolicyOperator,
+		Items:    []string{"a"},
+	}
+	for index, want := range []bool{false, true, true, true} {
+		if got := IsAdvisory(list, index); got != want {
+			t.Errorf("IsAdvisory(%d) with policy = %v, want %v", index, got, want)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+// getPrometheusAuthConfig reads the secrets referenced by the prometheus auth configuration
+func getPrometheusAuthConfig(kclient client.Client, namespace string, auth *kanaryv1alpha1.PrometheusAuth) (*anomalydetector.PrometheusAuthConfig, error) {
+	if auth == nil {
+		return nil, nil
+	}
+	secrets := &secretReader{kclient: kclient, namespace: namespace, cache: map[string]*corev1.Secret{}}
+	config := &anomalydetector.PrometheusAuthConfig{
+		Headers: auth.Headers,
+	}
+
+	var err error
+	if auth.BearerToken != nil {
+		if config.BearerToken, err = secrets.getString(auth.BearerToken); err != nil {
+			return nil, err
+		}
+	}
+	if auth.BasicAuth != nil {
+		if config.Username, err = secrets.getString(&auth.BasicAuth.Username); err != nil {
+			return nil, err
+		}
+		if config.Password, err = secrets.getString(&auth.BasicAuth.Password); err != nil {
+			return nil, err
+		}
+	}
+	if auth.TLS != nil {
+		config.ServerName = auth.TLS.ServerName
+		config.InsecureSkipVerify = auth.TLS.InsecureSkipVerify
+		if config.CA, err = secrets.get(auth.TLS.CA); err != nil {
+			return nil, err
+		}
+		if config.Cert, err = secrets.get(auth.TLS.Cert); err != nil {
+			return nil, err
+		}
+		if config.Key, err = secrets.get(auth.TLS.Key); err != nil {
+			return nil, err
+		}
+	}
+	return config, nil
+}
+
+// getHTTPHeaders returns the headers of an HTTP request, including the ones read from secrets
+func getHTTPHeaders(kclient client.Client, namespace string, values map[string]string, secretValues map[string]corev1.SecretKeySelector) (map[string]string, error) {
+	if len(values) == 0 && len(secretValues) == 0 {
+		return nil, nil
+	}
+	headers := map[string]string{}
+	for name, value := range values {
+		headers[name] = value
+	}
+	secrets := &secretReader{kclient: kclient, namespace: namespace, cache: map[string]*corev1.Secret{}}
+	for name, selector := range secretValues {
+		value, err := secrets.getString(&selector)
+		if err != nil {
+			return nil, err
+		}
+		headers[name] = value
+	}
+	return headers, nil
+}
+
+// secretReader reads secret keys, each secret is read only once
+type secretReader struct {
+	kclient   client.Client
+	namespace string
+	cache     map[string]*corev1.Secret
+}
+
+func (r *secretReader) get(selector *corev1.SecretKeySelector) ([]byte, error) {
+	if selector == nil {
+		return nil, nil
+	}
+	secret, ok := r.cache[selector.Name]
+	if !ok {
+		secret = &corev1.Secret{}
+		if err := r.kclient.Get(context.TODO(), client.ObjectKey{Name: selector.Name, Namespace: r.namespace}, secret); err != nil {
+			return nil, fmt.Errorf("unable to get the secret %s/%s: %v", r.namespace, selector.Name, err)
+		}
+		r.cache[selector.Name] = secret
+	}
+	value, ok := secret.Data[selector.Key]
+	if !ok {
+		return nil, fmt.Errorf("key %s not found in the secret %s/%s", selector.Key, r.namespace, selector.Name)
+	}
+	return value, nil
+}
+
+func (r *secretReader) getString(selector *corev1.SecretKeySelector) (string, error) {
+	value, err := r.get(selector)
+	return string(value), err
+}
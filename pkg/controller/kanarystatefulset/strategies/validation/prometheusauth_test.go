@@ -0,0 +1,93 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func Test_getPrometheusAuthConfig(t *testing.T) {
+	namespace := "kanary"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "prometheus", Namespace: namespace},
+		Data: map[string][]byte{
+			"token":    []byte("token"),
+			"username": []byte("user"),
+			"password": []byte("pass"),
+			"ca.crt":   []byte("ca"),
+		},
+	}
+	selector := func(key string) *corev1.SecretKeySelector {
+		return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prometheus"}, Key: key}
+	}
+
+	tests := []struct {
+		name    string
+		auth    *kanaryv1alpha1.PrometheusAuth
+		want    *anomalydetector.PrometheusAuthConfig
+		wantErr bool
+	}{
+		{
+			name: "no auth",
+			auth: nil,
+			want: nil,
+		},
+		{
+			name: "bearer token and headers",
+			auth: &kanaryv1alpha1.PrometheusAuth{
+				BearerToken: selector("token"),
+				Headers:     map[string]string{"X-Scope-OrgID": "tenant"},
+			},
+			want: &anomalydetector.PrometheusAuthConfig{
+				BearerToken: "token",
+				Headers:     map[string]string{"X-Scope-OrgID": "tenant"},
+			},
+		},
+		{
+			name: "basic auth and tls",
+			auth: &kanaryv1alpha1.PrometheusAuth{
+				BasicAuth: &kanaryv1alpha1.PrometheusBasicAuth{Username: *selector("username"), Password: *selector("password")},
+				TLS:       &kanaryv1alpha1.PrometheusTLSConfig{CA: selector("ca.crt"), ServerName: "prometheus"},
+			},
+			want: &anomalydetector.PrometheusAuthConfig{
+				Username:   "user",
+				Password:   "pass",
+				CA:         []byte("ca"),
+				ServerName: "prometheus",
+			},
+		},
+		{
+			name:    "missing key",
+			auth:    &kanaryv1alpha1.PrometheusAuth{BearerToken: selector("missing")},
+			wantErr: true,
+		},
+		{
+			name: "missing secret",
+			auth: &kanaryv1alpha1.PrometheusAuth{
+				BearerToken: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "token"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kclient := fake.NewFakeClient([]runtime.Object{secret}...)
+			got, err := getPrometheusAuthConfig(kclient, namespace, tt.auth)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("getPrometheusAuthConfig() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("getPrometheusAuthConfig() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
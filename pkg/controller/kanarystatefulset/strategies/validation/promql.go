@@ -2,6 +2,9 @@ package validation
 
 import (
 	"context"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	"github.com/go-logr/logr"
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
@@ -15,24 +18,26 @@ import (
 )
 
 // NewPromql returns new validation.Manual instance
-func NewPromql(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+func NewPromql(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation, index int) Interface {
 
 	return &promqlImpl{
-		validationSpec:    *s.PromQL,
-		validationPeriod:  list.ValidationPeriod.Duration,
-		maxIntervalPeriod: list.MaxIntervalPeriod.Duration,
-		dryRun:            list.NoUpdate,
+		validationSpec:   *s.PromQL,
+		index:            index,
+		validationPeriod: list.ValidationPeriod.Duration,
+		dryRun:           list.NoUpdate,
 	}
 }
 
 type promqlImpl struct {
-	validationSpec    kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL
-	validationPeriod  time.Duration
-	maxIntervalPeriod time.Duration
-	dryRun            bool
+	validationSpec   kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL
+	index            int
+	validationPeriod time.Duration
+	dryRun           bool
 
 	anomalydetector        anomalydetector.AnomalyDetector
 	anomalydetectorFactory anomalydetector.Factory //for test purposes
+	// requestsProviderFactory builds the provider of the exclusions requests query, for test purposes
+	requestsProviderFactory func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)
 }
 
 type promqlPodLister struct {
@@ -62,7 +67,12 @@ func (pl *promqlPodLister) Get(name string) (*corev1.Pod, error) {
 	return pod, nil
 }
 
-func (p *promqlImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, labelSelector map[string]string) error {
+func (p *promqlImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet, labelSelector map[string]string, query string, exclusions *podExclusions) error {
+	auth, err := getPrometheusAuthConfig(kclient, kd.Namespace, p.validationSpec.Auth)
+	if err != nil {
+		return err
+	}
+
 	//config is kind of cloned but that allow decoupling between the CRD definition and the anomalydetector package
 	anomalyDetectorConfig := anomalydetector.FactoryConfig{
 		Config: anomalydetector.Config{
@@ -77,25 +87,37 @@ func (p *promqlImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.L
 			PrometheusService: p.validationSpec.PrometheusService,
 			PodNameKey:        p.validationSpec.PodNameKey,
 			AllPodsQuery:      p.validationSpec.AllPodsQuery,
-			Query:             p.validationSpec.Query,
+			Query:             query,
+			URL:               p.validationSpec.URL,
+			Auth:              auth,
 		},
 	}
 
-	if p.validationSpec.ContinuousValueDeviation != nil {
-		anomalyDetectorConfig.ContinuousValueDeviationConfig = &anomalydetector.ContinuousValueDeviationConfig{
-			MaxDeviationPercent: *p.validationSpec.ContinuousValueDeviation.MaxDeviationPercent,
+	if exclusions != nil {
+		anomalyDetectorConfig.ExclusionFunc = exclusions.isExcluded
+	}
+
+	if p.validationSpec.RangeQuery != nil {
+		anomalyDetectorConfig.PromConfig.Range = &anomalydetector.RangeQueryConfig{
+			Start:      p.getRangeQueryStart(kd),
+			Step:       p.validationSpec.RangeQuery.Step.Duration,
+			Aggregator: string(p.validationSpec.RangeQuery.Aggregator),
+		}
+	}
+
+	setAnalyserConfig(&anomalyDetectorConfig, p.validationSpec.ValueInRange, p.validationSpec.DiscreteValueOutOfList, p.validationSpec.ContinuousValueDeviation)
+	if p.validationSpec.Trend != nil {
+		anomalyDetectorConfig.TrendConfig = &anomalydetector.TrendConfig{
+			Limit:                    p.validationSpec.Trend.Limit,
+			MaxSlopeDeviationPercent: p.validationSpec.Trend.MaxSlopeDeviationPercent,
 		}
-	} else if p.validationSpec.ValueInRange != nil {
-		anomalyDetectorConfig.ValueInRangeConfig = &anomalydetector.ValueInRangeConfig{
-			Min: *p.validationSpec.ValueInRange.Min,
-			Max: *p.validationSpec.ValueInRange.Max,
+		if p.validationSpec.Trend.Horizon != nil {
+			anomalyDetectorConfig.TrendConfig.Horizon = p.validationSpec.Trend.Horizon.Duration
 		}
-	} else if p.validationSpec.DiscreteValueOutOfList != nil {
-		anomalyDetectorConfig.DiscreteValueOutOfListConfig = &anomalydetector.DiscreteValueOutOfListConfig{
-			BadValues:        p.validationSpec.DiscreteValueOutOfList.BadValues,
-			GoodValues:       p.validationSpec.DiscreteValueOutOfList.GoodValues,
-			Key:              p.validationSpec.DiscreteValueOutOfList.Key,
-			TolerancePercent: *p.validationSpec.DiscreteValueOutOfList.TolerancePercent,
+		if sts != nil {
+			anomalyDetectorConfig.TrendConfig.IsCanary = func(pod *corev1.Pod) bool {
+				return isCanaryPod(pod, sts)
+			}
 		}
 	}
 
@@ -103,19 +125,83 @@ func (p *promqlImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.L
 		p.anomalydetectorFactory = anomalydetector.New
 	}
 
-	var err error
 	if p.anomalydetector, err = p.anomalydetectorFactory(anomalyDetectorConfig); err != nil {
 		return err
 	}
 	return nil
 }
 
+// getPodExclusions returns the exclusion rules of the pods, nil if no exclusion is configured.
+// The companion requests query is run before the anomaly detection.
+func (p *promqlImpl) getPodExclusions(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, templateData *QueryTemplateData) (*podExclusions, error) {
+	exclusions := p.validationSpec.Exclusions
+	if exclusions == nil {
+		return nil, nil
+	}
+
+	var requests map[string]float64
+	if exclusions.MinRequests != nil && exclusions.RequestsQuery != "" {
+		query, err := RenderQuery(exclusions.RequestsQuery, templateData)
+		if err != nil {
+			return nil, fmt.Errorf("exclusions requests query: %v", err)
+		}
+		auth, err := getPrometheusAuthConfig(kclient, kd.Namespace, p.validationSpec.Auth)
+		if err != nil {
+			return nil, err
+		}
+		if p.requestsProviderFactory == nil {
+			p.requestsProviderFactory = anomalydetector.NewPrometheusMetricsProvider
+		}
+		provider, err := p.requestsProviderFactory(anomalydetector.ConfigPrometheusAnomalyDetector{
+			PrometheusService: p.validationSpec.PrometheusService,
+			PodNameKey:        p.validationSpec.PodNameKey,
+			Query:             query,
+			URL:               p.validationSpec.URL,
+			Auth:              auth,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if requests, err = getRequestsByPod(provider); err != nil {
+			return nil, err
+		}
+	}
+	return newPodExclusions(*exclusions, requests, time.Now())
+}
+
+// getRangeQueryStart returns the beginning of the time range evaluated by the range query.
+// With the lastCheck window, it is the previous check of the validation item, never before the validation start.
+func (p *promqlImpl) getRangeQueryStart(kd *kanaryv1alpha1.KanaryStatefulset) time.Time {
+	if p.validationSpec.RangeQuery.Window != kanaryv1alpha1.LastCheckPromQLRangeQueryWindow {
+		return GetValidationStart(kd)
+	}
+	return GetLastCheckTime(kd, p.index)
+}
+
 func (p *promqlImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
 	var err error
 	result := &Result{}
 
+	var labelSelector map[string]string
+	if sts != nil && sts.Spec.Selector != nil {
+		labelSelector = sts.Spec.Selector.MatchLabels
+	}
+
+	templateData, err := NewQueryTemplateData(kclient, kd, sts)
+	if err != nil {
+		return result, err
+	}
+	if result.Query, err = RenderQuery(p.validationSpec.Query, templateData); err != nil {
+		return result, fmt.Errorf("promQL query: %v", err)
+	}
+
+	exclusions, err := p.getPodExclusions(kclient, kd, templateData)
+	if err != nil {
+		return result, err
+	}
+
 	//re-init the anomaly detector at each validation in case some settings have changed in the kd
-	if err = p.initAnomalyDetector(kclient, reqLogger, kd, sts.Spec.Selector.MatchLabels); err != nil {
+	if err = p.initAnomalyDetector(kclient, reqLogger, kd, sts, labelSelector, result.Query, exclusions); err != nil {
 		return result, err
 	}
 	// By default a Deployement is valid until a Label is discovered on pod or deployment.
@@ -125,6 +211,15 @@ func (p *promqlImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd
 		return result, err
 	}
 
+	result.Threshold = getThreshold(p.validationSpec.ValueInRange, p.validationSpec.DiscreteValueOutOfList, p.validationSpec.ContinuousValueDeviation)
+	if p.validationSpec.Trend != nil {
+		result.Threshold = getTrendThreshold(p.validationSpec.Trend)
+	}
+	setResultValues(result, p.anomalydetector)
+	if exclusions != nil && len(exclusions.excluded) > 0 {
+		result.ExcludedPods = exclusions.excluded
+	}
+
 	//Check if at least one kanary pod was detected by anomaly detector
 	if len(pods) > 0 {
 		result.IsFailed = true
@@ -134,6 +229,96 @@ func (p *promqlImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd
 	if result.IsFailed {
 		result.Comment = "promQL query reported an issue with one of the kanary pod"
 	}
+	result.Score = getPodsScore(result.Values, len(pods))
+
+	if len(p.validationSpec.Dependencies) > 0 {
+		if err = p.checkDependencies(kclient, reqLogger, kd, templateData, result); err != nil {
+			return result, err
+		}
+	}
+	setResultInconclusive(result, p.anomalydetector)
 
 	return result, err
 }
+
+// setAnalyserConfig configures the analyser applied on the values returned by the metrics provider
+func setAnalyserConfig(cfg *anomalydetector.FactoryConfig, valueInRange *kanaryv1alpha1.ValueInRange, discrete *kanaryv1alpha1.DiscreteValueOutOfList, continuous *kanaryv1alpha1.ContinuousValueDeviation) {
+	if continuous != nil {
+		cfg.ContinuousValueDeviationConfig = &anomalydetector.ContinuousValueDeviationConfig{
+			MaxDeviationPercent: *continuous.MaxDeviationPercent,
+		}
+	} else if valueInRange != nil {
+		cfg.ValueInRangeConfig = &anomalydetector.ValueInRangeConfig{
+			Min: *valueInRange.Min,
+			Max: *valueInRange.Max,
+		}
+	} else if discrete != nil {
+		cfg.DiscreteValueOutOfListConfig = &anomalydetector.DiscreteValueOutOfListConfig{
+			BadValues:        discrete.BadValues,
+			GoodValues:       discrete.GoodValues,
+			Key:              discrete.Key,
+			TolerancePercent: *discrete.TolerancePercent,
+		}
+		if discrete.MinSamples != nil {
+			cfg.DiscreteValueOutOfListConfig.MinSamples = *discrete.MinSamples
+		}
+	}
+}
+
+// setResultValues copies the last values measured by the anomaly detector in the result
+func setResultValues(result *Result, detector anomalydetector.AnomalyDetector) {
+	reporter, ok := detector.(anomalydetector.ValuesReporter)
+	if !ok {
+		return
+	}
+	for podName, value := range reporter.GetLastValues() {
+		if result.Values == nil {
+			result.Values = map[string]string{}
+		}
+		result.Values[podName] = strconv.FormatFloat(value, 'f', -1, 64)
+	}
+}
+
+// setResultInconclusive marks a passing result as inconclusive if the anomaly detector could not conclude on some pods
+func setResultInconclusive(result *Result, detector anomalydetector.AnomalyDetector) {
+	reporter, ok := detector.(anomalydetector.InconclusiveReporter)
+	if !ok || result.IsFailed {
+		return
+	}
+	if pods := reporter.GetInconclusivePods(); len(pods) > 0 {
+		result.IsInconclusive = true
+		result.Comment = fmt.Sprintf("inconclusive, not enough samples for the pods: %s", strings.Join(pods, ","))
+	}
+}
+
+// getThreshold returns a description of the bound applied on the measured values
+func getThreshold(valueInRange *kanaryv1alpha1.ValueInRange, discrete *kanaryv1alpha1.DiscreteValueOutOfList, continuous *kanaryv1alpha1.ContinuousValueDeviation) string {
+	switch {
+	case continuous != nil && continuous.MaxDeviationPercent != nil:
+		return fmt.Sprintf("maxDeviationPercent=%v", *continuous.MaxDeviationPercent)
+	case valueInRange != nil && valueInRange.Min != nil && valueInRange.Max != nil:
+		return fmt.Sprintf("[%v, %v]", *valueInRange.Min, *valueInRange.Max)
+	case discrete != nil && discrete.TolerancePercent != nil:
+		if discrete.MinSamples != nil {
+			return fmt.Sprintf("tolerancePercent=%v minSamples=%v", *discrete.TolerancePercent, *discrete.MinSamples)
+		}
+		return fmt.Sprintf("tolerancePercent=%v", *discrete.TolerancePercent)
+	}
+	return ""
+}
+
+// getTrendThreshold returns a description of the bounds applied on the trend of the values
+func getTrendThreshold(trend *kanaryv1alpha1.Trend) string {
+	var threshold []string
+	if trend.Limit != nil {
+		horizon := ""
+		if trend.Horizon != nil {
+			horizon = trend.Horizon.Duration.String()
+		}
+		threshold = append(threshold, fmt.Sprintf("limit=%v in %s", *trend.Limit, horizon))
+	}
+	if trend.MaxSlopeDeviationPercent != nil {
+		threshold = append(threshold, fmt.Sprintf("maxSlopeDeviationPercent=%v", *trend.MaxSlopeDeviationPercent))
+	}
+	return strings.Join(threshold, " ")
+}
@@ -1,6 +1,7 @@
 package validation
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -39,13 +40,22 @@ func Test_promqlImpl_Validation(t *testing.T) {
 		namespace       = "kanary"
 		defaultReplicas = int32(5)
 	)
+	newReadyPod := func(podName string, readySince time.Duration, podLabels map[string]string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: podName, Namespace: namespace, Labels: podLabels},
+			Status: corev1.PodStatus{Conditions: []corev1.PodCondition{
+				{Type: corev1.PodReady, Status: corev1.ConditionTrue, LastTransitionTime: metav1.Time{Time: now.Add(-readySince)}},
+			}},
+		}
+	}
+
 	type fields struct {
-		validationSpec         kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL
-		validationPeriod       time.Duration
-		maxIntervalPeriod      time.Duration
-		dryRun                 bool
-		anomalydetector        anomalydetector.AnomalyDetector
-		anomalydetectorFactory anomalydetector.Factory
+		validationSpec          kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL
+		validationPeriod        time.Duration
+		dryRun                  bool
+		anomalydetector         anomalydetector.AnomalyDetector
+		anomalydetectorFactory  anomalydetector.Factory
+		requestsProviderFactory func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)
 	}
 	type args struct {
 		kclient   client.Client
@@ -104,6 +114,142 @@ func Test_promqlImpl_Validation(t *testing.T) {
 			want: &Result{
 				IsFailed: true,
 				Comment:  "promQL query reported an issue with one of the kanary pod",
+				Score:    kanaryv1alpha1.NewFloat64(0),
+			},
+			wantErr: false,
+		},
+		{
+			name: "query template rendered",
+			fields: fields{
+				validationPeriod:       30 * time.Second,
+				validationSpec:         kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{Query: `up{namespace="{{.Namespace}}"}`},
+				anomalydetectorFactory: anomalydetector.FakeFactory([]*corev1.Pod{}, nil),
+			},
+			args: args{
+				kclient: fake.NewFakeClient([]runtime.Object{}...),
+				kd:      kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{}),
+			},
+			want: &Result{
+				IsFailed: false,
+				Query:    `up{namespace="` + namespace + `"}`,
+			},
+			wantErr: false,
+		},
+		{
+			name: "bad query template",
+			fields: fields{
+				validationPeriod:       30 * time.Second,
+				validationSpec:         kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{Query: `up{namespace="{{.Unknown}}"}`},
+				anomalydetectorFactory: anomalydetector.FakeFactory([]*corev1.Pod{}, nil),
+			},
+			args: args{
+				kclient: fake.NewFakeClient([]runtime.Object{}...),
+				kd:      kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{}),
+			},
+			want:    &Result{},
+			wantErr: true,
+		},
+		{
+			name: "values reported",
+			fields: fields{
+				validationPeriod: 30 * time.Second,
+				dryRun:           false,
+				validationSpec: kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{
+					ValueInRange: &kanaryv1alpha1.ValueInRange{Min: kanaryv1alpha1.NewFloat64(0), Max: kanaryv1alpha1.NewFloat64(1)},
+				},
+				anomalydetectorFactory: func(cfg anomalydetector.FactoryConfig) (anomalydetector.AnomalyDetector, error) {
+					return &anomalydetector.Fake{Values: map[string]float64{name + "-kanary": 0.25}}, nil
+				},
+			},
+			args: args{
+				kclient:   fake.NewFakeClient([]runtime.Object{utilstest.NewDeployment(name, namespace, defaultReplicas, nil)}...),
+				kd:        kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{}),
+				dep:       utilstest.NewDeployment(name, namespace, defaultReplicas, &utilstest.NewDeploymentOptions{CreationTime: creationTime, Labels: map[string]string{"foo": "bar"}, Selector: map[string]string{"foo": "bar"}}),
+				canaryDep: utilstest.NewDeployment(name+"-kanary-"+name, namespace, 1, &utilstest.NewDeploymentOptions{CreationTime: creationTime, Labels: map[string]string{"foo": "bar", "foo-k": "bar-k"}, Selector: map[string]string{"foo-k": "bar-k"}}),
+			},
+			want: &Result{
+				IsFailed:  false,
+				Values:    map[string]string{name + "-kanary": "0.25"},
+				Threshold: "[0, 1]",
+				Score:     kanaryv1alpha1.NewFloat64(100),
+			},
+			wantErr: false,
+		},
+		{
+			name: "trend values reported",
+			fields: fields{
+				validationPeriod: 30 * time.Second,
+				validationSpec: kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{
+					Trend: &kanaryv1alpha1.Trend{Limit: kanaryv1alpha1.NewFloat64(1024), Horizon: &metav1.Duration{Duration: time.Hour}, MaxSlopeDeviationPercent: kanaryv1alpha1.NewFloat64(50)},
+				},
+				anomalydetectorFactory: func(cfg anomalydetector.FactoryConfig) (anomalydetector.AnomalyDetector, error) {
+					if cfg.TrendConfig == nil || cfg.TrendConfig.Horizon != time.Hour || *cfg.TrendConfig.Limit != 1024 {
+						return nil, fmt.Errorf("unexpected trend config: %#v", cfg.TrendConfig)
+					}
+					return &anomalydetector.Fake{Values: map[string]float64{name + "-kanary/slope": 0.5, name + "-kanary/projection": 1900}}, nil
+				},
+			},
+			args: args{
+				kclient: fake.NewFakeClient([]runtime.Object{}...),
+				kd:      kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{}),
+			},
+			want: &Result{
+				IsFailed:  false,
+				Values:    map[string]string{name + "-kanary/slope": "0.5", name + "-kanary/projection": "1900"},
+				Threshold: "limit=1024 in 1h0m0s maxSlopeDeviationPercent=50",
+				Score:     kanaryv1alpha1.NewFloat64(100),
+			},
+			wantErr: false,
+		},
+		{
+			name: "pods excluded",
+			fields: fields{
+				validationPeriod: 30 * time.Second,
+				validationSpec: kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{
+					Query:      `rate(errors{pod=~"{{.CanaryPodsRegex}}"}[1m])`,
+					PodNameKey: "pod",
+					Exclusions: &kanaryv1alpha1.PromQLExclusions{
+						WarmUp:        &metav1.Duration{Duration: 5 * time.Minute},
+						MinRequests:   kanaryv1alpha1.NewFloat64(10),
+						RequestsQuery: `sum(increase(requests{namespace="{{.Namespace}}"}[1m])) by (pod)`,
+						Labels:        &metav1.LabelSelector{MatchLabels: map[string]string{"kanary-exclude": "true"}},
+					},
+				},
+				anomalydetectorFactory: func(cfg anomalydetector.FactoryConfig) (anomalydetector.AnomalyDetector, error) {
+					for _, pod := range []*corev1.Pod{
+						newReadyPod(name+"-0", time.Hour, nil),
+						newReadyPod(name+"-1", time.Minute, nil),
+						newReadyPod(name+"-2", time.Hour, nil),
+						newReadyPod(name+"-3", time.Hour, map[string]string{"kanary-exclude": "true"}),
+					} {
+						if _, err := cfg.ExclusionFunc(pod); err != nil {
+							return nil, err
+						}
+					}
+					return &anomalydetector.Fake{}, nil
+				},
+				requestsProviderFactory: func(cfg anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error) {
+					if cfg.Query != `sum(increase(requests{namespace="`+namespace+`"}[1m])) by (pod)` || cfg.PodNameKey != "pod" {
+						return nil, fmt.Errorf("unexpected requests query config: %#v", cfg)
+					}
+					return &fakeMetricsProvider{samples: []anomalydetector.Sample{
+						{PodName: name + "-0", Values: []float64{120}},
+						{PodName: name + "-1", Values: []float64{80}},
+						{PodName: name + "-2", Values: []float64{3}},
+					}}, nil
+				},
+			},
+			args: args{
+				kclient: fake.NewFakeClient([]runtime.Object{}...),
+				kd:      kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{}),
+			},
+			want: &Result{
+				Query: `rate(errors{pod=~""}[1m])`,
+				ExcludedPods: map[string]string{
+					name + "-1": ExclusionReasonWarmUp,
+					name + "-2": ExclusionReasonMinRequests,
+					name + "-3": ExclusionReasonLabel,
+				},
 			},
 			wantErr: false,
 		},
@@ -112,12 +258,12 @@ func Test_promqlImpl_Validation(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			reqLogger := log.WithValues("test:", tt.name)
 			p := &promqlImpl{
-				validationSpec:         tt.fields.validationSpec,
-				validationPeriod:       tt.fields.validationPeriod,
-				maxIntervalPeriod:      tt.fields.maxIntervalPeriod,
-				dryRun:                 tt.fields.dryRun,
-				anomalydetector:        tt.fields.anomalydetector,
-				anomalydetectorFactory: tt.fields.anomalydetectorFactory,
+				validationSpec:          tt.fields.validationSpec,
+				validationPeriod:        tt.fields.validationPeriod,
+				dryRun:                  tt.fields.dryRun,
+				anomalydetector:         tt.fields.anomalydetector,
+				anomalydetectorFactory:  tt.fields.anomalydetectorFactory,
+				requestsProviderFactory: tt.fields.requestsProviderFactory,
 			}
 			got, err := p.Validation(tt.args.kclient, reqLogger, tt.args.kd, tt.args.dep, tt.args.canaryDep, nil)
 			if (err != nil) != tt.wantErr {
@@ -130,3 +276,73 @@ func Test_promqlImpl_Validation(t *testing.T) {
 		})
 	}
 }
+
+// fakeMetricsProvider returns predefined samples
+type fakeMetricsProvider struct {
+	samples []anomalydetector.Sample
+	err     error
+}
+
+func (f *fakeMetricsProvider) GetSamples() ([]anomalydetector.Sample, error) {
+	return f.samples, f.err
+}
+
+func Test_promqlImpl_getRangeQueryStart(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	start := now.Add(-time.Hour)
+	lastCheck := metav1.NewTime(now.Add(-5 * time.Minute))
+	beforeStart := metav1.NewTime(start.Add(-time.Minute))
+
+	tests := []struct {
+		name     string
+		window   kanaryv1alpha1.PromQLRangeQueryWindow
+		statuses []kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		want     time.Time
+	}{
+		{
+			name:   "no previous check",
+			window: kanaryv1alpha1.LastCheckPromQLRangeQueryWindow,
+			want:   start,
+		},
+		{
+			name:   "previous check of the item",
+			window: kanaryv1alpha1.LastCheckPromQLRangeQueryWindow,
+			statuses: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				{Index: 0, LastCheckTime: &metav1.Time{Time: now.Add(-time.Minute)}},
+				{Index: 1, LastCheckTime: &lastCheck},
+			},
+			want: lastCheck.Time,
+		},
+		{
+			name:   "previous check before the validation start",
+			window: kanaryv1alpha1.LastCheckPromQLRangeQueryWindow,
+			statuses: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				{Index: 1, LastCheckTime: &beforeStart},
+			},
+			want: start,
+		},
+		{
+			name:   "validation window",
+			window: kanaryv1alpha1.ValidationPromQLRangeQueryWindow,
+			statuses: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{
+				{Index: 1, LastCheckTime: &lastCheck},
+			},
+			want: start,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "bar", "", 1, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.CreationTimestamp = metav1.NewTime(start)
+			kd.Spec.Validations.InitialDelay = nil
+			kd.Status.Validations = tt.statuses
+			p := &promqlImpl{
+				validationSpec: kanaryv1alpha1.KanaryStatefulsetSpecValidationPromQL{RangeQuery: &kanaryv1alpha1.PromQLRangeQuery{Window: tt.window}},
+				index:          1,
+			}
+			if got := p.getRangeQueryStart(kd); !got.Equal(tt.want) {
+				t.Errorf("promqlImpl.getRangeQueryStart() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/config"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// podMetricsListGVK is the PodMetrics list of the resource metrics API
+var podMetricsListGVK = schema.GroupVersionKind{Group: "metrics.k8s.io", Version: "v1beta1", Kind: "PodMetricsList"}
+
+// NewResourceUsage returns new validation.ResourceUsage instance
+func NewResourceUsage(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+	return &resourceUsageImpl{
+		validationSpec:   *s.ResourceUsage,
+		validationPeriod: list.ValidationPeriod.Duration,
+		dryRun:           list.NoUpdate,
+	}
+}
+
+type resourceUsageImpl struct {
+	validationSpec   kanaryv1alpha1.KanaryStatefulsetSpecValidationResourceUsage
+	validationPeriod time.Duration
+	dryRun           bool
+
+	usageGetter            anomalydetector.PodUsageGetter //for test purposes
+	anomalydetector        anomalydetector.AnomalyDetector
+	anomalydetectorFactory anomalydetector.Factory //for test purposes
+}
+
+func (r *resourceUsageImpl) initAnomalyDetector(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet) error {
+	usageGetter := r.usageGetter
+	if usageGetter == nil {
+		metricsClient, err := getResourceMetricsClient()
+		if err != nil {
+			return err
+		}
+		usageGetter = &podMetricsUsageGetter{kclient: metricsClient, namespace: kd.Namespace}
+	}
+
+	anomalyDetectorConfig := anomalydetector.FactoryConfig{
+		Config: anomalydetector.Config{
+			Logger: reqLogger,
+			PodLister: &promqlPodLister{
+				kclient:   kclient,
+				Namespace: kd.Namespace,
+			},
+			Selector: labels.SelectorFromSet(sts.Spec.Selector.MatchLabels),
+		},
+		ResourceUsageConfig: &anomalydetector.ResourceUsageConfig{
+			CPU:    getResourceUsageThresholdConfig(r.validationSpec.CPU, func(q *resource.Quantity) float64 { return float64(q.MilliValue()) / 1000 }),
+			Memory: getResourceUsageThresholdConfig(r.validationSpec.Memory, func(q *resource.Quantity) float64 { return float64(q.Value()) }),
+			IsCanary: func(pod *corev1.Pod) bool {
+				return isCanaryPod(pod, sts)
+			},
+			UsageGetter: usageGetter,
+		},
+	}
+
+	if r.anomalydetectorFactory == nil {
+		r.anomalydetectorFactory = anomalydetector.New
+	}
+
+	var err error
+	if r.anomalydetector, err = r.anomalydetectorFactory(anomalyDetectorConfig); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (r *resourceUsageImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{Threshold: r.getThreshold()}
+	if sts == nil || sts.Spec.Selector == nil {
+		return result, nil
+	}
+
+	//re-init the anomaly detector at each validation in case some settings have changed in the kd
+	if err := r.initAnomalyDetector(kclient, reqLogger, kd, sts); err != nil {
+		return result, err
+	}
+	pods, err := r.anomalydetector.GetPodsOutOfBounds()
+	if err != nil {
+		reqLogger.Error(err, "GetPodsOutOfBounds")
+		return result, err
+	}
+	setResultValues(result, r.anomalydetector)
+
+	if len(pods) > 0 {
+		result.IsFailed = true
+		result.Comment = "resource usage of one of the kanary pod is out of bounds"
+		reqLogger.Info("GetPodsOutOfBounds", "detection", len(pods))
+	}
+	result.Score = getPodsScore(result.Values, len(pods))
+	return result, nil
+}
+
+// getThreshold returns a description of the bounds applied on the cpu and memory usage
+func (r *resourceUsageImpl) getThreshold() string {
+	threshold := ""
+	for _, t := range []struct {
+		name string
+		spec *kanaryv1alpha1.ResourceUsageThreshold
+	}{{"cpu", r.validationSpec.CPU}, {"memory", r.validationSpec.Memory}} {
+		if t.spec == nil {
+			continue
+		}
+		if t.spec.MaxIncreasePercent != nil {
+			threshold += fmt.Sprintf(" %s.maxIncreasePercent=%v", t.name, *t.spec.MaxIncreasePercent)
+		}
+		if t.spec.Limit != nil {
+			threshold += fmt.Sprintf(" %s.limit=%s", t.name, t.spec.Limit.String())
+		}
+	}
+	if threshold == "" {
+		return ""
+	}
+	return threshold[1:]
+}
+
+func getResourceUsageThresholdConfig(spec *kanaryv1alpha1.ResourceUsageThreshold, toFloat func(*resource.Quantity) float64) *anomalydetector.ResourceUsageThresholdConfig {
+	if spec == nil {
+		return nil
+	}
+	cfg := &anomalydetector.ResourceUsageThresholdConfig{
+		MaxIncreasePercent: spec.MaxIncreasePercent,
+	}
+	if spec.Limit != nil {
+		limit := toFloat(spec.Limit)
+		cfg.Limit = &limit
+	}
+	return cfg
+}
+
+var (
+	resourceMetricsClient     client.Client
+	resourceMetricsClientErr  error
+	resourceMetricsClientOnce sync.Once
+)
+
+// getResourceMetricsClient returns a client reading directly from the API server: the resource metrics API
+// can't be watched, so the PodMetrics can't be read through the cache of the manager client
+func getResourceMetricsClient() (client.Client, error) {
+	resourceMetricsClientOnce.Do(func() {
+		cfg, err := config.GetConfig()
+		if err != nil {
+			resourceMetricsClientErr = err
+			return
+		}
+		resourceMetricsClient, resourceMetricsClientErr = client.New(cfg, client.Options{})
+	})
+	return resourceMetricsClient, resourceMetricsClientErr
+}
+
+var _ anomalydetector.PodUsageGetter = &podMetricsUsageGetter{}
+
+// podMetricsUsageGetter reads the pods usage from the metrics.k8s.io PodMetrics
+type podMetricsUsageGetter struct {
+	kclient   client.Client
+	namespace string
+}
+
+// GetPodsUsage implements anomalydetector.PodUsageGetter, the usage of the containers is summed by pod
+func (g *podMetricsUsageGetter) GetPodsUsage(pods []*corev1.Pod) (map[string]corev1.ResourceList, error) {
+	list := &unstructured.UnstructuredList{}
+	list.SetGroupVersionKind(podMetricsListGVK)
+	if err := g.kclient.List(context.TODO(), &client.ListOptions{Namespace: g.namespace}, list); err != nil {
+		return nil, fmt.Errorf("unable to list the pod metrics: %v", err)
+	}
+
+	podNames := map[string]bool{}
+	for _, pod := range pods {
+		podNames[pod.Name] = true
+	}
+	result := map[string]corev1.ResourceList{}
+	for _, item := range list.Items {
+		if !podNames[item.GetName()] {
+			continue
+		}
+		containers, _, err := unstructured.NestedSlice(item.Object, "containers")
+		if err != nil {
+			return nil, fmt.Errorf("unable to read the metrics of the pod %s: %v", item.GetName(), err)
+		}
+		usage := corev1.ResourceList{}
+		for _, container := range containers {
+			c, ok := container.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			containerUsage, _, err := unstructured.NestedStringMap(c, "usage")
+			if err != nil {
+				return nil, fmt.Errorf("unable to read the metrics of the pod %s: %v", item.GetName(), err)
+			}
+			for name, value := range containerUsage {
+				q, err := resource.ParseQuantity(value)
+				if err != nil {
+					return nil, fmt.Errorf("unable to parse the %s usage of the pod %s: %v", name, item.GetName(), err)
+				}
+				total := usage[corev1.ResourceName(name)]
+				total.Add(q)
+				usage[corev1.ResourceName(name)] = total
+			}
+		}
+		result[item.GetName()] = usage
+	}
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package validation
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+	utilstest "github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+func Test_resourceUsageImpl_Validation(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+	log := logf.Log.WithName("Test_resourceUsageImpl_Validation")
+
+	var (
+		name            = "foo"
+		namespace       = "kanary"
+		defaultReplicas = int32(5)
+	)
+	limit := resource.MustParse("500m")
+	spec := kanaryv1alpha1.KanaryStatefulsetSpecValidationResourceUsage{
+		CPU:    &kanaryv1alpha1.ResourceUsageThreshold{MaxIncreasePercent: kanaryv1alpha1.NewFloat64(20), Limit: &limit},
+		Memory: &kanaryv1alpha1.ResourceUsageThreshold{MaxIncreasePercent: kanaryv1alpha1.NewFloat64(50)},
+	}
+	sts := &kruisev1alpha1.StatefulSet{
+		Spec:   kruisev1alpha1.StatefulSetSpec{Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": name}}},
+		Status: kruisev1alpha1.StatefulSetStatus{UpdateRevision: "v2"},
+	}
+
+	tests := []struct {
+		name       string
+		sts        *kruisev1alpha1.StatefulSet
+		pods       []*corev1.Pod
+		values     map[string]float64
+		want       *Result
+		wantCanary map[string]bool
+	}{
+		{
+			name: "no statefulset",
+			want: &Result{Threshold: "cpu.maxIncreasePercent=20 cpu.limit=500m memory.maxIncreasePercent=50"},
+		},
+		{
+			name:   "canary pod out of bounds",
+			sts:    sts,
+			pods:   []*corev1.Pod{utilstest.NewPod(name+"-1", namespace, "hash", nil)},
+			values: map[string]float64{name + "-1/cpu": 0.75},
+			want: &Result{
+				IsFailed:  true,
+				Comment:   "resource usage of one of the kanary pod is out of bounds",
+				Threshold: "cpu.maxIncreasePercent=20 cpu.limit=500m memory.maxIncreasePercent=50",
+				Values:    map[string]string{name + "-1/cpu": "0.75"},
+				Score:     kanaryv1alpha1.NewFloat64(0),
+			},
+			wantCanary: map[string]bool{"v1": false, "v2": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotConfig anomalydetector.FactoryConfig
+			r := &resourceUsageImpl{
+				validationSpec: spec,
+				usageGetter:    &podMetricsUsageGetter{},
+				anomalydetectorFactory: func(cfg anomalydetector.FactoryConfig) (anomalydetector.AnomalyDetector, error) {
+					gotConfig = cfg
+					return &anomalydetector.Fake{Pods: tt.pods, Values: tt.values}, nil
+				},
+			}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "", defaultReplicas, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			got, err := r.Validation(fake.NewFakeClient([]runtime.Object{}...), log.WithValues("test:", tt.name), kd, nil, nil, tt.sts)
+			if err != nil {
+				t.Errorf("resourceUsageImpl.Validation() error = %v", err)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("resourceUsageImpl.Validation() = %#v, want %#v", got, tt.want)
+			}
+			if tt.sts == nil {
+				return
+			}
+			if *gotConfig.ResourceUsageConfig.CPU.Limit != 0.5 {
+				t.Errorf("resourceUsageImpl.Validation() cpu limit = %v, want 0.5", *gotConfig.ResourceUsageConfig.CPU.Limit)
+			}
+			for revision, want := range tt.wantCanary {
+				pod := utilstest.NewPod(name, namespace, "hash", &utilstest.NewPodOptions{Labels: map[string]string{appsv1.ControllerRevisionHashLabelKey: revision}})
+				if got := gotConfig.ResourceUsageConfig.IsCanary(pod); got != want {
+					t.Errorf("resourceUsageImpl.Validation() IsCanary(%s) = %v, want %v", revision, got, want)
+				}
+			}
+		})
+	}
+}
+
+// podMetricsClient returns the PodMetrics list, other methods are not implemented
+type podMetricsClient struct {
+	client.Client
+	items []unstructured.Unstructured
+}
+
+func (c *podMetricsClient) List(ctx context.Context, opts *client.ListOptions, obj runtime.Object) error {
+	obj.(*unstructured.UnstructuredList).Items = c.items
+	return nil
+}
+
+func newPodMetrics(name string, usages ...map[string]interface{}) unstructured.Unstructured {
+	containers := []interface{}{}
+	for _, usage := range usages {
+		containers = append(containers, map[string]interface{}{"usage": usage})
+	}
+	return unstructured.Unstructured{Object: map[string]interface{}{
+		"metadata":   map[string]interface{}{"name": name},
+		"containers": containers,
+	}}
+}
+
+func Test_podMetricsUsageGetter_GetPodsUsage(t *testing.T) {
+	g := &podMetricsUsageGetter{
+		kclient: &podMetricsClient{items: []unstructured.Unstructured{
+			newPodMetrics("foo-0", map[string]interface{}{"cpu": "100m", "memory": "10Mi"}, map[string]interface{}{"cpu": "50m", "memory": "5Mi"}),
+			newPodMetrics("foo-1", map[string]interface{}{"cpu": "1", "memory": "1Gi"}),
+			newPodMetrics("bar-0", map[string]interface{}{"cpu": "1", "memory": "1Gi"}),
+		}},
+		namespace: "kanary",
+	}
+	got, err := g.GetPodsUsage([]*corev1.Pod{utilstest.NewPod("foo-0", "kanary", "hash", nil), utilstest.NewPod("foo-1", "kanary", "hash", nil)})
+	if err != nil {
+		t.Fatalf("podMetricsUsageGetter.GetPodsUsage() error = %v", err)
+	}
+	want := map[string]string{"foo-0/cpu": "150m", "foo-0/memory": "15Mi", "foo-1/cpu": "1", "foo-1/memory": "1Gi"}
+	gotStr := map[string]string{}
+	for podName, usage := range got {
+		for name, q := range usage {
+			gotStr[podName+"/"+string(name)] = q.String()
+		}
+	}
+	if !reflect.DeepEqual(gotStr, want) {
+		t.Errorf("podMetricsUsageGetter.GetPodsUsage() = %v, want %v", gotStr, want)
+	}
+}
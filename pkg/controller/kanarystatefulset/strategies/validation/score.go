@@ -0,0 +1,143 @@
+package validation
+
+import (
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+const (
+	// MaxScore score of a passing check
+	MaxScore = float64(100)
+	// MinScore score of a failing check
+	MinScore = float64(0)
+)
+
+// GetResultScore returns the sub-score of a check: the score provided by the validation item, else
+// MaxScore if the check passed and MinScore if it failed.
+func GetResultScore(result *Result) float64 {
+	if result.Score != nil {
+		return *result.Score
+	}
+	if result.IsFailed {
+		return MinScore
+	}
+	return MaxScore
+}
+
+// getPodsScore returns the sub-score of a check done on several pods: the share of the measured pods within bounds.
+// The pod of a measured value is the prefix of its name, for example foo-1 for foo-1/cpu, and the values of the
+// stable pods are ignored. It returns nil if no pod has been measured, the sub-score is then based on the outcome.
+func getPodsScore(values map[string]string, outOfBounds int) *float64 {
+	pods := map[string]bool{}
+	for name := range values {
+		pod := strings.SplitN(name, "/", 2)[0]
+		if pod != "stable" {
+			pods[pod] = true
+		}
+	}
+	measured := len(pods)
+	if measured < outOfBounds {
+		measured = outOfBounds
+	}
+	if measured == 0 {
+		return nil
+	}
+	score := MaxScore * float64(measured-outOfBounds) / float64(measured)
+	return &score
+}
+
+// IsScored returns true if the validation item is part of the weighted score. The manual items keep their
+// decision role, and the advisory items never change the outcome of the validation.
+func IsScored(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, index int) bool {
+	return list.Items[index].Manual == nil && !list.Items[index].Advisory && getWeight(&list.Items[index]) > 0
+}
+
+// ComputeScore returns the weighted average of the sub-scores of the scored validation items, the inconclusive
+// checks are ignored. It returns false if no item is scored.
+func ComputeScore(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, indexes []int, results []*Result) (float64, bool) {
+	var sum, weights float64
+	for i, result := range results {
+		if result == nil || !IsScored(list, indexes[i]) || (result.IsInconclusive && !result.IsFailed) {
+			continue
+		}
+		weight := float64(getWeight(&list.Items[indexes[i]]))
+		sum += weight * GetResultScore(result)
+		weights += weight
+	}
+	if weights == 0 {
+		return 0, false
+	}
+	return sum / weights, true
+}
+
+// RecordScore stores the score of a check in the status
+func RecordScore(status *kanaryv1alpha1.KanaryStatefulsetStatus, score float64, now time.Time) {
+	if status.Score == nil {
+		status.Score = &kanaryv1alpha1.KanaryStatefulsetScoreStatus{}
+	}
+	status.Score.History = append(status.Score.History, kanaryv1alpha1.KanaryStatefulsetScoreCheck{Time: metav1.NewTime(now), Score: score})
+	if len(status.Score.History) > MaxCheckHistory {
+		status.Score.History = status.Score.History[len(status.Score.History)-MaxCheckHistory:]
+	}
+}
+
+// GetLastScore returns the score of the last check, it returns false if no score has been computed yet
+func GetLastScore(status *kanaryv1alpha1.KanaryStatefulsetStatus) (float64, bool) {
+	if status.Score == nil || len(status.Score.History) == 0 {
+		return 0, false
+	}
+	return status.Score.History[len(status.Score.History)-1].Score, true
+}
+
+// GetAverageScore returns the average of the scores of the last checks kept in the status, it returns false
+// if no score has been computed yet
+func GetAverageScore(status *kanaryv1alpha1.KanaryStatefulsetStatus) (float64, bool) {
+	if status.Score == nil || len(status.Score.History) == 0 {
+		return 0, false
+	}
+	var sum float64
+	for _, check := range status.Score.History {
+		sum += check.Score
+	}
+	return sum / float64(len(status.Score.History)), true
+}
+
+// IsScoreMarginal returns true if the average score of the last checks is below the pass threshold
+func IsScoreMarginal(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	if list.Score == nil || list.Score.Pass == nil {
+		return false
+	}
+	score, ok := GetAverageScore(status)
+	return ok && score < *list.Score.Pass
+}
+
+// CanExtendValidation returns true if the marginal action is ExtendValidation and the maximum number of extensions is not reached
+func CanExtendValidation(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	if list.Score == nil || list.Score.MarginalAction != kanaryv1alpha1.ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction {
+		return false
+	}
+	extensions := int32(0)
+	if status.Score != nil {
+		extensions = status.Score.Extensions
+	}
+	return list.Score.MaxExtensions == nil || extensions < *list.Score.MaxExtensions
+}
+
+// getScoreExtension returns the duration added to the validation period due to marginal scores
+func getScoreExtension(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) time.Duration {
+	if list.Score == nil || list.Score.ExtensionPeriod == nil || status.Score == nil {
+		return 0
+	}
+	return time.Duration(status.Score.Extensions) * list.Score.ExtensionPeriod.Duration
+}
+
+func getWeight(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation) int32 {
+	if item.Weight == nil {
+		return 1
+	}
+	return *item.Weight
+}
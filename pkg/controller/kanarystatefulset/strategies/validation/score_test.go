@@ -0,0 +1,140 @@
+package validation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestComputeScore(t *testing.T) {
+	list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+			{Name: "latency", Weight: kanaryv1alpha1.NewInt32(3)},
+			{Name: "errors"},
+			{Name: "manual", Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}},
+			{Name: "logs", Advisory: true},
+			{Name: "disabled", Weight: kanaryv1alpha1.NewInt32(0)},
+		},
+	}
+	indexes := []int{0, 1, 2, 3, 4}
+
+	tests := []struct {
+		name    string
+		results []*Result
+		want    float64
+		wantOK  bool
+	}{
+		{
+			name:    "all passed",
+			results: []*Result{{}, {}, {IsFailed: true}, {IsFailed: true}, {IsFailed: true}},
+			want:    100,
+			wantOK:  true,
+		},
+		{
+			name:    "weighted failure",
+			results: []*Result{{}, {IsFailed: true}, {}, {}, {}},
+			want:    75,
+			wantOK:  true,
+		},
+		{
+			name:    "sub-score provided by the item",
+			results: []*Result{{Score: kanaryv1alpha1.NewFloat64(60)}, {}, {}, {}, {}},
+			want:    70,
+			wantOK:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ComputeScore(list, indexes, tt.results)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ComputeScore() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	if _, ok := ComputeScore(list, []int{2, 3}, []*Result{{}, {}}); ok {
+		t.Errorf("ComputeScore() without scored item should return false")
+	}
+}
+
+func TestScoreMarginalAndExtension(t *testing.T) {
+	now := time.Now()
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	kd.CreationTimestamp = metav1.Time{Time: now}
+	kd.Spec.Validations = kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+		InitialDelay:     &metav1.Duration{Duration: time.Minute},
+		ValidationPeriod: &metav1.Duration{Duration: 10 * time.Minute},
+		Score: &kanaryv1alpha1.KanaryStatefulsetSpecValidationScore{
+			Pass:            kanaryv1alpha1.NewFloat64(95),
+			Marginal:        kanaryv1alpha1.NewFloat64(75),
+			MarginalAction:  kanaryv1alpha1.ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction,
+			ExtensionPeriod: &metav1.Duration{Duration: 5 * time.Minute},
+			MaxExtensions:   kanaryv1alpha1.NewInt32(2),
+		},
+	}
+
+	if IsScoreMarginal(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("IsScoreMarginal() without score should be false")
+	}
+	RecordScore(&kd.Status, 80, now)
+	if !IsScoreMarginal(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("IsScoreMarginal() with score 80 should be true")
+	}
+	if !CanExtendValidation(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("CanExtendValidation() should be true")
+	}
+
+	kd.Status.Score.Extensions = 2
+	if got, want := GetValidationDeadLine(kd), now.Add(21*time.Minute); !got.Equal(want) {
+		t.Errorf("GetValidationDeadLine() = %v, want %v", got, want)
+	}
+	if CanExtendValidation(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("CanExtendValidation() after max extensions should be false")
+	}
+
+	for i := 0; i < MaxCheckHistory+2; i++ {
+		RecordScore(&kd.Status, 96, now)
+	}
+	if len(kd.Status.Score.History) != MaxCheckHistory {
+		t.Errorf("RecordScore() history length = %d, want %d", len(kd.Status.Score.History), MaxCheckHistory)
+	}
+	if IsScoreMarginal(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("IsScoreMarginal() with score 96 should be false")
+	}
+
+	// a good last check does not hide the marginal checks before it
+	kd.Status.Score.History = nil
+	for _, score := range []float64{80, 85, 100} {
+		RecordScore(&kd.Status, score, now)
+	}
+	if !IsScoreMarginal(&kd.Spec.Validations, &kd.Status) {
+		t.Errorf("IsScoreMarginal() with an average score of 88.33 should be true")
+	}
+}
+
+func Test_getPodsScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		values      map[string]string
+		outOfBounds int
+		want        *float64
+	}{
+		{name: "no value", want: nil},
+		{name: "all pods within bounds", values: map[string]string{"foo-1": "1", "foo-2": "1"}, want: kanaryv1alpha1.NewFloat64(100)},
+		{name: "one pod out of bounds", values: map[string]string{"foo-1": "1", "foo-2": "1", "foo-3": "1", "foo-4": "1"}, outOfBounds: 1, want: kanaryv1alpha1.NewFloat64(75)},
+		{name: "values by pod and stable values", values: map[string]string{"foo-1/cpu": "1", "foo-1/memory": "1", "foo-2/cpu": "1", "foo-2/memory": "1", "stable/cpu": "1", "stable": "1"}, outOfBounds: 1, want: kanaryv1alpha1.NewFloat64(50)},
+		{name: "out of bounds pods without value", outOfBounds: 1, want: kanaryv1alpha1.NewFloat64(0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getPodsScore(tt.values, tt.outOfBounds)
+			if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+				t.Errorf("getPodsScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package validation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1beta1 "k8s.io/api/apps/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+)
+
+// NewSLO returns new validation.SLO instance
+func NewSLO(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, s *kanaryv1alpha1.KanaryStatefulsetSpecValidation) Interface {
+	return &sloImpl{
+		validationSpec: *s.SLO,
+		dryRun:         list.NoUpdate,
+	}
+}
+
+type sloImpl struct {
+	validationSpec kanaryv1alpha1.KanaryStatefulsetSpecValidationSLO
+	dryRun         bool
+}
+
+func (s *sloImpl) Validation(kclient client.Client, reqLogger logr.Logger, kd *kanaryv1alpha1.KanaryStatefulset, dep, canaryDep *appsv1beta1.Deployment, sts *kruisev1alpha1.StatefulSet) (*Result, error) {
+	result := &Result{Values: map[string]string{}}
+
+	templateData, err := NewQueryTemplateData(kclient, kd, sts)
+	if err != nil {
+		return result, err
+	}
+
+	// each window duration is queried once, even if it is shared by several thresholds
+	burnRates := map[time.Duration]float64{}
+	var queries, thresholds, comments, noData []string
+	for _, w := range s.validationSpec.Windows {
+		for _, d := range []time.Duration{w.Short.Duration, w.Long.Duration} {
+			if _, ok := burnRates[d]; ok {
+				continue
+			}
+			query := GetSLOBurnRateQuery(&s.validationSpec, d)
+			rendered, err := RenderQuery(query, templateData)
+			if err != nil {
+				return result, fmt.Errorf("slo burn rate query: %v", err)
+			}
+			queries = append(queries, rendered)
+			value, series, err := runSumQuery(kclient, kd.Namespace, templateData, s.validationSpec.PrometheusService, s.validationSpec.URL, s.validationSpec.Auth, query)
+			if err != nil {
+				return result, fmt.Errorf("slo burn rate query: %v", err)
+			}
+			// no request during the window: the burn rate is unknown
+			if series == 0 || math.IsNaN(value) || math.IsInf(value, 0) {
+				value = math.NaN()
+			}
+			burnRates[d] = value
+			result.Values["burnRate["+formatPromDuration(d)+"]"] = strconv.FormatFloat(value, 'f', 2, 64)
+		}
+
+		short, long := burnRates[w.Short.Duration], burnRates[w.Long.Duration]
+		thresholds = append(thresholds, fmt.Sprintf("%s/%s>%g", formatPromDuration(w.Short.Duration), formatPromDuration(w.Long.Duration), w.Factor))
+		if math.IsNaN(short) || math.IsNaN(long) {
+			noData = append(noData, formatPromDuration(w.Short.Duration)+"/"+formatPromDuration(w.Long.Duration))
+			continue
+		}
+		if short > w.Factor && long > w.Factor {
+			comments = append(comments, fmt.Sprintf("burn rate %.2f over %s and %.2f over %s exceeds %g", short, formatPromDuration(w.Short.Duration), long, formatPromDuration(w.Long.Duration), w.Factor))
+		}
+	}
+	result.Query = strings.Join(queries, "\n")
+	result.Threshold = fmt.Sprintf("objective %g%%, burn rate %s", s.validationSpec.Objective, strings.Join(thresholds, ","))
+
+	if len(comments) > 0 {
+		result.IsFailed = true
+		result.Comment = "slo error budget, " + strings.Join(comments, "; ")
+		reqLogger.Info("SLO validation", "detection", result.Comment)
+	} else if len(noData) > 0 {
+		result.IsInconclusive = true
+		result.Comment = "inconclusive, no request over the windows: " + strings.Join(noData, ",")
+	}
+	return result, nil
+}
+
+// GetSLOBurnRateQuery returns the promQL query of the burn rate of the canary pods over the window:
+// the error ratio of the canary pods divided by the error budget of the objective.
+// The selectors are scoped to the canary pods with PodNameKey, unless AllPodsQuery is set.
+func GetSLOBurnRateQuery(slo *kanaryv1alpha1.KanaryStatefulsetSpecValidationSLO, window time.Duration) string {
+	success, total := slo.SuccessSelector, slo.TotalSelector
+	if !slo.AllPodsQuery {
+		matcher := fmt.Sprintf(`%s=~"{{.CanaryPodsRegex}}"`, slo.PodNameKey)
+		success = addSelectorMatcher(success, matcher)
+		total = addSelectorMatcher(total, matcher)
+	}
+	budget := 1 - slo.Objective/100
+	w := formatPromDuration(window)
+	return fmt.Sprintf("(1 - sum(rate(%s[%s])) / sum(rate(%s[%s]))) / %s", success, w, total, w, strconv.FormatFloat(budget, 'g', 10, 64))
+}
+
+// addSelectorMatcher adds a label matcher to a promQL selector, for example http_requests_total{code!~"5.."}
+func addSelectorMatcher(selector, matcher string) string {
+	selector = strings.TrimSpace(selector)
+	open := strings.Index(selector, "{")
+	if open < 0 || !strings.HasSuffix(selector, "}") {
+		return selector + "{" + matcher + "}"
+	}
+	if strings.TrimSpace(selector[open+1:len(selector)-1]) == "" {
+		return selector[:open] + "{" + matcher + "}"
+	}
+	return selector[:len(selector)-1] + "," + matcher + "}"
+}
+
+// formatPromDuration formats a duration with the promQL syntax, for example 1h30m
+func formatPromDuration(d time.Duration) string {
+	if d%time.Minute != 0 {
+		return fmt.Sprintf("%ds", int64(d/time.Second))
+	}
+	s := strings.TrimSuffix(d.String(), "0s")
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
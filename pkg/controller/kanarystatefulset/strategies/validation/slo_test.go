@@ -0,0 +1,169 @@
+package validation
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/anomalydetector"
+)
+
+func TestGetSLOBurnRateQuery(t *testing.T) {
+	slo := &kanaryv1alpha1.KanaryStatefulsetSpecValidationSLO{
+		PodNameKey:      "pod",
+		SuccessSelector: `http_requests_total{namespace="{{.Namespace}}",code!~"5.."}`,
+		TotalSelector:   "http_requests_total",
+		Objective:       99.9,
+	}
+	want := `(1 - sum(rate(http_requests_total{namespace="{{.Namespace}}",code!~"5..",pod=~"{{.CanaryPodsRegex}}"}[1h])) / sum(rate(http_requests_total{pod=~"{{.CanaryPodsRegex}}"}[1h]))) / 0.001`
+	if got := GetSLOBurnRateQuery(slo, time.Hour); got != want {
+		t.Errorf("GetSLOBurnRateQuery() = %s, want %s", got, want)
+	}
+
+	slo.AllPodsQuery = true
+	want = `(1 - sum(rate(http_requests_total{namespace="{{.Namespace}}",code!~"5.."}[1h])) / sum(rate(http_requests_total[1h]))) / 0.001`
+	if got := GetSLOBurnRateQuery(slo, time.Hour); got != want {
+		t.Errorf("GetSLOBurnRateQuery() with allPodsQuery = %s, want %s", got, want)
+	}
+}
+
+func Test_addSelectorMatcher(t *testing.T) {
+	tests := []struct {
+		selector string
+		want     string
+	}{
+		{selector: "requests", want: `requests{pod="a"}`},
+		{selector: "requests{}", want: `requests{pod="a"}`},
+		{selector: ` requests{code="200"} `, want: `requests{code="200",pod="a"}`},
+		{selector: `{__name__="requests"}`, want: `{__name__="requests",pod="a"}`},
+	}
+	for _, tt := range tests {
+		if got := addSelectorMatcher(tt.selector, `pod="a"`); got != tt.want {
+			t.Errorf("addSelectorMatcher(%s) = %s, want %s", tt.selector, got, tt.want)
+		}
+	}
+}
+
+func Test_formatPromDuration(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		5 * time.Minute:  "5m",
+		time.Hour:        "1h",
+		90 * time.Minute: "1h30m",
+		6 * time.Hour:    "6h",
+		90 * time.Second: "90s",
+	} {
+		if got := formatPromDuration(d); got != want {
+			t.Errorf("formatPromDuration(%v) = %s, want %s", d, got, want)
+		}
+	}
+}
+
+func Test_sloImpl_Validation(t *testing.T) {
+	log := logf.Log.WithName("Test_sloImpl_Validation")
+	defer func(f func(anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error)) {
+		sumQueryProviderFactory = f
+	}(sumQueryProviderFactory)
+
+	spec := kanaryv1alpha1.KanaryStatefulsetSpecValidationSLO{
+		PrometheusService: "prometheus:9090",
+		PodNameKey:        "pod",
+		SuccessSelector:   `http_requests_total{code!~"5.."}`,
+		TotalSelector:     "http_requests_total",
+		Objective:         99,
+		Windows:           kanaryv1alpha1.DefaultSLOBurnRateWindows,
+	}
+	tests := []struct {
+		name             string
+		burnRates        map[string]float64
+		err              error
+		wantFailed       bool
+		wantInconclusive bool
+		wantValues       map[string]string
+		wantComment      string
+		wantErr          bool
+	}{
+		{
+			name:       "budget preserved",
+			burnRates:  map[string]float64{"5m": 1, "1h": 0.5, "30m": 0.8, "6h": 0.5},
+			wantValues: map[string]string{"burnRate[5m]": "1.00", "burnRate[1h]": "0.50", "burnRate[30m]": "0.80", "burnRate[6h]": "0.50"},
+		},
+		{
+			name:       "short window only",
+			burnRates:  map[string]float64{"5m": 20, "1h": 2, "30m": 4, "6h": 1},
+			wantValues: map[string]string{"burnRate[5m]": "20.00", "burnRate[1h]": "2.00", "burnRate[30m]": "4.00", "burnRate[6h]": "1.00"},
+		},
+		{
+			name:        "fast burn",
+			burnRates:   map[string]float64{"5m": 20, "1h": 15, "30m": 4, "6h": 1},
+			wantFailed:  true,
+			wantValues:  map[string]string{"burnRate[5m]": "20.00", "burnRate[1h]": "15.00", "burnRate[30m]": "4.00", "burnRate[6h]": "1.00"},
+			wantComment: "slo error budget, burn rate 20.00 over 5m and 15.00 over 1h exceeds 14.4",
+		},
+		{
+			name:             "no request",
+			burnRates:        map[string]float64{},
+			wantInconclusive: true,
+			wantValues:       map[string]string{"burnRate[5m]": "NaN", "burnRate[1h]": "NaN", "burnRate[30m]": "NaN", "burnRate[6h]": "NaN"},
+			wantComment:      "inconclusive, no request over the windows: 5m/1h,30m/6h",
+		},
+		{
+			name:             "no request over the short window",
+			burnRates:        map[string]float64{"1h": 0.5, "30m": 0.8, "6h": 0.5},
+			wantInconclusive: true,
+			wantValues:       map[string]string{"burnRate[5m]": "NaN", "burnRate[1h]": "0.50", "burnRate[30m]": "0.80", "burnRate[6h]": "0.50"},
+			wantComment:      "inconclusive, no request over the windows: 5m/1h",
+		},
+		{
+			name:        "slow burn without recent request",
+			burnRates:   map[string]float64{"1h": 8, "30m": 7, "6h": 6.5},
+			wantFailed:  true,
+			wantValues:  map[string]string{"burnRate[5m]": "NaN", "burnRate[1h]": "8.00", "burnRate[30m]": "7.00", "burnRate[6h]": "6.50"},
+			wantComment: "slo error budget, burn rate 7.00 over 30m and 6.50 over 6h exceeds 6",
+		},
+		{
+			name:    "prometheus error",
+			err:     fmt.Errorf("unavailable"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sumQueryProviderFactory = func(cfg anomalydetector.ConfigPrometheusAnomalyDetector) (anomalydetector.MetricsProvider, error) {
+				if tt.err != nil {
+					return &fakeMetricsProvider{err: tt.err}, nil
+				}
+				for window, value := range tt.burnRates {
+					if strings.Contains(cfg.Query, "["+window+"]") {
+						return &fakeMetricsProvider{samples: []anomalydetector.Sample{{PodName: anomalydetector.GlobalQueryKey, Values: []float64{value}}}}, nil
+					}
+				}
+				return &fakeMetricsProvider{}, nil
+			}
+			s := &sloImpl{validationSpec: spec}
+			kd := kanaryv1alpha1test.NewKanaryStatefulset("foo", "bar", "", 1, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{})
+			kd.CreationTimestamp = metav1.Now()
+			got, err := s.Validation(fake.NewFakeClient([]runtime.Object{}...), log, kd, nil, nil, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("sloImpl.Validation() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.IsFailed != tt.wantFailed || got.IsInconclusive != tt.wantInconclusive || got.Comment != tt.wantComment || !reflect.DeepEqual(got.Values, tt.wantValues) {
+				t.Errorf("sloImpl.Validation() = %#v", got)
+			}
+			if len(strings.Split(got.Query, "\n")) != 4 || !strings.Contains(got.Query, `code!~"5..",pod=~""}[6h]`) {
+				t.Errorf("the rendered queries of the 4 windows should be reported, got %s", got.Query)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils"
+)
+
+// QueryTemplateData contains the variables available in the query templates
+type QueryTemplateData struct {
+	Namespace            string
+	KanaryName           string
+	StatefulSetName      string
+	ServiceName          string
+	CanaryDeploymentName string
+	// CanaryRevision is the revision of the statefulset pods under validation
+	CanaryRevision string
+	// StableRevision is the revision of the statefulset pods not updated yet
+	StableRevision string
+	// CanaryPodsRegex matches the name of the canary pods, for example "myapp-3|myapp-4"
+	CanaryPodsRegex string
+	// StablePodsRegex matches the name of the stable pods
+	StablePodsRegex string
+	// ValidationStart is the end of the initial delay
+	ValidationStart time.Time
+	// Window is the baseline window of a promQL dependency, for example "10m". Only set in the dependency queries.
+	Window string
+}
+
+// NewQueryTemplateData returns the variables available in the query templates
+func NewQueryTemplateData(kclient client.Client, kd *kanaryv1alpha1.KanaryStatefulset, sts *kruisev1alpha1.StatefulSet) (*QueryTemplateData, error) {
+	data := &QueryTemplateData{
+		Namespace:            kd.Namespace,
+		KanaryName:           kd.Name,
+		StatefulSetName:      kd.Spec.StatefulSetName,
+		ServiceName:          kd.Spec.ServiceName,
+		CanaryDeploymentName: utils.GetCanaryDeploymentName(kd),
+		ValidationStart:      GetValidationStart(kd),
+	}
+	if sts == nil || sts.Spec.Selector == nil {
+		return data, nil
+	}
+
+	data.CanaryRevision = sts.Status.UpdateRevision
+	data.StableRevision = sts.Status.CurrentRevision
+
+	selector := labels.SelectorFromSet(sts.Spec.Selector.MatchLabels)
+	pods := &corev1.PodList{}
+	if err := kclient.List(context.TODO(), &client.ListOptions{Namespace: kd.Namespace, LabelSelector: selector}, pods); err != nil {
+		return nil, fmt.Errorf("unable to list the statefulset pods: %v", err)
+	}
+	var canaryPods, stablePods []string
+	for _, pod := range pods.Items {
+		if !selector.Matches(labels.Set(pod.Labels)) {
+			continue
+		}
+		if isCanaryPod(&pod, sts) {
+			canaryPods = append(canaryPods, regexp.QuoteMeta(pod.Name))
+		} else {
+			stablePods = append(stablePods, regexp.QuoteMeta(pod.Name))
+		}
+	}
+	sort.Strings(canaryPods)
+	sort.Strings(stablePods)
+	data.CanaryPodsRegex = strings.Join(canaryPods, "|")
+	data.StablePodsRegex = strings.Join(stablePods, "|")
+
+	return data, nil
+}
+
+// RenderQuery renders the query as a go template with the given data
+func RenderQuery(query string, data *QueryTemplateData) (string, error) {
+	tmpl, err := template.New("query").Option("missingkey=error").Parse(query)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse the query template: %v", err)
+	}
+	var buf bytes.Buffer
+	if err = tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("unable to render the query template: %v", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	kanaryv1alpha1test "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1/test"
+	utilstest "github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils/test"
+
+	kruisev1alpha1 "github.com/openkruise/kruise/pkg/apis/apps/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNewQueryTemplateData(t *testing.T) {
+	var (
+		name      = "foo"
+		namespace = "kanary"
+		start     = metav1.Time{Time: time.Now().Add(-time.Hour)}
+	)
+	kd := kanaryv1alpha1test.NewKanaryStatefulset(name, namespace, "foo-svc", 3, &kanaryv1alpha1test.NewKanaryStatefulsetOptions{
+		StartTime:   &start,
+		Validations: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{InitialDelay: &metav1.Duration{Duration: time.Minute}},
+	})
+	kd.Spec.StatefulSetName = "myapp"
+
+	sts := &kruisev1alpha1.StatefulSet{}
+	sts.Spec.Selector = &metav1.LabelSelector{MatchLabels: map[string]string{"app": "myapp"}}
+	sts.Status.UpdateRevision = "myapp-v2"
+	sts.Status.CurrentRevision = "myapp-v1"
+
+	kclient := fake.NewFakeClient([]runtime.Object{
+		utilstest.NewPod("myapp-0", namespace, "hash", &utilstest.NewPodOptions{Labels: map[string]string{"app": "myapp", "controller-revision-hash": "myapp-v1"}}),
+		utilstest.NewPod("myapp-1", namespace, "hash", &utilstest.NewPodOptions{Labels: map[string]string{"app": "myapp", "controller-revision-hash": "myapp-v1"}}),
+		utilstest.NewPod("myapp-2", namespace, "hash", &utilstest.NewPodOptions{Labels: map[string]string{"app": "myapp", "controller-revision-hash": "myapp-v2"}}),
+		utilstest.NewPod("other-0", namespace, "hash", &utilstest.NewPodOptions{Labels: map[string]string{"app": "other", "controller-revision-hash": "myapp-v2"}}),
+	}...)
+
+	tests := []struct {
+		name    string
+		sts     *kruisev1alpha1.StatefulSet
+		want    *QueryTemplateData
+		wantErr bool
+	}{
+		{
+			name: "no statefulset",
+			sts:  nil,
+			want: &QueryTemplateData{
+				Namespace:            namespace,
+				KanaryName:           name,
+				StatefulSetName:      "myapp",
+				ServiceName:          "foo-svc",
+				CanaryDeploymentName: "foo-kanary-foo",
+				ValidationStart:      start.Add(time.Minute),
+			},
+		},
+		{
+			name: "canary and stable pods",
+			sts:  sts,
+			want: &QueryTemplateData{
+				Namespace:            namespace,
+				KanaryName:           name,
+				StatefulSetName:      "myapp",
+				ServiceName:          "foo-svc",
+				CanaryDeploymentName: "foo-kanary-foo",
+				CanaryRevision:       "myapp-v2",
+				StableRevision:       "myapp-v1",
+				CanaryPodsRegex:      "myapp-2",
+				StablePodsRegex:      "myapp-0|myapp-1",
+				ValidationStart:      start.Add(time.Minute),
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewQueryTemplateData(kclient, kd, tt.sts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewQueryTemplateData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewQueryTemplateData() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderQuery(t *testing.T) {
+	data := &QueryTemplateData{Namespace: "kanary", CanaryPodsRegex: "myapp-2|myapp-3"}
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no template",
+			query: `sum(rate(http_requests_total[1m]))`,
+			want:  `sum(rate(http_requests_total[1m]))`,
+		},
+		{
+			name:  "variables",
+			query: `sum(rate(http_requests_total{namespace="{{.Namespace}}",pod=~"{{.CanaryPodsRegex}}"}[1m]))`,
+			want:  `sum(rate(http_requests_total{namespace="kanary",pod=~"myapp-2|myapp-3"}[1m]))`,
+		},
+		{
+			name:    "unknown variable",
+			query:   `up{pod="{{.PodName}}"}`,
+			wantErr: true,
+		},
+		{
+			name:    "bad template",
+			query:   `up{pod="{{.Namespace"}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RenderQuery(tt.query, data)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenderQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RenderQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
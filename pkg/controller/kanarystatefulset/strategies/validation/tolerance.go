@@ -0,0 +1,85 @@
+package validation
+
+import (
+	"time"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+// ApplyTolerance updates the validation item status counters with the result of a check.
+// If the failure tolerance of the validation item is not exceeded, the failure is ignored and
+// result.IsFailed is set back to false. An inconclusive check does not change the counters.
+func ApplyTolerance(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation, status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, result *Result) {
+	if result == nil || (result.IsInconclusive && !result.IsFailed) {
+		return
+	}
+
+	if !result.IsFailed {
+		status.Successes++
+		status.ConsecutiveSuccesses++
+		if status.ConsecutiveSuccesses >= getSuccessThreshold(item) {
+			// cap the counter, only reaching the threshold matters
+			status.ConsecutiveSuccesses = getSuccessThreshold(item)
+			status.ConsecutiveFailures = 0
+		}
+		return
+	}
+
+	status.ConsecutiveSuccesses = 0
+	status.ConsecutiveFailures++
+	status.Failures++
+
+	hasFailureLimit := item.FailureLimit != nil && *item.FailureLimit > 0
+	// with failureLimit alone, the consecutive failures are tolerated up to the limit
+	if (item.FailureThreshold != nil || !hasFailureLimit) && status.ConsecutiveFailures >= getFailureThreshold(item) {
+		return
+	}
+	if hasFailureLimit && status.Failures >= *item.FailureLimit {
+		return
+	}
+	result.IsFailed = false
+}
+
+// IsCheckDue returns true if the validation item has not been checked during the last MaxIntervalPeriod. The reconciles
+// triggered in between, by the changes of the pods or by the Warning events, should not update the tolerance counters.
+func IsCheckDue(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetValidationStatus, now time.Time) bool {
+	if status.LastCheckTime == nil || list.MaxIntervalPeriod == nil {
+		return true
+	}
+	return now.Sub(status.LastCheckTime.Time) >= list.MaxIntervalPeriod.Duration
+}
+
+// IsRecovering returns true if a validation item had a failed check and
+// did not reach its success threshold since, and if its failure could fail the KanaryStatefulset:
+// the advisory items are ignored, with a score only the manual items count since the score decides for the others,
+// and with a policy the recovering items only count if their failure fails the policy.
+func IsRecovering(list *kanaryv1alpha1.KanaryStatefulsetSpecValidationList, status *kanaryv1alpha1.KanaryStatefulsetStatus) bool {
+	recoveringByName := map[string]bool{}
+	for _, v := range status.Validations {
+		if v.ConsecutiveFailures == 0 || v.Index >= len(list.Items) || IsAdvisory(list, v.Index) {
+			continue
+		}
+		if list.Score != nil && list.Items[v.Index].Manual == nil {
+			continue
+		}
+		if list.Policy == nil {
+			return true
+		}
+		recoveringByName[list.Items[v.Index].Name] = true
+	}
+	return list.Policy != nil && IsPolicyFailed(list.Policy, recoveringByName)
+}
+
+func getFailureThreshold(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation) int32 {
+	if item.FailureThreshold == nil || *item.FailureThreshold < 1 {
+		return 1
+	}
+	return *item.FailureThreshold
+}
+
+func getSuccessThreshold(item *kanaryv1alpha1.KanaryStatefulsetSpecValidation) int32 {
+	if item.SuccessThreshold == nil || *item.SuccessThreshold < 1 {
+		return 1
+	}
+	return *item.SuccessThreshold
+}
@@ -0,0 +1,210 @@
+package validation
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
+func TestApplyTolerance(t *testing.T) {
+	tests := []struct {
+		name       string
+		item       *kanaryv1alpha1.KanaryStatefulsetSpecValidation
+		status     kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		result     *Result
+		wantStatus kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		wantFailed bool
+	}{
+		{
+			name:       "default, success",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{},
+			result:     &Result{},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 1, Successes: 1},
+			wantFailed: false,
+		},
+		{
+			name:       "default, failure",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 1},
+			wantFailed: true,
+		},
+		{
+			name:       "failureThreshold not reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 1},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, Failures: 2},
+			wantFailed: false,
+		},
+		{
+			name:       "failureThreshold reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, Failures: 2},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 3, Failures: 3},
+			wantFailed: true,
+		},
+		{
+			name:       "failureLimit reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3), FailureLimit: kanaryv1alpha1.NewInt32(4)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 1, Failures: 3},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 4},
+			wantFailed: true,
+		},
+		{
+			name:       "failureLimit alone not reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureLimit: kanaryv1alpha1.NewInt32(3)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 1},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, Failures: 2},
+			wantFailed: false,
+		},
+		{
+			name:       "failureLimit alone reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureLimit: kanaryv1alpha1.NewInt32(3)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 1, Failures: 2},
+			result:     &Result{IsFailed: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 3},
+			wantFailed: true,
+		},
+		{
+			name:       "successThreshold not reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3), SuccessThreshold: kanaryv1alpha1.NewInt32(2)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, Failures: 2},
+			result:     &Result{},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, ConsecutiveSuccesses: 1, Failures: 2, Successes: 1},
+			wantFailed: false,
+		},
+		{
+			name:       "successThreshold reached",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3), SuccessThreshold: kanaryv1alpha1.NewInt32(2)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 2, ConsecutiveSuccesses: 1, Failures: 2},
+			result:     &Result{},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 2, Failures: 2, Successes: 1},
+			wantFailed: false,
+		},
+		{
+			name:       "consecutive successes capped",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 1, Successes: 1},
+			result:     &Result{},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveSuccesses: 1, Successes: 2},
+			wantFailed: false,
+		},
+		{
+			name:       "inconclusive, counters unchanged",
+			item:       &kanaryv1alpha1.KanaryStatefulsetSpecValidation{FailureThreshold: kanaryv1alpha1.NewInt32(3)},
+			status:     kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 1},
+			result:     &Result{IsInconclusive: true},
+			wantStatus: kanaryv1alpha1.KanaryStatefulsetValidationStatus{ConsecutiveFailures: 1, Failures: 1},
+			wantFailed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := tt.status
+			ApplyTolerance(tt.item, &status, tt.result)
+			if !reflect.DeepEqual(status, tt.wantStatus) {
+				t.Errorf("ApplyTolerance() status = %#v, want %#v", status, tt.wantStatus)
+			}
+			if tt.result.IsFailed != tt.wantFailed {
+				t.Errorf("ApplyTolerance() IsFailed = %v, want %v", tt.result.IsFailed, tt.wantFailed)
+			}
+		})
+	}
+}
+
+func TestIsCheckDue(t *testing.T) {
+	now := time.Now()
+	list := &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{MaxIntervalPeriod: &metav1.Duration{Duration: time.Minute}}
+	tests := []struct {
+		name      string
+		lastCheck time.Duration
+		want      bool
+	}{
+		{name: "never checked", want: true},
+		{name: "checked during the interval", lastCheck: 20 * time.Second, want: false},
+		{name: "checked before the interval", lastCheck: time.Minute, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := &kanaryv1alpha1.KanaryStatefulsetValidationStatus{}
+			if tt.lastCheck > 0 {
+				status.LastCheckTime = &metav1.Time{Time: now.Add(-tt.lastCheck)}
+			}
+			if got := IsCheckDue(list, status, now); got != tt.want {
+				t.Errorf("IsCheckDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsRecovering(t *testing.T) {
+	orPolicy := &kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+		Operator: kanaryv1alpha1.OrKanaryStatefulsetSpecValidationPolicyOperator,
+		Items:    []string{"latency", "errors"},
+	}
+	andPolicy := &kanaryv1alpha1.KanaryStatefulsetSpecValidationPolicy{
+		Operator: kanaryv1alpha1.AndKanaryStatefulsetSpecValidationPolicyOperator,
+		Items:    []string{"latency", "errors"},
+	}
+	tests := []struct {
+		name        string
+		list        *kanaryv1alpha1.KanaryStatefulsetSpecValidationList
+		validations []kanaryv1alpha1.KanaryStatefulsetValidationStatus
+		want        bool
+	}{
+		{
+			name:        "no failure",
+			list:        &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}}},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, ConsecutiveSuccesses: 1}},
+			want:        false,
+		},
+		{
+			name:        "failure",
+			list:        &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}}},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, ConsecutiveFailures: 1}},
+			want:        true,
+		},
+		{
+			name:        "advisory failure",
+			list:        &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency", Advisory: true}}},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, ConsecutiveFailures: 3}},
+			want:        false,
+		},
+		{
+			name: "failure with a score",
+			list: &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{
+				Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}, {Name: "approval", Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}}},
+				Score: &kanaryv1alpha1.KanaryStatefulsetSpecValidationScore{Pass: kanaryv1alpha1.NewFloat64(95)},
+			},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0, ConsecutiveFailures: 1}, {Index: 1}},
+			want:        false,
+		},
+		{
+			name:        "failure failing the or policy",
+			list:        &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}, {Name: "errors"}}, Policy: orPolicy},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0}, {Index: 1, ConsecutiveFailures: 1}},
+			want:        true,
+		},
+		{
+			name:        "failure tolerated by the and policy",
+			list:        &kanaryv1alpha1.KanaryStatefulsetSpecValidationList{Items: []kanaryv1alpha1.KanaryStatefulsetSpecValidation{{Name: "latency"}, {Name: "errors"}}, Policy: andPolicy},
+			validations: []kanaryv1alpha1.KanaryStatefulsetValidationStatus{{Index: 0}, {Index: 1, ConsecutiveFailures: 1}},
+			want:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsRecovering(tt.list, &kanaryv1alpha1.KanaryStatefulsetStatus{Validations: tt.validations}); got != tt.want {
+				t.Errorf("IsRecovering() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
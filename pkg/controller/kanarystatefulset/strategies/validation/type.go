@@ -1,8 +1,28 @@
 package validation
 
+import (
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+)
+
 //Result returns result of a Validation
 type Result struct {
 	IsFailed        bool
 	ForceSuccessNow bool
 	Comment         string
+
+	// IsInconclusive the check could not conclude, it is neither a success nor a failure
+	IsInconclusive bool
+	// Values measured during the check, indexed by pod name
+	Values map[string]string
+	// Threshold applied on the measured values
+	Threshold string
+	// Query is the rendered query sent to the metrics backend
+	Query string
+	// Score optional sub-score (0-100) of the check, used by the weighted score.
+	// If not set, the sub-score is 100 for a passing check and 0 for a failing check.
+	Score *float64
+	// ExcludedPods pods excluded from the anomaly detection, with the reason, indexed by pod name
+	ExcludedPods map[string]string
+	// Baselines of the promQL dependencies captured during the check, recorded once in the status
+	Baselines []kanaryv1alpha1.KanaryStatefulsetValidationBaseline
 }
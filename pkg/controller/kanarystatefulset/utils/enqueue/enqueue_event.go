@@ -0,0 +1,61 @@
+package enqueue
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var _ handler.Mapper = &WarningEventMapper{}
+
+// WarningEventMapper maps the Warning Events involving a StatefulSet, or one of its pods, to the
+// KanaryStatefulsets of this StatefulSet.
+type WarningEventMapper struct {
+	Client client.Client
+}
+
+// Map implements handler.Mapper
+func (m *WarningEventMapper) Map(obj handler.MapObject) []reconcile.Request {
+	ev, ok := obj.Object.(*corev1.Event)
+	if !ok || ev.Type != corev1.EventTypeWarning {
+		return nil
+	}
+	if ev.InvolvedObject.Kind != "Pod" && ev.InvolvedObject.Kind != "StatefulSet" {
+		return nil
+	}
+
+	list := &v1alpha1.KanaryStatefulsetList{}
+	if err := m.Client.List(context.TODO(), &client.ListOptions{Namespace: ev.InvolvedObject.Namespace}, list); err != nil {
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, kd := range list.Items {
+		if kd.Spec.StatefulSetName == "" {
+			continue
+		}
+		if (ev.InvolvedObject.Kind == "StatefulSet" && ev.InvolvedObject.Name == kd.Spec.StatefulSetName) ||
+			(ev.InvolvedObject.Kind == "Pod" && IsStatefulSetPodName(ev.InvolvedObject.Name, kd.Spec.StatefulSetName)) {
+			requests = append(requests, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: kd.Namespace, Name: kd.Name},
+			})
+		}
+	}
+	return requests
+}
+
+// IsStatefulSetPodName returns true if the pod name is the name of a pod of the StatefulSet: <statefulset>-<ordinal>
+func IsStatefulSetPodName(podName, statefulSetName string) bool {
+	if !strings.HasPrefix(podName, statefulSetName+"-") {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(podName, statefulSetName+"-"))
+	return err == nil
+}
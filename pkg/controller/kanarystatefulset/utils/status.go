@@ -3,6 +3,8 @@ package utils
 import (
 	"context"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/go-logr/logr"
@@ -70,6 +72,17 @@ func UpdateKanaryStatefulsetStatusCondition(status *kanaryv1alpha1.KanaryStatefu
 	}
 }
 
+// GetValidationStatus returns the status of the validation item at the given index, the status is created if missing
+func GetValidationStatus(status *kanaryv1alpha1.KanaryStatefulsetStatus, index int) *kanaryv1alpha1.KanaryStatefulsetValidationStatus {
+	for i := range status.Validations {
+		if status.Validations[i].Index == index {
+			return &status.Validations[i]
+		}
+	}
+	status.Validations = append(status.Validations, kanaryv1alpha1.KanaryStatefulsetValidationStatus{Index: index})
+	return &status.Validations[len(status.Validations)-1]
+}
+
 // NewKanaryStatefulsetStatusCondition returns new KanaryStatefulsetCondition instance
 func NewKanaryStatefulsetStatusCondition(conditionType kanaryv1alpha1.KanaryStatefulsetConditionType, conditionStatus corev1.ConditionStatus, now metav1.Time, reason, message string) kanaryv1alpha1.KanaryStatefulsetCondition {
 	return kanaryv1alpha1.KanaryStatefulsetCondition{
@@ -213,6 +226,27 @@ func getValidation(kd *kanaryv1alpha1.KanaryStatefulset) string {
 		if v.PromQL != nil {
 			list = append(list, "promQL")
 		}
+		if v.Metrics != nil {
+			list = append(list, "metrics")
+		}
+		if v.ResourceUsage != nil {
+			list = append(list, "resourceUsage")
+		}
+		if v.Logs != nil {
+			list = append(list, "logs")
+		}
+		if v.Events != nil {
+			list = append(list, "events")
+		}
+		if v.Job != nil {
+			list = append(list, "job")
+		}
+		if v.Alerts != nil {
+			list = append(list, "alerts")
+		}
+		if v.SLO != nil {
+			list = append(list, "slo")
+		}
 		if v.Manual != nil {
 			list = append(list, "manual")
 		}
@@ -234,11 +268,36 @@ func getTraffic(kd *kanaryv1alpha1.KanaryStatefulset) string {
 	return string(kd.Spec.Traffic.Source)
 }
 
+// reportScoreHistoryLength number of scores displayed in the report
+const reportScoreHistoryLength = 5
+
+// getScore returns the last scores, the most recent last
+func getScore(status *kanaryv1alpha1.KanaryStatefulsetStatus) string {
+	if status.Score == nil {
+		return ""
+	}
+	history := status.Score.History
+	if len(history) > reportScoreHistoryLength {
+		history = history[len(history)-reportScoreHistoryLength:]
+	}
+	var scores []string
+	for _, check := range history {
+		scores = append(scores, FormatScore(check.Score))
+	}
+	return strings.Join(scores, ",")
+}
+
+// FormatScore formats a score with at most one decimal
+func FormatScore(score float64) string {
+	return strconv.FormatFloat(math.Round(score*10)/10, 'f', -1, 64)
+}
+
 func updateStatusReport(kd *kanaryv1alpha1.KanaryStatefulset, status *kanaryv1alpha1.KanaryStatefulsetStatus) {
 	status.Report = kanaryv1alpha1.KanaryStatefulsetStatusReport{
 		Status:     getReportStatus(status),
 		Validation: getValidation(kd),
 		Scale:      getScale(kd),
 		Traffic:    getTraffic(kd),
+		Score:      getScore(status),
 	}
 }
@@ -92,6 +92,7 @@ func NewService(name, namespace string, labelsSelector map[string]string, option
 type NewPodOptions struct {
 	CreationTime *metav1.Time
 	Labels       map[string]string
+	Annotations  map[string]string
 }
 
 // NewPods returns a slice of new Pod instance
@@ -125,6 +126,9 @@ func NewPod(name, namespace, hash string, options *NewPodOptions) *corev1.Pod {
 		if options.Labels != nil {
 			newPod.Labels = options.Labels
 		}
+		for key, value := range options.Annotations {
+			newPod.Annotations[key] = value
+		}
 	}
 
 	return newPod
@@ -2,6 +2,12 @@ package utils
 
 import (
 	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
 )
@@ -13,6 +19,17 @@ func ValidateKanaryStatefulset(kd *v1alpha1.KanaryStatefulset) []error {
 	errs = append(errs, validateKanaryStatefulsetSpecScale(&kd.Spec.Scale)...)
 	errs = append(errs, validateKanaryStatefulsetSpecTraffic(&kd.Spec.Traffic)...)
 	errs = append(errs, validateKanaryStatefulsetSpecValidationList(&kd.Spec.Validations)...)
+	errs = append(errs, validateKanaryStatefulsetApprovalAnnotation(kd)...)
+	return errs
+}
+
+func validateKanaryStatefulsetApprovalAnnotation(kd *v1alpha1.KanaryStatefulset) []error {
+	var errs []error
+	switch approval := v1alpha1.KanaryStatefulsetSpecValidationManualStatus(kd.Annotations[v1alpha1.KanaryStatefulsetApprovalAnnotationKey]); approval {
+	case "", v1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus, v1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus:
+	default:
+		errs = append(errs, fmt.Errorf("annotation %s should be valid or invalid, current value: %q", v1alpha1.KanaryStatefulsetApprovalAnnotationKey, approval))
+	}
 	return errs
 }
 
@@ -50,14 +67,420 @@ func validateKanaryStatefulsetSpecValidationList(list *v1alpha1.KanaryStatefulse
 	for _, v := range list.Items {
 		errs = append(errs, validateKanaryStatefulsetSpecValidation(&v)...)
 	}
+	if list.Policy != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationPolicy(list, list.Policy, "spec.validation.policy")...)
+	}
+	if list.Score != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationScore(list)...)
+	}
+	if list.Inconclusive != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationInconclusive(list.Inconclusive)...)
+	}
+	if list.MaxValidationPeriod != nil || list.Confidence != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationConfidence(list)...)
+	}
+	if list.EarlySuccess != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationEarlySuccess(list.EarlySuccess)...)
+	}
+	if list.Chaos != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationChaos(list.Chaos)...)
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationEarlySuccess(earlySuccess *v1alpha1.KanaryStatefulsetSpecValidationEarlySuccess) []error {
+	var errs []error
+	if len(earlySuccess.Conditions) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.earlySuccess.conditions is not set"))
+	}
+	if earlySuccess.MinElapsed != nil && earlySuccess.MinElapsed.Duration < 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.earlySuccess.minElapsed should be positive"))
+	}
+	for i, c := range earlySuccess.Conditions {
+		if c.Query == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.earlySuccess.conditions[%d].query is not set", i))
+		}
+		switch c.Operator {
+		case v1alpha1.LessThanKanaryStatefulsetSpecValidationEarlySuccessOperator, v1alpha1.GreaterThanKanaryStatefulsetSpecValidationEarlySuccessOperator:
+		default:
+			errs = append(errs, fmt.Errorf("spec.validation.earlySuccess.conditions[%d].operator should be LessThan or GreaterThan, current value: %q", i, c.Operator))
+		}
+		if (c.Value == nil) == (c.BaselineQuery == "") {
+			errs = append(errs, fmt.Errorf("spec.validation.earlySuccess.conditions[%d] requires either value or baselineQuery", i))
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationChaos(chaos *v1alpha1.KanaryStatefulsetSpecValidationChaos) []error {
+	var errs []error
+	if len(chaos.Actions) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.chaos.actions is not set"))
+	}
+	if chaos.StartDelay != nil && chaos.StartDelay.Duration < 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.chaos.startDelay should be positive"))
+	}
+	names := map[string]bool{}
+	for i, a := range chaos.Actions {
+		if a.Name == "" || names[a.Name] {
+			errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].name should be set and unique", i))
+		}
+		names[a.Name] = true
+		if (a.PodKill == nil) == (a.HTTPFault == nil) {
+			errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d] requires either podKill or httpFault", i))
+		}
+		if a.PodKill != nil && a.PodKill.ReadyTimeout != nil && a.PodKill.ReadyTimeout.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].podKill.readyTimeout should be greater than 0", i))
+		}
+		if f := a.HTTPFault; f != nil {
+			if f.VirtualService == "" || f.Subset == "" {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault: virtualService and subset should be set", i))
+			}
+			if f.Delay == nil && f.AbortHTTPStatus == nil {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault: delay or abortHttpStatus should be set", i))
+			}
+			if f.Delay != nil && f.Delay.Duration <= 0 {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault.delay should be greater than 0", i))
+			}
+			if f.AbortHTTPStatus != nil && (*f.AbortHTTPStatus < 200 || *f.AbortHTTPStatus > 599) {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault.abortHttpStatus is not a valid http status: %d", i, *f.AbortHTTPStatus))
+			}
+			if f.Percentage != nil && (*f.Percentage <= 0 || *f.Percentage > 100) {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault.percentage should be in (0, 100]", i))
+			}
+			if f.Duration != nil && f.Duration.Duration <= 0 {
+				errs = append(errs, fmt.Errorf("spec.validation.chaos.actions[%d].httpFault.duration should be greater than 0", i))
+			}
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationConfidence(list *v1alpha1.KanaryStatefulsetSpecValidationList) []error {
+	var errs []error
+	if list.MaxValidationPeriod == nil {
+		return []error{fmt.Errorf("spec.validation.confidence requires spec.validation.maxValidationPeriod")}
+	}
+	if list.ValidationPeriod != nil && list.MaxValidationPeriod.Duration < list.ValidationPeriod.Duration {
+		errs = append(errs, fmt.Errorf("spec.validation.maxValidationPeriod should be greater than or equal to spec.validation.validationPeriod"))
+	}
+	confidence := list.Confidence
+	if confidence == nil {
+		return errs
+	}
+	if confidence.ExtensionPeriod != nil && confidence.ExtensionPeriod.Duration <= 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.confidence.extensionPeriod should be greater than 0"))
+	}
+	if requests := confidence.MinRequests; requests != nil {
+		if requests.Query == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.confidence.minRequests.query is not set"))
+		}
+		if requests.PrometheusService == "" && requests.URL == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.confidence.minRequests requires prometheusService or url"))
+		}
+		if requests.Min < 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.confidence.minRequests.min should be positive"))
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationInconclusive(i *v1alpha1.KanaryStatefulsetSpecValidationInconclusive) []error {
+	var errs []error
+	switch i.Action {
+	case "", v1alpha1.FailKanaryStatefulsetSpecValidationInconclusiveAction, v1alpha1.PassKanaryStatefulsetSpecValidationInconclusiveAction, v1alpha1.ExtendKanaryStatefulsetSpecValidationInconclusiveAction:
+	default:
+		errs = append(errs, fmt.Errorf("spec.validation.inconclusive.action should be Fail, Pass or Extend, current value: %q", i.Action))
+	}
+	if i.MaxExtensions != nil && *i.MaxExtensions < 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.inconclusive.maxExtensions should be positive"))
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationScore(list *v1alpha1.KanaryStatefulsetSpecValidationList) []error {
+	var errs []error
+	score := list.Score
+	if list.Policy != nil {
+		errs = append(errs, fmt.Errorf("spec.validation.score and spec.validation.policy can't be defined together"))
+	}
+	if score.Pass != nil && (*score.Pass < 0 || *score.Pass > 100) {
+		errs = append(errs, fmt.Errorf("spec.validation.score.pass should be between 0 and 100"))
+	}
+	if score.Marginal != nil && (*score.Marginal < 0 || *score.Marginal > 100) {
+		errs = append(errs, fmt.Errorf("spec.validation.score.marginal should be between 0 and 100"))
+	}
+	if score.Pass != nil && score.Marginal != nil && *score.Marginal > *score.Pass {
+		errs = append(errs, fmt.Errorf("spec.validation.score.marginal should be lower or equal to spec.validation.score.pass"))
+	}
+	switch score.MarginalAction {
+	case "", v1alpha1.ExtendValidationKanaryStatefulsetSpecValidationScoreMarginalAction:
+	case v1alpha1.ManualApprovalKanaryStatefulsetSpecValidationScoreMarginalAction:
+		hasManual := false
+		for _, item := range list.Items {
+			hasManual = hasManual || item.Manual != nil
+		}
+		if !hasManual {
+			errs = append(errs, fmt.Errorf("spec.validation.score.marginalAction=%s requires a manual validation item", score.MarginalAction))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("spec.validation.score.marginalAction should be ExtendValidation or ManualApproval, current value: %q", score.MarginalAction))
+	}
+	for i, item := range list.Items {
+		if item.Weight != nil && *item.Weight < 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.items[%d].weight should be positive", i))
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationPolicy(list *v1alpha1.KanaryStatefulsetSpecValidationList, policy *v1alpha1.KanaryStatefulsetSpecValidationPolicy, path string) []error {
+	var errs []error
+	operands := len(policy.Items) + len(policy.Groups)
+	if operands == 0 {
+		errs = append(errs, fmt.Errorf("%s should contain at least one item or group", path))
+	}
+	switch policy.Operator {
+	case v1alpha1.AndKanaryStatefulsetSpecValidationPolicyOperator, v1alpha1.OrKanaryStatefulsetSpecValidationPolicyOperator:
+	case v1alpha1.QuorumKanaryStatefulsetSpecValidationPolicyOperator:
+		if policy.Quorum == nil || *policy.Quorum < 1 || int(*policy.Quorum) > operands {
+			errs = append(errs, fmt.Errorf("%s.quorum should be between 1 and the number of operands (%d)", path, operands))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("%s.operator should be And, Or or Quorum, current value: %q", path, policy.Operator))
+	}
+	for _, name := range policy.Items {
+		found := 0
+		for _, item := range list.Items {
+			if item.Name != name {
+				continue
+			}
+			found++
+			if item.Advisory {
+				errs = append(errs, fmt.Errorf("%s.items: %q is an advisory item", path, name))
+			}
+		}
+		if found != 1 {
+			errs = append(errs, fmt.Errorf("%s.items: %q should match exactly one validation item name, found %d", path, name, found))
+		}
+	}
+	for i := range policy.Groups {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationPolicy(list, &policy.Groups[i], fmt.Sprintf("%s.groups[%d]", path, i))...)
+	}
 	return errs
 }
 
 func validateKanaryStatefulsetSpecValidation(v *v1alpha1.KanaryStatefulsetSpecValidation) []error {
 	var errs []error
-	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil {
+	if v.Manual == nil && v.LabelWatch == nil && v.PromQL == nil && v.Metrics == nil && v.ResourceUsage == nil && v.Logs == nil && v.Events == nil && v.Job == nil && v.Alerts == nil && v.SLO == nil {
 		errs = append(errs, fmt.Errorf("spec.validation not defined: %v", v))
 	}
+	if v.Manual != nil && v.Manual.Approvers != nil && len(v.Manual.Approvers.Users) == 0 && len(v.Manual.Approvers.Groups) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.manual.approvers should contain at least one user or group"))
+	}
+	if v.PromQL != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationPromQL(v.PromQL)...)
+	}
+	if v.Metrics != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationMetrics(v.Metrics)...)
+	}
+	if v.ResourceUsage != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationResourceUsage(v.ResourceUsage)...)
+	}
+	if v.Logs != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationLogs(v.Logs)...)
+	}
+	if v.Events != nil && v.Events.MaxOccurrences != nil && *v.Events.MaxOccurrences < 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.events.maxOccurrences should be positive"))
+	}
+	if v.Job != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationJob(v.Job)...)
+	}
+	if v.Alerts != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationAlerts(v.Alerts)...)
+	}
+	if v.SLO != nil {
+		errs = append(errs, validateKanaryStatefulsetSpecValidationSLO(v.SLO)...)
+	}
+	if v.OnProviderError != nil {
+		switch v.OnProviderError.Policy {
+		case "", v1alpha1.FailKanaryStatefulsetSpecValidationProviderErrorPolicy, v1alpha1.PauseKanaryStatefulsetSpecValidationProviderErrorPolicy, v1alpha1.IgnoreKanaryStatefulsetSpecValidationProviderErrorPolicy:
+		default:
+			errs = append(errs, fmt.Errorf("spec.validation.onProviderError.policy should be Fail, Pause or Ignore, current value: %q", v.OnProviderError.Policy))
+		}
+		if v.OnProviderError.MaxOutage != nil && v.OnProviderError.MaxOutage.Duration < 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.onProviderError.maxOutage should be positive"))
+		}
+	}
+
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationPromQL(pq *v1alpha1.KanaryStatefulsetSpecValidationPromQL) []error {
+	var errs []error
+	if pq.URL != "" {
+		if u, err := url.Parse(pq.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.url is not a valid http(s) url: %s", pq.URL))
+		}
+	}
+	if pq.Auth != nil && pq.Auth.TLS != nil && (pq.Auth.TLS.Cert == nil) != (pq.Auth.TLS.Key == nil) {
+		errs = append(errs, fmt.Errorf("spec.validation.promQL.auth.tls: cert and key should be defined together"))
+	}
+	if pq.Trend != nil {
+		if pq.Trend.Limit == nil && pq.Trend.MaxSlopeDeviationPercent == nil {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.trend: limit or maxSlopeDeviationPercent should be defined"))
+		}
+		if pq.ValueInRange != nil || pq.DiscreteValueOutOfList != nil || pq.ContinuousValueDeviation != nil {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.trend can't be combined with another analyser"))
+		}
+		if pq.AllPodsQuery {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.trend requires a serie per pod, allPodsQuery should be false"))
+		}
+	}
+	if pq.Exclusions != nil {
+		if pq.Exclusions.WarmUp != nil && pq.Exclusions.WarmUp.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.exclusions.warmUp should be positive"))
+		}
+		if (pq.Exclusions.MinRequests == nil) != (pq.Exclusions.RequestsQuery == "") {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.exclusions: minRequests and requestsQuery should be defined together"))
+		}
+		if pq.Exclusions.Labels != nil {
+			if _, err := metav1.LabelSelectorAsSelector(pq.Exclusions.Labels); err != nil {
+				errs = append(errs, fmt.Errorf("spec.validation.promQL.exclusions.labels is not a valid label selector: %v", err))
+			}
+		}
+	}
+	names := map[string]bool{}
+	for _, d := range pq.Dependencies {
+		if d.Name == "" || d.Query == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.dependencies: name and query should be defined"))
+			continue
+		}
+		if names[d.Name] {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.dependencies: duplicated name %s", d.Name))
+		}
+		names[d.Name] = true
+		if d.BaselineWindow != nil && d.BaselineWindow.Duration <= 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.dependencies[%s].baselineWindow should be positive", d.Name))
+		}
+		if (d.MaxIncreasePercent != nil && *d.MaxIncreasePercent < 0) || (d.MinIncrease != nil && *d.MinIncrease < 0) {
+			errs = append(errs, fmt.Errorf("spec.validation.promQL.dependencies[%s]: maxIncreasePercent and minIncrease should not be negative", d.Name))
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationMetrics(m *v1alpha1.KanaryStatefulsetSpecValidationMetrics) []error {
+	var errs []error
+	if m.HTTP == nil {
+		errs = append(errs, fmt.Errorf("spec.validation.metrics: a provider should be defined"))
+	} else {
+		if m.HTTP.URL == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.metrics.http.url is not defined"))
+		}
+		if m.HTTP.ItemsPath == "" || m.HTTP.ValuePath == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.metrics.http: itemsPath and valuePath should be defined"))
+		}
+		if m.HTTP.PodNamePath == "" && !m.HTTP.AllPodsQuery {
+			errs = append(errs, fmt.Errorf("spec.validation.metrics.http.podNamePath should be defined if allPodsQuery is false"))
+		}
+	}
+	if m.ValueInRange == nil && m.DiscreteValueOutOfList == nil && m.ContinuousValueDeviation == nil {
+		errs = append(errs, fmt.Errorf("spec.validation.metrics: an analyser should be defined (valueInRange, discreteValueOutOfList or continuousValueDeviation)"))
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationResourceUsage(r *v1alpha1.KanaryStatefulsetSpecValidationResourceUsage) []error {
+	var errs []error
+	if r.CPU == nil && r.Memory == nil {
+		errs = append(errs, fmt.Errorf("spec.validation.resourceUsage: cpu or memory should be defined"))
+	}
+	for _, item := range []struct {
+		name string
+		t    *v1alpha1.ResourceUsageThreshold
+	}{{"cpu", r.CPU}, {"memory", r.Memory}} {
+		name, t := item.name, item.t
+		if t == nil {
+			continue
+		}
+		if t.MaxIncreasePercent == nil && t.Limit == nil {
+			errs = append(errs, fmt.Errorf("spec.validation.resourceUsage.%s: maxIncreasePercent or limit should be defined", name))
+		}
+		if t.MaxIncreasePercent != nil && *t.MaxIncreasePercent < 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.resourceUsage.%s.maxIncreasePercent should be positive", name))
+		}
+	}
+	return errs
+}
 
+func validateKanaryStatefulsetSpecValidationLogs(l *v1alpha1.KanaryStatefulsetSpecValidationLogs) []error {
+	var errs []error
+	if len(l.Patterns) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.logs.patterns should contain at least one pattern"))
+	}
+	for _, pattern := range l.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			errs = append(errs, fmt.Errorf("spec.validation.logs.patterns: invalid pattern %q: %v", pattern, err))
+		}
+	}
+	if l.MaxLinesPerMinute == nil && l.MaxIncreasePercent == nil {
+		errs = append(errs, fmt.Errorf("spec.validation.logs: maxLinesPerMinute or maxIncreasePercent should be defined"))
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationJob(j *v1alpha1.KanaryStatefulsetSpecValidationJob) []error {
+	var errs []error
+	podSpec := j.Template.Spec.Template.Spec
+	if len(podSpec.Containers) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.job.template should contain at least one container"))
+	}
+	if podSpec.RestartPolicy == corev1.RestartPolicyAlways {
+		errs = append(errs, fmt.Errorf("spec.validation.job.template: restartPolicy should be Never or OnFailure"))
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationAlerts(a *v1alpha1.KanaryStatefulsetSpecValidationAlerts) []error {
+	var errs []error
+	if a.URL == "" {
+		errs = append(errs, fmt.Errorf("spec.validation.alerts.url should be defined"))
+	} else if u, err := url.Parse(a.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		errs = append(errs, fmt.Errorf("spec.validation.alerts.url is not a valid http(s) url: %s", a.URL))
+	}
+	if len(a.Matchers) == 0 {
+		errs = append(errs, fmt.Errorf("spec.validation.alerts.matchers should contain at least one matcher"))
+	}
+	for i, matcher := range a.Matchers {
+		if matcher.Name == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.alerts.matchers[%d].name should be defined", i))
+		}
+	}
+	return errs
+}
+
+func validateKanaryStatefulsetSpecValidationSLO(slo *v1alpha1.KanaryStatefulsetSpecValidationSLO) []error {
+	var errs []error
+	names := []string{"successSelector", "totalSelector"}
+	for i, selector := range []string{slo.SuccessSelector, slo.TotalSelector} {
+		name := names[i]
+		if selector == "" {
+			errs = append(errs, fmt.Errorf("spec.validation.slo.%s should be defined", name))
+		} else if strings.ContainsAny(selector, "([") {
+			errs = append(errs, fmt.Errorf("spec.validation.slo.%s should be a metric selector, without function nor range: %s", name, selector))
+		}
+	}
+	if slo.Objective <= 0 || slo.Objective >= 100 {
+		errs = append(errs, fmt.Errorf("spec.validation.slo.objective should be between 0 and 100 (excluded), current value: %v", slo.Objective))
+	}
+	for i, w := range slo.Windows {
+		if w.Short.Duration <= 0 || w.Long.Duration < w.Short.Duration {
+			errs = append(errs, fmt.Errorf("spec.validation.slo.windows[%d] short window should be greater than 0 and lower or equal to the long window", i))
+		}
+		if w.Factor <= 0 {
+			errs = append(errs, fmt.Errorf("spec.validation.slo.windows[%d].factor should be greater than 0", i))
+		}
+	}
 	return errs
 }
@@ -24,7 +24,6 @@ import (
 	appsv1beta1 "k8s.io/api/apps/v1beta1"
 
 	"github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
-	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/utils"
 )
 
 var (
@@ -33,6 +32,8 @@ var (
 	%[1]s generate foo
 	# generate KanaryStatefulset from "foo" Deployment and service "bar"
 	%[1]s generate foo --service bar
+	# generate KanaryStatefulset from "foo" Deployment with an slo validation of 99.9% of successful requests
+	%[1]s generate foo --validation-slo 99.9
 `
 )
 
@@ -48,6 +49,7 @@ const (
 	argValidationLabelWatchDeployment = "validation-labelwatch-dep"
 	argValidationPromQLIstioQuantile  = "validation-promql-istio-quantile"
 	argValidationPromQLIstioSuccess   = "validation-promql-istio-success"
+	argValidationSLO                  = "validation-slo"
 )
 
 type outputFormat string
@@ -108,6 +110,7 @@ type generateOptions struct {
 	userValidationLabelWatchDeployment string
 	userValidationPromQLIstioQuantile  string
 	userValidationPromQLIstioSuccess   float64
+	userValidationSLO                  float64
 	userOutputFormat                   outputFormatArg
 }
 
@@ -149,6 +152,7 @@ func NewCmdGenerate(streams genericclioptions.IOStreams) *cobra.Command {
 	cmd.Flags().StringVarP(&o.userValidationLabelWatchDeployment, argValidationLabelWatchDeployment, "", "", "kanary validation labelwatch: string representation of label-selector for deployment invalidation")
 	cmd.Flags().StringVarP(&o.userValidationPromQLIstioQuantile, argValidationPromQLIstioQuantile, "", "", "kanary validation using promql on top of istio response time monitoring. format(percentile 90 lower or equal 150 ms) P90<150  ")
 	cmd.Flags().Float64VarP(&o.userValidationPromQLIstioSuccess, argValidationPromQLIstioSuccess, "", -1, "kanary validation using promql on top of istio success rate. ")
+	cmd.Flags().Float64VarP(&o.userValidationSLO, argValidationSLO, "", -1, "kanary validation using the multi-window burn rate of an slo on top of istio requests. format: objective in percent, for example 99.9")
 
 	cmd.Flags().DurationVarP(&o.userValidationPeriod, argValidationPeriod, "", 15*time.Minute, "kanary validation periode")
 	cmd.Flags().VarP(&o.userOutputFormat, argOutputFormat, "o", "generation output format (json or yaml)")
@@ -256,7 +260,7 @@ func (o *generateOptions) Run() error {
 		return fmt.Errorf("wrong value for 'traffic' parameter, current value:%s", o.userTraffic)
 	}
 
-	if o.userValidationLabelWatchDeployment == "" && o.userValidationLabelWatchPod == "" && o.userValidationPromQLIstioQuantile == "" && o.userValidationPromQLIstioSuccess >= 0 {
+	if o.userValidationLabelWatchDeployment == "" && o.userValidationLabelWatchPod == "" && o.userValidationPromQLIstioQuantile == "" && o.userValidationPromQLIstioSuccess >= 0 && o.userValidationSLO < 0 {
 		newKanaryStatefulset.Spec.Validations.Items = append(newKanaryStatefulset.Spec.Validations.Items, v1alpha1.KanaryStatefulsetSpecValidation{Manual: &v1alpha1.KanaryStatefulsetSpecValidationManual{}})
 	}
 
@@ -295,7 +299,7 @@ func (o *generateOptions) Run() error {
 		d := time.Duration(ms) * time.Millisecond
 
 		newKanaryStatefulset.Spec.Validations.Items = append(newKanaryStatefulset.Spec.Validations.Items, v1alpha1.KanaryStatefulsetSpecValidation{PromQL: &v1alpha1.KanaryStatefulsetSpecValidationPromQL{
-			Query:             "histogram_quantile(0." + p + ", sum(rate(istio_request_duration_seconds_bucket{reporter=\"destination\",destination_workload=\"" + utils.GetCanaryDeploymentName(newKanaryStatefulset) + "\"}[1m])) by (le))",
+			Query:             "histogram_quantile(0." + p + ", sum(rate(istio_request_duration_seconds_bucket{reporter=\"destination\",destination_workload=\"{{.CanaryDeploymentName}}\"}[1m])) by (le))",
 			PrometheusService: "prometheus.istio-system:9090",
 			AllPodsQuery:      true,
 			ValueInRange: &v1alpha1.ValueInRange{
@@ -308,7 +312,7 @@ func (o *generateOptions) Run() error {
 
 	if o.userValidationPromQLIstioSuccess >= 0 {
 		newKanaryStatefulset.Spec.Validations.Items = append(newKanaryStatefulset.Spec.Validations.Items, v1alpha1.KanaryStatefulsetSpecValidation{PromQL: &v1alpha1.KanaryStatefulsetSpecValidationPromQL{
-			Query:             "sum(rate(istio_requests_total{reporter=\"destination\", destination_workload_namespace=~\"" + o.userNamespace + "\", destination_workload=~\"" + utils.GetCanaryDeploymentName(newKanaryStatefulset) + "\",response_code!~\"5.*\"}[1m]))/sum(rate(istio_requests_total{reporter=\"destination\", destination_workload_namespace=~\"" + o.userNamespace + "\", destination_workload=~\"" + utils.GetCanaryDeploymentName(newKanaryStatefulset) + "\"}[1m]))",
+			Query:             "sum(rate(istio_requests_total{reporter=\"destination\", destination_workload_namespace=~\"{{.Namespace}}\", destination_workload=~\"{{.CanaryDeploymentName}}\",response_code!~\"5.*\"}[1m]))/sum(rate(istio_requests_total{reporter=\"destination\", destination_workload_namespace=~\"{{.Namespace}}\", destination_workload=~\"{{.CanaryDeploymentName}}\"}[1m]))",
 			PrometheusService: "prometheus.istio-system:9090",
 			AllPodsQuery:      true,
 			ValueInRange: &v1alpha1.ValueInRange{
@@ -320,6 +324,19 @@ func (o *generateOptions) Run() error {
 		newKanaryStatefulset.Spec.Validations.MaxIntervalPeriod = &metav1.Duration{Duration: 10 * time.Second}
 	}
 
+	if o.userValidationSLO >= 0 {
+		if o.userValidationSLO == 0 || o.userValidationSLO >= 100 {
+			return fmt.Errorf("Bad value for %s, should be an objective between 0 and 100 (excluded), like 99.9", argValidationSLO)
+		}
+		newKanaryStatefulset.Spec.Validations.Items = append(newKanaryStatefulset.Spec.Validations.Items, v1alpha1.KanaryStatefulsetSpecValidation{SLO: &v1alpha1.KanaryStatefulsetSpecValidationSLO{
+			PrometheusService: "prometheus.istio-system:9090",
+			AllPodsQuery:      true,
+			SuccessSelector:   "istio_requests_total{reporter=\"destination\",destination_workload_namespace=\"{{.Namespace}}\",destination_workload=\"{{.CanaryDeploymentName}}\",response_code!~\"5.*\"}",
+			TotalSelector:     "istio_requests_total{reporter=\"destination\",destination_workload_namespace=\"{{.Namespace}}\",destination_workload=\"{{.CanaryDeploymentName}}\"}",
+			Objective:         o.userValidationSLO,
+		}})
+	}
+
 	if o.userDryRun {
 		newKanaryStatefulset.Spec.Validations.NoUpdate = true
 	}
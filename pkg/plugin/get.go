@@ -140,7 +140,7 @@ func (o *GetOptions) Run() error {
 
 	table := newTable(o.Out)
 	for _, item := range kanaryList.Items {
-		data := []string{item.Namespace, item.Name, getStatus(&item), item.Spec.DeploymentName, item.Spec.ServiceName, getScale(&item), getTraffic(&item), getValidation(&item), getDuration(&item)}
+		data := []string{item.Namespace, item.Name, getStatus(&item), item.Spec.DeploymentName, item.Spec.ServiceName, getScale(&item), getTraffic(&item), getValidation(&item), getScore(&item), getDuration(&item)}
 		table.Append(data)
 	}
 
@@ -160,6 +160,11 @@ func getTraffic(kd *v1alpha1.KanaryStatefulset) string {
 func getValidation(kd *v1alpha1.KanaryStatefulset) string {
 	return kd.Status.Report.Validation
 }
+
+func getScore(kd *v1alpha1.KanaryStatefulset) string {
+	return kd.Status.Report.Score
+}
+
 func getStatus(kd *v1alpha1.KanaryStatefulset) string {
 	return kd.Status.Report.Status
 }
@@ -172,13 +177,19 @@ func getDuration(kd *v1alpha1.KanaryStatefulset) string {
 	if kd.Spec.Validations.ValidationPeriod != nil {
 		duration += kd.Spec.Validations.ValidationPeriod.Duration
 	}
+	if kd.Status.ValidationDeadline != nil {
+		duration = kd.Status.ValidationDeadline.Sub(kd.ObjectMeta.CreationTimestamp.Time)
+	}
 	since := time.Since(kd.ObjectMeta.CreationTimestamp.Time)
+	if n := len(kd.Status.ConfidenceExtensions); n > 0 {
+		return fmt.Sprintf("%s/%s (extended: %s)", since, duration, kd.Status.ConfidenceExtensions[n-1].Reason)
+	}
 	return fmt.Sprintf("%s/%s", since, duration)
 }
 
 func newTable(out io.Writer) *tablewriter.Table {
 	table := tablewriter.NewWriter(out)
-	table.SetHeader([]string{"Namespace", "Name", "Status", "Deployment", "Service", "Scale", "Traffic", "Validation", "Duration"})
+	table.SetHeader([]string{"Namespace", "Name", "Status", "Deployment", "Service", "Scale", "Traffic", "Validation", "Score", "Duration"})
 	table.SetBorders(tablewriter.Border{Left: false, Top: false, Right: false, Bottom: false})
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetRowLine(false)
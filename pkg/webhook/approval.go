@@ -0,0 +1,262 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattbaird/jsonpatch"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies/validation"
+)
+
+var _ admission.Handler = &ApprovalHandler{}
+
+// ApprovalHandler admission handler of the manual approval decisions. When approvers are configured on a manual
+// validation item, only the allowed users and groups can change the decision (approval annotation or manual status)
+// and the allow-list. The approver and the time of each decision, approval annotation or manual status, are always
+// recorded in annotations by the handler, the values provided by the client are overwritten, and the recorded
+// decisions are signed with the key of the handler.
+type ApprovalHandler struct {
+	key []byte
+	// now for test purposes
+	now func() time.Time
+}
+
+// NewApprovalHandler returns new ApprovalHandler instance, the key signs the recorded decisions
+func NewApprovalHandler(key []byte) *ApprovalHandler {
+	return &ApprovalHandler{key: key, now: time.Now}
+}
+
+// Handle implements admission.Handler
+func (h *ApprovalHandler) Handle(ctx context.Context, req atypes.Request) atypes.Response {
+	ar := req.AdmissionRequest
+	if ar.Operation != admissionv1beta1.Create && ar.Operation != admissionv1beta1.Update {
+		return admission.ValidationResponse(true, "")
+	}
+
+	kd := &kanaryv1alpha1.KanaryStatefulset{}
+	if err := json.Unmarshal(ar.Object.Raw, kd); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	oldKd := &kanaryv1alpha1.KanaryStatefulset{}
+	if ar.Operation == admissionv1beta1.Update {
+		if err := json.Unmarshal(ar.OldObject.Raw, oldKd); err != nil {
+			return admission.ErrorResponse(http.StatusBadRequest, err)
+		}
+	}
+	// the namespace is not always provided in the object at creation
+	kd.Namespace, oldKd.Namespace = ar.Namespace, ar.Namespace
+
+	// the allow-list of the current object applies, the one of the new object is used at creation
+	approvers := getApprovers(oldKd)
+	if ar.Operation == admissionv1beta1.Create {
+		approvers = getApprovers(kd)
+	}
+	if approvers != nil && isDecisionChanged(oldKd, kd) && !IsApprover(approvers, ar.UserInfo) {
+		return admission.ValidationResponse(false, fmt.Sprintf("user %s is not allowed to take the manual approval decision of the KanaryStatefulset %s", ar.UserInfo.Username, kd.Name))
+	}
+
+	now := metav1.NewTime(h.now().UTC().Truncate(time.Second))
+	oldSigned := validation.IsApprovalSigned(h.key, oldKd)
+	approvedBy, approvedAt := ar.UserInfo.Username, now.Format(time.RFC3339)
+	if !isApprovalChanged(oldKd, kd) {
+		// the recorded approver can only be changed by a new decision, if it has been recorded by the webhook
+		approvedBy, approvedAt = "", ""
+		if oldSigned {
+			approvedBy = oldKd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey]
+			approvedAt = oldKd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey]
+		}
+	}
+	if kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey] == "" {
+		approvedBy, approvedAt = "", ""
+	}
+
+	var oldDecisions []kanaryv1alpha1.KanaryStatefulsetApproval
+	if oldSigned {
+		oldDecisions = validation.ParseManualDecisions(oldKd)
+	}
+	decisions, err := newManualDecisions(kd, oldDecisions, ar.UserInfo.Username, now)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+
+	recorded := kd.DeepCopy()
+	values := map[string]string{
+		kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey:      approvedBy,
+		kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey:      approvedAt,
+		kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decisions,
+	}
+	setAnnotations(recorded, values)
+	values[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = ""
+	if approvedBy != "" || decisions != "" {
+		values[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = validation.SignApprovals(h.key, recorded)
+	}
+
+	return atypes.Response{
+		Patches: newAnnotationsPatch(kd, values),
+		Response: &admissionv1beta1.AdmissionResponse{
+			Allowed:   true,
+			PatchType: func() *admissionv1beta1.PatchType { pt := admissionv1beta1.PatchTypeJSONPatch; return &pt }(),
+		},
+	}
+}
+
+// newManualDecisions returns the decisions taken with the status of the manual items (JSON), empty without decision.
+// A decision keeps its user and time while the status of the item is unchanged, a new status is recorded with the user of the request.
+func newManualDecisions(kd *kanaryv1alpha1.KanaryStatefulset, oldDecisions []kanaryv1alpha1.KanaryStatefulsetApproval, user string, now metav1.Time) (string, error) {
+	oldByID := map[string]kanaryv1alpha1.KanaryStatefulsetApproval{}
+	for _, decision := range oldDecisions {
+		oldByID[decision.Item] = decision
+	}
+	var decisions []kanaryv1alpha1.KanaryStatefulsetApproval
+	for i, item := range kd.Spec.Validations.Items {
+		if item.Manual == nil || (item.Manual.Status != kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus && item.Manual.Status != kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus) {
+			continue
+		}
+		id := validation.GetManualItemID(&kd.Spec.Validations, i)
+		if decision, ok := oldByID[id]; ok && decision.Decision == item.Manual.Status {
+			decisions = append(decisions, decision)
+			continue
+		}
+		decisions = append(decisions, kanaryv1alpha1.KanaryStatefulsetApproval{Approver: user, Decision: item.Manual.Status, Time: now, Item: id})
+	}
+	if len(decisions) == 0 {
+		return "", nil
+	}
+	raw, err := json.Marshal(decisions)
+	return string(raw), err
+}
+
+// IsApprover returns true if the user, or one of its groups, is in the allow-list
+func IsApprover(approvers *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers, user authenticationv1.UserInfo) bool {
+	for _, name := range approvers.Users {
+		if name == user.Username {
+			return true
+		}
+	}
+	for _, group := range approvers.Groups {
+		for _, userGroup := range user.Groups {
+			if group == userGroup {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// getApprovers returns the union of the allow-lists of the manual validation items, nil if no allow-list is configured
+func getApprovers(kd *kanaryv1alpha1.KanaryStatefulset) *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers {
+	var approvers *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers
+	for _, item := range kd.Spec.Validations.Items {
+		if item.Manual == nil || item.Manual.Approvers == nil {
+			continue
+		}
+		if approvers == nil {
+			approvers = &kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers{}
+		}
+		approvers.Users = append(approvers.Users, item.Manual.Approvers.Users...)
+		approvers.Groups = append(approvers.Groups, item.Manual.Approvers.Groups...)
+	}
+	return approvers
+}
+
+// isDecisionChanged returns true if the request changes the approval annotations, the status of a manual item or an allow-list
+func isDecisionChanged(oldKd, kd *kanaryv1alpha1.KanaryStatefulset) bool {
+	for _, key := range []string{
+		kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey,
+		kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey,
+		kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey,
+		kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey,
+		kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey,
+		kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey,
+	} {
+		if oldKd.Annotations[key] != kd.Annotations[key] {
+			return true
+		}
+	}
+	return !apiequality.Semantic.DeepEqual(getManualItems(oldKd), getManualItems(kd))
+}
+
+func getManualItems(kd *kanaryv1alpha1.KanaryStatefulset) []kanaryv1alpha1.KanaryStatefulsetSpecValidationManual {
+	items := []kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{}
+	for _, item := range kd.Spec.Validations.Items {
+		if item.Manual != nil {
+			items = append(items, *item.Manual)
+		}
+	}
+	return items
+}
+
+// isApprovalChanged returns true if the request takes a new decision with the approval annotations
+func isApprovalChanged(oldKd, kd *kanaryv1alpha1.KanaryStatefulset) bool {
+	return oldKd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey] != kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey] ||
+		oldKd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey] != kd.Annotations[kanaryv1alpha1.KanaryStatefulsetApprovalCommentAnnotationKey]
+}
+
+// setAnnotations sets the annotation values, an empty value removes the annotation
+func setAnnotations(kd *kanaryv1alpha1.KanaryStatefulset, values map[string]string) {
+	for key, value := range values {
+		if value == "" {
+			delete(kd.Annotations, key)
+			continue
+		}
+		if kd.Annotations == nil {
+			kd.Annotations = map[string]string{}
+		}
+		kd.Annotations[key] = value
+	}
+}
+
+// newAnnotationsPatch returns the patch operations setting the annotation values, an empty value removes the annotation
+func newAnnotationsPatch(kd *kanaryv1alpha1.KanaryStatefulset, values map[string]string) []jsonpatch.JsonPatchOperation {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if kd.Annotations == nil {
+		annotations := map[string]string{}
+		for _, key := range keys {
+			if values[key] != "" {
+				annotations[key] = values[key]
+			}
+		}
+		if len(annotations) == 0 {
+			return nil
+		}
+		return []jsonpatch.JsonPatchOperation{{Operation: "add", Path: "/metadata/annotations", Value: annotations}}
+	}
+
+	var patches []jsonpatch.JsonPatchOperation
+	for _, key := range keys {
+		current, ok := kd.Annotations[key]
+		switch value := values[key]; {
+		case value == "" && ok:
+			patches = append(patches, jsonpatch.JsonPatchOperation{Operation: "remove", Path: getAnnotationPath(key)})
+		case value == "" || (ok && current == value):
+		default:
+			patches = append(patches, jsonpatch.JsonPatchOperation{Operation: "add", Path: getAnnotationPath(key), Value: value})
+		}
+	}
+	return patches
+}
+
+// getAnnotationPath returns the JSON pointer of an annotation, "/" is escaped as "~1"
+func getAnnotationPath(key string) string {
+	return "/metadata/annotations/" + strings.Replace(key, "/", "~1", -1)
+}
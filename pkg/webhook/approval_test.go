@@ -0,0 +1,274 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mattbaird/jsonpatch"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	atypes "sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+
+	kanaryv1alpha1 "github.com/k8s-kanary/kanary/pkg/apis/kanary/v1alpha1"
+	"github.com/k8s-kanary/kanary/pkg/controller/kanarystatefulset/strategies/validation"
+)
+
+func TestApprovalHandler_Handle(t *testing.T) {
+	now := time.Date(2019, 4, 1, 10, 0, 0, 0, time.UTC)
+	key := []byte("webhook-key")
+	approvers := &kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers{Users: []string{"alice"}, Groups: []string{"release-managers"}}
+
+	newKd := func(approvers *kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers, annotations map[string]string) *kanaryv1alpha1.KanaryStatefulset {
+		kd := &kanaryv1alpha1.KanaryStatefulset{}
+		kd.Name = "foo"
+		kd.Namespace = "bar"
+		kd.Annotations = annotations
+		kd.Spec.Validations.Items = []kanaryv1alpha1.KanaryStatefulsetSpecValidation{
+			{Manual: &kanaryv1alpha1.KanaryStatefulsetSpecValidationManual{Approvers: approvers}},
+		}
+		return kd
+	}
+	// sign returns the signature of the decision recorded with the annotations
+	sign := func(annotations map[string]string) string {
+		return validation.SignApprovals(key, newKd(nil, annotations))
+	}
+	// signed returns the annotations with their signature
+	signed := func(annotations map[string]string) map[string]string {
+		annotations[kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey] = sign(annotations)
+		return annotations
+	}
+	approved := func() map[string]string {
+		return map[string]string{
+			kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:   "valid",
+			kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "alice",
+			kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey: "2019-04-01T09:00:00Z",
+		}
+	}
+	// withStatus sets the status of the manual item
+	withStatus := func(kd *kanaryv1alpha1.KanaryStatefulset, status kanaryv1alpha1.KanaryStatefulsetSpecValidationManualStatus) *kanaryv1alpha1.KanaryStatefulset {
+		kd.Spec.Validations.Items[0].Manual.Status = status
+		return kd
+	}
+	decidedByAlice := `[{"approver":"alice","decision":"valid","time":"2019-04-01T10:00:00Z","item":"items[0]"}]`
+	signaturePath := "/metadata/annotations/kanary.k8s-operators.dev~1approval-signature"
+	approvedAtPath := "/metadata/annotations/kanary.k8s-operators.dev~1approved-at"
+	approvedByPath := "/metadata/annotations/kanary.k8s-operators.dev~1approved-by"
+
+	tests := []struct {
+		name        string
+		oldKd       *kanaryv1alpha1.KanaryStatefulset
+		kd          *kanaryv1alpha1.KanaryStatefulset
+		user        authenticationv1.UserInfo
+		wantAllowed bool
+		wantPatches []jsonpatch.JsonPatchOperation
+	}{
+		{
+			name:        "no approvers",
+			oldKd:       newKd(nil, nil),
+			kd:          newKd(nil, map[string]string{kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey: "valid"}),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: signaturePath, Value: sign(map[string]string{
+					kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:   "valid",
+					kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "bob",
+					kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey: "2019-04-01T10:00:00Z",
+				})},
+				{Operation: "add", Path: approvedAtPath, Value: "2019-04-01T10:00:00Z"},
+				{Operation: "add", Path: approvedByPath, Value: "bob"},
+			},
+		},
+		{
+			name:  "approver forged without approvers",
+			oldKd: newKd(nil, signed(approved())),
+			kd: newKd(nil, func() map[string]string {
+				annotations := signed(approved())
+				annotations[kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey] = "mallory"
+				return annotations
+			}()),
+			user:        authenticationv1.UserInfo{Username: "mallory"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: approvedByPath, Value: "alice"},
+			},
+		},
+		{
+			name:  "recorded annotations forged without decision",
+			oldKd: newKd(nil, nil),
+			kd: newKd(nil, map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "mallory",
+				kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey: "2019-04-01T09:00:00Z",
+			}),
+			user:        authenticationv1.UserInfo{Username: "mallory"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "remove", Path: approvedAtPath},
+				{Operation: "remove", Path: approvedByPath},
+			},
+		},
+		{
+			name:  "decision not recorded by the webhook",
+			oldKd: newKd(nil, approved()),
+			kd: newKd(nil, func() map[string]string {
+				annotations := approved()
+				annotations["foo"] = "bar"
+				return annotations
+			}()),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "remove", Path: approvedAtPath},
+				{Operation: "remove", Path: approvedByPath},
+			},
+		},
+		{
+			name:        "decision unchanged",
+			oldKd:       newKd(approvers, nil),
+			kd:          newKd(approvers, map[string]string{"foo": "bar"}),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: true,
+		},
+		{
+			name:        "user not allowed",
+			oldKd:       newKd(approvers, nil),
+			kd:          newKd(approvers, map[string]string{kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey: "valid"}),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: false,
+		},
+		{
+			name:        "allow-list change not allowed",
+			oldKd:       newKd(approvers, nil),
+			kd:          newKd(&kanaryv1alpha1.KanaryStatefulsetSpecValidationManualApprovers{Users: []string{"bob"}}, nil),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: false,
+		},
+		{
+			name:        "approved by a user",
+			oldKd:       newKd(approvers, map[string]string{}),
+			kd:          newKd(approvers, map[string]string{kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey: "valid"}),
+			user:        authenticationv1.UserInfo{Username: "alice"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: signaturePath, Value: sign(map[string]string{
+					kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:   "valid",
+					kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "alice",
+					kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey: "2019-04-01T10:00:00Z",
+				})},
+				{Operation: "add", Path: approvedAtPath, Value: "2019-04-01T10:00:00Z"},
+				{Operation: "add", Path: approvedByPath, Value: "alice"},
+			},
+		},
+		{
+			name:  "rejected by a group member",
+			oldKd: newKd(approvers, signed(approved())),
+			kd: newKd(approvers, map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:   "invalid",
+				kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "alice",
+			}),
+			user:        authenticationv1.UserInfo{Username: "carol", Groups: []string{"release-managers"}},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: signaturePath, Value: sign(map[string]string{
+					kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey:   "invalid",
+					kanaryv1alpha1.KanaryStatefulsetApprovedByAnnotationKey: "carol",
+					kanaryv1alpha1.KanaryStatefulsetApprovedAtAnnotationKey: "2019-04-01T10:00:00Z",
+				})},
+				{Operation: "add", Path: approvedAtPath, Value: "2019-04-01T10:00:00Z"},
+				{Operation: "add", Path: approvedByPath, Value: "carol"},
+			},
+		},
+		{
+			name:        "manual status changed",
+			oldKd:       newKd(approvers, nil),
+			kd:          withStatus(newKd(approvers, nil), kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus),
+			user:        authenticationv1.UserInfo{Username: "alice"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: "/metadata/annotations", Value: map[string]string{
+					kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey:   decidedByAlice,
+					kanaryv1alpha1.KanaryStatefulsetApprovalSignatureAnnotationKey: sign(map[string]string{kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decidedByAlice}),
+				}},
+			},
+		},
+		{
+			name: "manual status unchanged",
+			oldKd: withStatus(newKd(nil, signed(map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decidedByAlice,
+			})), kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus),
+			kd: withStatus(newKd(nil, signed(map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decidedByAlice,
+				"foo": "bar",
+			})), kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: true,
+		},
+		{
+			name: "manual status reverted",
+			oldKd: withStatus(newKd(nil, signed(map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decidedByAlice,
+			})), kanaryv1alpha1.ValidKanaryStatefulsetSpecValidationManualStatus),
+			kd: withStatus(newKd(nil, signed(map[string]string{
+				kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: decidedByAlice,
+			})), kanaryv1alpha1.InvalidKanaryStatefulsetSpecValidationManualStatus),
+			user:        authenticationv1.UserInfo{Username: "bob"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "add", Path: signaturePath, Value: sign(map[string]string{
+					kanaryv1alpha1.KanaryStatefulsetManualDecisionsAnnotationKey: `[{"approver":"bob","decision":"invalid","time":"2019-04-01T10:00:00Z","item":"items[0]"}]`,
+				})},
+				{Operation: "add", Path: "/metadata/annotations/kanary.k8s-operators.dev~1manual-decisions", Value: `[{"approver":"bob","decision":"invalid","time":"2019-04-01T10:00:00Z","item":"items[0]"}]`},
+			},
+		},
+		{
+			name:  "approval removed",
+			oldKd: newKd(approvers, signed(approved())),
+			kd: newKd(approvers, func() map[string]string {
+				annotations := signed(approved())
+				delete(annotations, kanaryv1alpha1.KanaryStatefulsetApprovalAnnotationKey)
+				return annotations
+			}()),
+			user:        authenticationv1.UserInfo{Username: "alice"},
+			wantAllowed: true,
+			wantPatches: []jsonpatch.JsonPatchOperation{
+				{Operation: "remove", Path: signaturePath},
+				{Operation: "remove", Path: approvedAtPath},
+				{Operation: "remove", Path: approvedByPath},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &ApprovalHandler{key: key, now: func() time.Time { return now }}
+			req := atypes.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+				Operation: admissionv1beta1.Update,
+				Namespace: "bar",
+				Object:    runtime.RawExtension{Raw: mustMarshal(t, tt.kd)},
+				OldObject: runtime.RawExtension{Raw: mustMarshal(t, tt.oldKd)},
+				UserInfo:  tt.user,
+			}}
+			got := h.Handle(context.TODO(), req)
+			if got.Response.Allowed != tt.wantAllowed {
+				t.Fatalf("ApprovalHandler.Handle() allowed = %v, want %v, result: %#v", got.Response.Allowed, tt.wantAllowed, got.Response.Result)
+			}
+			if len(got.Patches) != 0 || len(tt.wantPatches) != 0 {
+				if !reflect.DeepEqual(got.Patches, tt.wantPatches) {
+					t.Errorf("ApprovalHandler.Handle() patches = %#v, want %#v", got.Patches, tt.wantPatches)
+				}
+			}
+		})
+	}
+}
+
+func mustMarshal(t *testing.T, obj interface{}) []byte {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatalf("unable to marshal object: %v", err)
+	}
+	return raw
+}
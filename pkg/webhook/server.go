@@ -0,0 +1,67 @@
+package webhook
+
+import (
+	"context"
+	"crypto/sha256"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/types"
+)
+
+var log = logf.Log.WithName("webhook")
+
+const (
+	// ApprovalPath path of the manual approval admission webhook
+	ApprovalPath = "/mutate-kanarystatefulsets"
+	// DefaultPort default port of the admission webhook server
+	DefaultPort = "9443"
+)
+
+// NewApprovalKey returns the key of the signature of the manual approval decisions, derived from the tls.key file of
+// the certDir directory: the decisions recorded with a previous certificate are not valid anymore.
+func NewApprovalKey(certDir string) ([]byte, error) {
+	tlsKey, err := ioutil.ReadFile(filepath.Join(certDir, "tls.key"))
+	if err != nil {
+		return nil, err
+	}
+	key := sha256.Sum256(tlsKey)
+	return key[:], nil
+}
+
+// NewServer returns a manager.Runnable serving the kanary admission webhooks over TLS.
+// The certDir directory should contain the tls.crt and tls.key files, the key signs the manual approval decisions.
+func NewServer(certDir, port string, key []byte) manager.Runnable {
+	if port == "" {
+		port = DefaultPort
+	}
+	mux := http.NewServeMux()
+	mux.Handle(ApprovalPath, &admission.Webhook{
+		Name:     "approval.kanary.k8s-operators.dev",
+		Type:     types.WebhookTypeMutating,
+		Path:     ApprovalPath,
+		Handlers: []admission.Handler{NewApprovalHandler(key)},
+	})
+	srv := &http.Server{Addr: ":" + port, Handler: mux}
+
+	return manager.RunnableFunc(func(stop <-chan struct{}) error {
+		errChan := make(chan error, 1)
+		go func() {
+			log.Info("Starting the admission webhook server", "port", port)
+			errChan <- srv.ListenAndServeTLS(filepath.Join(certDir, "tls.crt"), filepath.Join(certDir, "tls.key"))
+		}()
+		select {
+		case err := <-errChan:
+			return err
+		case <-stop:
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return srv.Shutdown(ctx)
+		}
+	})
+}